@@ -0,0 +1,250 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	databaseHostKey                  = "database.host"
+	databasePortKey                  = "database.port"
+	databaseNameKey                  = "database.name"
+	databaseUserKey                  = "database.user"
+	databasePasswordKey              = "database.password"
+	databaseSSLModeKey               = "database.sslmode"
+	databaseMaxOpenConnectionsKey    = "database.max_open_connections"
+	databaseMaxIdleConnectionsKey    = "database.max_idle_connections"
+	databaseConnectionMaxLifetimeKey = "database.connection_max_lifetime"
+	databaseStatementTimeoutKey      = "database.statement_timeout"
+
+	redactedPassword = "xxxxx"
+)
+
+// ConnectionConfig describes how to reach the database and how the
+// connection pool should be sized.
+type ConnectionConfig struct {
+	Host     string
+	Port     int
+	Database string
+	User     string
+	Password string
+	// SSLMode is passed to the driver as is, e.g. "disable" or "verify-full".
+	SSLMode string
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	// StatementTimeout aborts any statement running longer than this. Zero
+	// leaves the server default in place.
+	StatementTimeout time.Duration
+	// Options holds any other connection parameters, such as search_path,
+	// sslrootcert or application_name, and is passed to the driver as is.
+	Options map[string]string
+}
+
+var DefaultConnectionConfig = ConnectionConfig{
+	Host:            "localhost",
+	Port:            5432,
+	SSLMode:         "require",
+	MaxOpenConns:    25,
+	MaxIdleConns:    5,
+	ConnMaxLifetime: 30 * time.Minute,
+}
+
+// withDefaults fills zero fields with their default value.
+func (c ConnectionConfig) withDefaults() ConnectionConfig {
+	if c.Host == "" {
+		c.Host = DefaultConnectionConfig.Host
+	}
+	if c.Port == 0 {
+		c.Port = DefaultConnectionConfig.Port
+	}
+	if c.SSLMode == "" {
+		c.SSLMode = DefaultConnectionConfig.SSLMode
+	}
+	if c.MaxOpenConns == 0 {
+		c.MaxOpenConns = DefaultConnectionConfig.MaxOpenConns
+	}
+	if c.MaxIdleConns == 0 {
+		c.MaxIdleConns = DefaultConnectionConfig.MaxIdleConns
+	}
+	if c.ConnMaxLifetime == 0 {
+		c.ConnMaxLifetime = DefaultConnectionConfig.ConnMaxLifetime
+	}
+	return c
+}
+
+func (c ConnectionConfig) url(password *url.Userinfo) *url.URL {
+	q := url.Values{}
+	for k, v := range c.Options {
+		q.Set(k, v)
+	}
+	q.Set("sslmode", c.SSLMode)
+	if c.StatementTimeout > 0 {
+		q.Set("statement_timeout", strconv.FormatInt(int64(c.StatementTimeout/time.Millisecond), 10))
+	}
+	return &url.URL{
+		Scheme:   "postgres",
+		User:     password,
+		Host:     net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+		Path:     "/" + c.Database,
+		RawQuery: q.Encode(),
+	}
+}
+
+func (c ConnectionConfig) userinfo(password string) *url.Userinfo {
+	if c.User == "" {
+		return nil
+	}
+	if c.Password == "" {
+		return url.User(c.User)
+	}
+	return url.UserPassword(c.User, password)
+}
+
+// DSN returns the connection URL to hand to the driver. It contains the
+// password, so it must never be logged; use String for that.
+func (c ConnectionConfig) DSN() string {
+	c = c.withDefaults()
+	return c.url(c.userinfo(c.Password)).String()
+}
+
+// String returns the connection URL with the password masked.
+func (c ConnectionConfig) String() string {
+	c = c.withDefaults()
+	if _, ok := c.Options["password"]; ok {
+		options := make(map[string]string, len(c.Options))
+		for k, v := range c.Options {
+			options[k] = v
+		}
+		options["password"] = redactedPassword
+		c.Options = options
+	}
+	return c.url(c.userinfo(redactedPassword)).String()
+}
+
+var errInvalidConnectionURL = errors.New("invalid database connection url")
+
+// ParseConnectionURL reads a postgres:// URL into a ConnectionConfig. Query
+// parameters other than sslmode and statement_timeout are kept in Options.
+// Pool settings are left zero. Errors never include the URL itself, since it
+// usually holds a password.
+func ParseConnectionURL(raw string) (ConnectionConfig, error) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+		return ConnectionConfig{}, errInvalidConnectionURL
+	}
+
+	cfg := ConnectionConfig{
+		Host:     u.Hostname(),
+		Database: strings.TrimPrefix(u.Path, "/"),
+		SSLMode:  u.Query().Get("sslmode"),
+	}
+	if p := u.Port(); p != "" {
+		if cfg.Port, err = strconv.Atoi(p); err != nil {
+			return ConnectionConfig{}, errInvalidConnectionURL
+		}
+	}
+	if u.User != nil {
+		cfg.User = u.User.Username()
+		cfg.Password, _ = u.User.Password()
+	}
+	if t := u.Query().Get("statement_timeout"); t != "" {
+		ms, err := strconv.ParseInt(t, 10, 64)
+		if err != nil {
+			return ConnectionConfig{}, errInvalidConnectionURL
+		}
+		cfg.StatementTimeout = time.Duration(ms) * time.Millisecond
+	}
+	for k, v := range u.Query() {
+		if k == "sslmode" || k == "statement_timeout" || len(v) == 0 {
+			continue
+		}
+		if cfg.Options == nil {
+			cfg.Options = map[string]string{}
+		}
+		cfg.Options[k] = v[0]
+	}
+	return cfg, nil
+}
+
+// ConnectionConfigFromViper builds a ConnectionConfig from the database.*
+// keys. A URL under the older connection_details key is still honoured and
+// takes the place of the host, port, database, user, password and sslmode
+// keys.
+func ConnectionConfigFromViper(cfg *viper.Viper) (ConnectionConfig, error) {
+	c := ConnectionConfig{
+		Host:     cfg.GetString(databaseHostKey),
+		Port:     cfg.GetInt(databasePortKey),
+		Database: cfg.GetString(databaseNameKey),
+		User:     cfg.GetString(databaseUserKey),
+		Password: cfg.GetString(databasePasswordKey),
+		SSLMode:  cfg.GetString(databaseSSLModeKey),
+	}
+	if raw := cfg.GetString(databaseConnectionKey); raw != "" {
+		var err error
+		if c, err = ParseConnectionURL(raw); err != nil {
+			return ConnectionConfig{}, err
+		}
+	}
+
+	c.MaxOpenConns = cfg.GetInt(databaseMaxOpenConnectionsKey)
+	c.MaxIdleConns = cfg.GetInt(databaseMaxIdleConnectionsKey)
+	c.ConnMaxLifetime = cfg.GetDuration(databaseConnectionMaxLifetimeKey)
+	if t := cfg.GetDuration(databaseStatementTimeoutKey); t != 0 {
+		c.StatementTimeout = t
+	}
+	return c.withDefaults(), nil
+}
+
+var (
+	urlPasswordPattern      = regexp.MustCompile(`(postgres(?:ql)?://[^:/@\s]*:)[^@\s]*@`)
+	keyValuePasswordPattern = regexp.MustCompile(`(password\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+)
+
+// redactDSN masks every password in s, whether it appears in a connection
+// URL or in a space separated key=value connection string.
+func redactDSN(s string) string {
+	s = urlPasswordPattern.ReplaceAllString(s, "${1}"+redactedPassword+"@")
+	return keyValuePasswordPattern.ReplaceAllString(s, "${1}"+redactedPassword)
+}
+
+// redactingLogger masks connection passwords before a message reaches the
+// underlying Logger.
+type redactingLogger struct {
+	Logger
+}
+
+func (l redactingLogger) Printf(format string, v ...interface{}) {
+	l.Logger.Printf("%s", redactDSN(fmt.Sprintf(format, v...)))
+}
+
+// Connect opens a connection pool sized according to cfg and waits for the
+// database to accept connections.
+func (pg *postgres) Connect(cfg ConnectionConfig) (*sql.DB, error) {
+	cfg = cfg.withDefaults()
+	pg.logger.Printf("connecting to postgres database at url: '%s'", cfg)
+
+	db, err := sql.Open("postgres", cfg.DSN())
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	if err = pg.databaseIsAvailable(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
@@ -34,18 +34,20 @@ const discountQueryByCode = `
         sku = $1
 `
 
-func (pg *postgres) GetDiscountByCode(db database.Querier, code string) (*models.Discount, error) {
+func (pg *postgres) GetDiscountByCode(db database.Querier, code string) (result *models.Discount, err error) {
+	defer pg.observe("GetDiscountByCode", time.Now(), &err, &result, code)
 	d := &models.Discount{}
-	err := db.QueryRow(discountQueryByCode, code).Scan(&d.ID, &d.Name, &d.DiscountType, &d.Amount, &d.ExpiresOn, &d.RequiresCode, &d.Code, &d.LimitedUse, &d.NumberOfUses, &d.LoginRequired, &d.StartsOn, &d.CreatedOn, &d.UpdatedOn, &d.ArchivedOn)
+	err = db.QueryRow(discountQueryByCode, code).Scan(&d.ID, &d.Name, &d.DiscountType, &d.Amount, &d.ExpiresOn, &d.RequiresCode, &d.Code, &d.LimitedUse, &d.NumberOfUses, &d.LoginRequired, &d.StartsOn, &d.CreatedOn, &d.UpdatedOn, &d.ArchivedOn)
 	return d, err
 }
 
 const discountExistenceQuery = `SELECT EXISTS(SELECT id FROM discounts WHERE id = $1 and archived_on IS NULL);`
 
-func (pg *postgres) DiscountExists(db database.Querier, id uint64) (bool, error) {
+func (pg *postgres) DiscountExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("DiscountExists", time.Now(), &err, &result, id)
 	var exists string
 
-	err := db.QueryRow(discountExistenceQuery, id).Scan(&exists)
+	err = db.QueryRow(discountExistenceQuery, id).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -79,10 +81,11 @@ const discountSelectionQuery = `
         id = $1
 `
 
-func (pg *postgres) GetDiscount(db database.Querier, id uint64) (*models.Discount, error) {
+func (pg *postgres) GetDiscount(db database.Querier, id uint64) (result *models.Discount, err error) {
+	defer pg.observe("GetDiscount", time.Now(), &err, &result, id)
 	d := &models.Discount{}
 
-	err := db.QueryRow(discountSelectionQuery, id).Scan(&d.ID, &d.Name, &d.DiscountType, &d.Amount, &d.ExpiresOn, &d.RequiresCode, &d.Code, &d.LimitedUse, &d.NumberOfUses, &d.LoginRequired, &d.StartsOn, &d.CreatedOn, &d.UpdatedOn, &d.ArchivedOn)
+	err = db.QueryRow(discountSelectionQuery, id).Scan(&d.ID, &d.Name, &d.DiscountType, &d.Amount, &d.ExpiresOn, &d.RequiresCode, &d.Code, &d.LimitedUse, &d.NumberOfUses, &d.LoginRequired, &d.StartsOn, &d.CreatedOn, &d.UpdatedOn, &d.ArchivedOn)
 
 	return d, err
 }
@@ -112,7 +115,8 @@ func buildDiscountListRetrievalQuery(qf *models.QueryFilter) (string, []interfac
 	return query, args
 }
 
-func (pg *postgres) GetDiscountList(db database.Querier, qf *models.QueryFilter) ([]models.Discount, error) {
+func (pg *postgres) GetDiscountList(db database.Querier, qf *models.QueryFilter) (result []models.Discount, err error) {
+	defer pg.observe("GetDiscountList", time.Now(), &err, &result, qf)
 	var list []models.Discount
 	query, args := buildDiscountListRetrievalQuery(qf)
 
@@ -161,14 +165,16 @@ func buildDiscountCountRetrievalQuery(qf *models.QueryFilter) (string, []interfa
 	return query, args
 }
 
-func (pg *postgres) GetDiscountCount(db database.Querier, qf *models.QueryFilter) (uint64, error) {
+func (pg *postgres) GetDiscountCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetDiscountCount", time.Now(), &err, nil, qf)
 	var count uint64
 	query, args := buildDiscountCountRetrievalQuery(qf)
-	err := db.QueryRow(query, args...).Scan(&count)
+	err = db.QueryRow(query, args...).Scan(&count)
 	return count, err
 }
 
 const discountCreationQuery = `
+    WITH created AS (
     INSERT INTO discounts
         (
             name, discount_type, amount, expires_on, requires_code, code, limited_use, number_of_uses, login_required, starts_on
@@ -178,15 +184,24 @@ const discountCreationQuery = `
             $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
         )
     RETURNING
-        id, created_on;
+        *
+    ), outboxed AS (
+        INSERT INTO outbox_events (event_type, payload)
+        SELECT 'discount_created', to_jsonb(created) FROM created
+    )
+    SELECT
+        id, created_on
+    FROM created;
 `
 
 func (pg *postgres) CreateDiscount(db database.Querier, nu *models.Discount) (createdID uint64, createdOn time.Time, err error) {
+	defer pg.observe("CreateDiscount", time.Now(), &err, &createdID, nu)
 	err = db.QueryRow(discountCreationQuery, &nu.Name, &nu.DiscountType, &nu.Amount, &nu.ExpiresOn, &nu.RequiresCode, &nu.Code, &nu.LimitedUse, &nu.NumberOfUses, &nu.LoginRequired, &nu.StartsOn).Scan(&createdID, &createdOn)
 	return createdID, createdOn, err
 }
 
 const discountUpdateQuery = `
+    WITH updated AS (
     UPDATE discounts
     SET
         name = $1,
@@ -201,23 +216,36 @@ const discountUpdateQuery = `
         starts_on = $10,
         updated_on = NOW()
     WHERE id = $11
-    RETURNING updated_on;
+    RETURNING *
+    ), outboxed AS (
+        INSERT INTO outbox_events (event_type, payload)
+        SELECT 'discount_updated', to_jsonb(updated) FROM updated
+    )
+    SELECT updated_on FROM updated;
 `
 
-func (pg *postgres) UpdateDiscount(db database.Querier, updated *models.Discount) (time.Time, error) {
+func (pg *postgres) UpdateDiscount(db database.Querier, updated *models.Discount) (result time.Time, err error) {
+	defer pg.observe("UpdateDiscount", time.Now(), &err, &result, updated)
 	var t time.Time
-	err := db.QueryRow(discountUpdateQuery, &updated.Name, &updated.DiscountType, &updated.Amount, &updated.ExpiresOn, &updated.RequiresCode, &updated.Code, &updated.LimitedUse, &updated.NumberOfUses, &updated.LoginRequired, &updated.StartsOn, &updated.ID).Scan(&t)
+	err = db.QueryRow(discountUpdateQuery, &updated.Name, &updated.DiscountType, &updated.Amount, &updated.ExpiresOn, &updated.RequiresCode, &updated.Code, &updated.LimitedUse, &updated.NumberOfUses, &updated.LoginRequired, &updated.StartsOn, &updated.ID).Scan(&t)
 	return t, err
 }
 
 const discountDeletionQuery = `
+    WITH archived AS (
     UPDATE discounts
     SET archived_on = NOW()
     WHERE id = $1
-    RETURNING archived_on
+    RETURNING *
+    ), outboxed AS (
+        INSERT INTO outbox_events (event_type, payload)
+        SELECT 'discount_archived', to_jsonb(archived) FROM archived
+    )
+    SELECT archived_on FROM archived
 `
 
 func (pg *postgres) DeleteDiscount(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteDiscount", time.Now(), &err, &t, id)
 	err = db.QueryRow(discountDeletionQuery, id).Scan(&t)
 	return t, err
 }
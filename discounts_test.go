@@ -473,3 +473,16 @@ func TestDeleteDiscountByID(t *testing.T) {
 		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
 	})
 }
+
+func TestDiscountWritesAppendOutboxEvents(t *testing.T) {
+	t.Parallel()
+
+	for query, eventType := range map[string]string{
+		discountCreationQuery: "discount_created",
+		discountUpdateQuery:   "discount_updated",
+		discountDeletionQuery: "discount_archived",
+	} {
+		assert.Contains(t, query, "INSERT INTO outbox_events", "discount writes should append to the outbox in the same statement")
+		assert.Contains(t, query, "'"+eventType+"'", "discount write should record a %s event", eventType)
+	}
+}
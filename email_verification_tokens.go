@@ -0,0 +1,232 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/dairycart/dairycart/storage/database"
+	"github.com/dairycart/dairymodels/v1"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const emailVerificationTokenConsumptionQuery = `
+    WITH consumed AS (
+        UPDATE email_verification_tokens
+        SET verified_on = NOW()
+        FROM users
+        WHERE email_verification_tokens.token_hash = $1
+        AND NOW() < email_verification_tokens.expires_on
+        AND email_verification_tokens.verified_on IS NULL
+        AND email_verification_tokens.invalidated_on IS NULL
+        AND email_verification_tokens.archived_on IS NULL
+        AND users.id = email_verification_tokens.user_id
+        AND lower(users.email) = lower(email_verification_tokens.email)
+        AND users.archived_on IS NULL
+        RETURNING email_verification_tokens.id, email_verification_tokens.user_id, email_verification_tokens.verified_on
+    ), invalidated AS (
+        UPDATE email_verification_tokens
+        SET invalidated_on = NOW()
+        WHERE user_id IN (SELECT user_id FROM consumed)
+        AND id NOT IN (SELECT id FROM consumed)
+        AND verified_on IS NULL
+        AND invalidated_on IS NULL
+    ), verified AS (
+        UPDATE users
+        SET verified_on = consumed.verified_on
+        FROM consumed
+        WHERE users.id = consumed.user_id
+        RETURNING users.id, users.verified_on
+    )
+    SELECT id, verified_on FROM verified
+`
+
+func (pg *postgres) ConsumeEmailVerificationToken(db database.Querier, tokenHash string) (userID uint64, verifiedOn time.Time, err error) {
+	defer pg.observe("ConsumeEmailVerificationToken", time.Now(), &err, &userID, tokenHash)
+	err = db.QueryRow(emailVerificationTokenConsumptionQuery, tokenHash).Scan(&userID, &verifiedOn)
+	return userID, verifiedOn, err
+}
+
+const emailVerificationTokenExpiredDeletionQuery = `DELETE FROM email_verification_tokens WHERE expires_on < NOW()`
+
+func (pg *postgres) DeleteExpiredEmailVerificationTokens(db database.Querier) (result int64, err error) {
+	defer pg.observe("DeleteExpiredEmailVerificationTokens", time.Now(), &err, &result)
+	res, err := db.Exec(emailVerificationTokenExpiredDeletionQuery)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+const emailVerificationTokenExistenceQuery = `SELECT EXISTS(SELECT id FROM email_verification_tokens WHERE id = $1 and archived_on IS NULL);`
+
+func (pg *postgres) EmailVerificationTokenExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("EmailVerificationTokenExists", time.Now(), &err, &result, id)
+	var exists string
+
+	err = db.QueryRow(emailVerificationTokenExistenceQuery, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return exists == "true", err
+}
+
+const emailVerificationTokenSelectionQuery = `
+    SELECT
+        id,
+        user_id,
+        email,
+        token_hash,
+        created_on,
+        expires_on,
+        verified_on,
+        invalidated_on,
+        updated_on,
+        archived_on
+    FROM
+        email_verification_tokens
+    WHERE
+        archived_on is null
+    AND
+        id = $1
+`
+
+func (pg *postgres) GetEmailVerificationToken(db database.Querier, id uint64) (result *models.EmailVerificationToken, err error) {
+	defer pg.observe("GetEmailVerificationToken", time.Now(), &err, &result, id)
+	e := &models.EmailVerificationToken{}
+
+	err = db.QueryRow(emailVerificationTokenSelectionQuery, id).Scan(&e.ID, &e.UserID, &e.Email, &e.TokenHash, &e.CreatedOn, &e.ExpiresOn, &e.VerifiedOn, &e.InvalidatedOn, &e.UpdatedOn, &e.ArchivedOn)
+
+	return e, err
+}
+
+func buildEmailVerificationTokenListRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	sqlBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	queryBuilder := sqlBuilder.
+		Select(
+			"id",
+			"user_id",
+			"email",
+			"token_hash",
+			"created_on",
+			"expires_on",
+			"verified_on",
+			"invalidated_on",
+			"updated_on",
+			"archived_on",
+		).
+		From("email_verification_tokens")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, true).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetEmailVerificationTokenList(db database.Querier, qf *models.QueryFilter) (result []models.EmailVerificationToken, err error) {
+	defer pg.observe("GetEmailVerificationTokenList", time.Now(), &err, &result, qf)
+	var list []models.EmailVerificationToken
+	query, args := buildEmailVerificationTokenListRetrievalQuery(qf)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var e models.EmailVerificationToken
+		err := rows.Scan(
+			&e.ID,
+			&e.UserID,
+			&e.Email,
+			&e.TokenHash,
+			&e.CreatedOn,
+			&e.ExpiresOn,
+			&e.VerifiedOn,
+			&e.InvalidatedOn,
+			&e.UpdatedOn,
+			&e.ArchivedOn,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, e)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+func buildEmailVerificationTokenCountRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("count(id)").
+		From("email_verification_tokens")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, false).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetEmailVerificationTokenCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetEmailVerificationTokenCount", time.Now(), &err, nil, qf)
+	var count uint64
+	query, args := buildEmailVerificationTokenCountRetrievalQuery(qf)
+	err = db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+const emailVerificationTokenCreationQuery = `
+    INSERT INTO email_verification_tokens
+        (
+            user_id, email, token_hash, expires_on, verified_on, invalidated_on
+        )
+    VALUES
+        (
+            $1, $2, $3, $4, $5, $6
+        )
+    RETURNING
+        id, created_on;
+`
+
+func (pg *postgres) CreateEmailVerificationToken(db database.Querier, nu *models.EmailVerificationToken) (createdID uint64, createdOn time.Time, err error) {
+	defer pg.observe("CreateEmailVerificationToken", time.Now(), &err, &createdID, nu)
+	err = db.QueryRow(emailVerificationTokenCreationQuery, &nu.UserID, &nu.Email, &nu.TokenHash, &nu.ExpiresOn, &nu.VerifiedOn, &nu.InvalidatedOn).Scan(&createdID, &createdOn)
+	return createdID, createdOn, err
+}
+
+const emailVerificationTokenUpdateQuery = `
+    UPDATE email_verification_tokens
+    SET
+        user_id = $1,
+        email = $2,
+        token_hash = $3,
+        expires_on = $4,
+        verified_on = $5,
+        invalidated_on = $6,
+        updated_on = NOW()
+    WHERE id = $7
+    RETURNING updated_on;
+`
+
+func (pg *postgres) UpdateEmailVerificationToken(db database.Querier, updated *models.EmailVerificationToken) (result time.Time, err error) {
+	defer pg.observe("UpdateEmailVerificationToken", time.Now(), &err, &result, updated)
+	var t time.Time
+	err = db.QueryRow(emailVerificationTokenUpdateQuery, &updated.UserID, &updated.Email, &updated.TokenHash, &updated.ExpiresOn, &updated.VerifiedOn, &updated.InvalidatedOn, &updated.ID).Scan(&t)
+	return t, err
+}
+
+const emailVerificationTokenDeletionQuery = `
+    UPDATE email_verification_tokens
+    SET archived_on = NOW()
+    WHERE id = $1
+    RETURNING archived_on
+`
+
+func (pg *postgres) DeleteEmailVerificationToken(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteEmailVerificationToken", time.Now(), &err, &t, id)
+	err = db.QueryRow(emailVerificationTokenDeletionQuery, id).Scan(&t)
+	return t, err
+}
@@ -0,0 +1,447 @@
+package postgres
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strconv"
+	"testing"
+
+	// internal dependencies
+	"github.com/dairycart/dairymodels/v1"
+
+	// external dependencies
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func setEmailVerificationTokenConsumptionQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, tokenHash string, userID uint64, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(emailVerificationTokenConsumptionQuery)
+	exampleRows := sqlmock.NewRows([]string{"id", "verified_on"}).AddRow(userID, buildTestTime(t))
+	mock.ExpectQuery(query).WithArgs(tokenHash).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestConsumeEmailVerificationToken(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleTokenHash := "deadbeef"
+	exampleUserID := uint64(1)
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setEmailVerificationTokenConsumptionQueryExpectation(t, mock, exampleTokenHash, exampleUserID, nil)
+		expected := buildTestTime(t)
+		actualUserID, actualVerifiedOn, err := client.ConsumeEmailVerificationToken(mockDB, exampleTokenHash)
+
+		assert.NoError(t, err)
+		assert.Equal(t, exampleUserID, actualUserID, "expected user ID did not match actual user ID")
+		assert.Equal(t, expected, actualVerifiedOn, "expected verification time did not match actual verification time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with an expired or already used token", func(t *testing.T) {
+		setEmailVerificationTokenConsumptionQueryExpectation(t, mock, exampleTokenHash, exampleUserID, sql.ErrNoRows)
+		_, _, err := client.ConsumeEmailVerificationToken(mockDB, exampleTokenHash)
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+func TestDeleteExpiredEmailVerificationTokens(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+	query := formatQueryForSQLMock(emailVerificationTokenExpiredDeletionQuery)
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 3))
+		actual, err := client.DeleteExpiredEmailVerificationTokens(mockDB)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), actual, "expected deleted token count did not match actual count")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with a database error", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnError(errors.New("pineapple on pizza"))
+		actual, err := client.DeleteExpiredEmailVerificationTokens(mockDB)
+
+		assert.NotNil(t, err)
+		assert.Zero(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setEmailVerificationTokenExistenceQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, shouldExist bool, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(emailVerificationTokenExistenceQuery)
+
+	mock.ExpectQuery(query).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(strconv.FormatBool(shouldExist))).
+		WillReturnError(err)
+}
+
+func TestEmailVerificationTokenExists(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	client := NewPostgres()
+
+	t.Run("existing", func(t *testing.T) {
+		setEmailVerificationTokenExistenceQueryExpectation(t, mock, exampleID, true, nil)
+		actual, err := client.EmailVerificationTokenExists(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.True(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with no rows found", func(t *testing.T) {
+		setEmailVerificationTokenExistenceQueryExpectation(t, mock, exampleID, true, sql.ErrNoRows)
+		actual, err := client.EmailVerificationTokenExists(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.False(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with a database error", func(t *testing.T) {
+		setEmailVerificationTokenExistenceQueryExpectation(t, mock, exampleID, true, errors.New("pineapple on pizza"))
+		actual, err := client.EmailVerificationTokenExists(mockDB, exampleID)
+
+		assert.NotNil(t, err)
+		assert.False(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setEmailVerificationTokenReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, toReturn *models.EmailVerificationToken, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(emailVerificationTokenSelectionQuery)
+
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"user_id",
+		"email",
+		"token_hash",
+		"created_on",
+		"expires_on",
+		"verified_on",
+		"invalidated_on",
+		"updated_on",
+		"archived_on",
+	}).AddRow(
+		toReturn.ID,
+		toReturn.UserID,
+		toReturn.Email,
+		toReturn.TokenHash,
+		toReturn.CreatedOn,
+		toReturn.ExpiresOn,
+		toReturn.VerifiedOn,
+		toReturn.InvalidatedOn,
+		toReturn.UpdatedOn,
+		toReturn.ArchivedOn,
+	)
+	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestGetEmailVerificationToken(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	expected := &models.EmailVerificationToken{ID: exampleID}
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setEmailVerificationTokenReadQueryExpectation(t, mock, exampleID, expected, nil)
+		actual, err := client.GetEmailVerificationToken(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected emailverificationtoken did not match actual emailverificationtoken")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setEmailVerificationTokenListReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, qf *models.QueryFilter, example *models.EmailVerificationToken, rowErr error, err error) {
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"user_id",
+		"email",
+		"token_hash",
+		"created_on",
+		"expires_on",
+		"verified_on",
+		"invalidated_on",
+		"updated_on",
+		"archived_on",
+	}).AddRow(
+		example.ID,
+		example.UserID,
+		example.Email,
+		example.TokenHash,
+		example.CreatedOn,
+		example.ExpiresOn,
+		example.VerifiedOn,
+		example.InvalidatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).AddRow(
+		example.ID,
+		example.UserID,
+		example.Email,
+		example.TokenHash,
+		example.CreatedOn,
+		example.ExpiresOn,
+		example.VerifiedOn,
+		example.InvalidatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).AddRow(
+		example.ID,
+		example.UserID,
+		example.Email,
+		example.TokenHash,
+		example.CreatedOn,
+		example.ExpiresOn,
+		example.VerifiedOn,
+		example.InvalidatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).RowError(1, rowErr)
+
+	query, _ := buildEmailVerificationTokenListRetrievalQuery(qf)
+
+	mock.ExpectQuery(formatQueryForSQLMock(query)).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestGetEmailVerificationTokenList(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	example := &models.EmailVerificationToken{ID: exampleID}
+	client := NewPostgres()
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setEmailVerificationTokenListReadQueryExpectation(t, mock, exampleQF, example, nil, nil)
+		actual, err := client.GetEmailVerificationTokenList(mockDB, exampleQF)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, actual, "list retrieval method should not return an empty slice")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error executing query", func(t *testing.T) {
+		setEmailVerificationTokenListReadQueryExpectation(t, mock, exampleQF, example, nil, errors.New("pineapple on pizza"))
+		actual, err := client.GetEmailVerificationTokenList(mockDB, exampleQF)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error scanning values", func(t *testing.T) {
+		exampleRows := sqlmock.NewRows([]string{"things"}).AddRow("stuff")
+		query, _ := buildEmailVerificationTokenListRetrievalQuery(exampleQF)
+		mock.ExpectQuery(formatQueryForSQLMock(query)).
+			WillReturnRows(exampleRows)
+
+		actual, err := client.GetEmailVerificationTokenList(mockDB, exampleQF)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with with row errors", func(t *testing.T) {
+		setEmailVerificationTokenListReadQueryExpectation(t, mock, exampleQF, example, errors.New("pineapple on pizza"), nil)
+		actual, err := client.GetEmailVerificationTokenList(mockDB, exampleQF)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func TestBuildEmailVerificationTokenCountRetrievalQuery(t *testing.T) {
+	t.Parallel()
+
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+	expected := `SELECT count(id) FROM email_verification_tokens WHERE archived_on IS NULL LIMIT 25`
+	actual, _ := buildEmailVerificationTokenCountRetrievalQuery(exampleQF)
+
+	assert.Equal(t, expected, actual, "expected and actual queries should match")
+}
+
+func setEmailVerificationTokenCountRetrievalQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, qf *models.QueryFilter, count uint64, err error) {
+	t.Helper()
+	query, args := buildEmailVerificationTokenCountRetrievalQuery(qf)
+	query = formatQueryForSQLMock(query)
+
+	var argsToExpect []driver.Value
+	for _, x := range args {
+		argsToExpect = append(argsToExpect, x)
+	}
+
+	exampleRow := sqlmock.NewRows([]string{"count"}).AddRow(count)
+	mock.ExpectQuery(query).WithArgs(argsToExpect...).WillReturnRows(exampleRow).WillReturnError(err)
+}
+
+func TestGetEmailVerificationTokenCount(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+	expected := uint64(123)
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setEmailVerificationTokenCountRetrievalQueryExpectation(t, mock, exampleQF, expected, nil)
+		actual, err := client.GetEmailVerificationTokenCount(mockDB, exampleQF)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "count retrieval method should return the expected value")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setEmailVerificationTokenCreationQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toCreate *models.EmailVerificationToken, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(emailVerificationTokenCreationQuery)
+	tt := buildTestTime(t)
+	exampleRows := sqlmock.NewRows([]string{"id", "created_on"}).AddRow(uint64(1), tt)
+	mock.ExpectQuery(query).
+		WithArgs(
+			toCreate.UserID,
+			toCreate.Email,
+			toCreate.TokenHash,
+			toCreate.ExpiresOn,
+			toCreate.VerifiedOn,
+			toCreate.InvalidatedOn,
+		).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestCreateEmailVerificationToken(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	expectedID := uint64(1)
+	exampleInput := &models.EmailVerificationToken{ID: expectedID}
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setEmailVerificationTokenCreationQueryExpectation(t, mock, exampleInput, nil)
+		expectedCreatedOn := buildTestTime(t)
+
+		actualID, actualCreatedOn, err := client.CreateEmailVerificationToken(mockDB, exampleInput)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedID, actualID, "expected and actual IDs don't match")
+		assert.Equal(t, expectedCreatedOn, actualCreatedOn, "expected creation time did not match actual creation time")
+
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setEmailVerificationTokenUpdateQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toUpdate *models.EmailVerificationToken, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(emailVerificationTokenUpdateQuery)
+	exampleRows := sqlmock.NewRows([]string{"updated_on"}).AddRow(buildTestTime(t))
+	mock.ExpectQuery(query).
+		WithArgs(
+			toUpdate.UserID,
+			toUpdate.Email,
+			toUpdate.TokenHash,
+			toUpdate.ExpiresOn,
+			toUpdate.VerifiedOn,
+			toUpdate.InvalidatedOn,
+			toUpdate.ID,
+		).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestUpdateEmailVerificationTokenByID(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleInput := &models.EmailVerificationToken{ID: uint64(1)}
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setEmailVerificationTokenUpdateQueryExpectation(t, mock, exampleInput, nil)
+		expected := buildTestTime(t)
+		actual, err := client.UpdateEmailVerificationToken(mockDB, exampleInput)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected deletion time did not match actual deletion time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setEmailVerificationTokenDeletionQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(emailVerificationTokenDeletionQuery)
+	exampleRows := sqlmock.NewRows([]string{"archived_on"}).AddRow(buildTestTime(t))
+	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestDeleteEmailVerificationTokenByID(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setEmailVerificationTokenDeletionQueryExpectation(t, mock, exampleID, nil)
+		expected := buildTestTime(t)
+		actual, err := client.DeleteEmailVerificationToken(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected deletion time did not match actual deletion time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		setEmailVerificationTokenDeletionQueryExpectation(t, mock, exampleID, nil)
+		expected := buildTestTime(t)
+		tx, err := mockDB.Begin()
+		assert.NoError(t, err, "no error should be returned setting up a transaction in the mock DB")
+		actual, err := client.DeleteEmailVerificationToken(tx, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected deletion time did not match actual deletion time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
@@ -1,7 +1,5 @@
 set -e
 
-(cd migrations && go-bindata -nocompress -pkg migrations .)
-
 gnorm gen # --verbose
 if [ -z "$1" ]; then
     go test github.com/dairycart/postgres -cover
@@ -10,7 +10,7 @@ import (
 )
 
 func formatQueryForSQLMock(query string) string {
-	for _, x := range []string{"$", "(", ")", "=", "*", ".", "+", "?", ",", "-"} {
+	for _, x := range []string{"$", "(", ")", "=", "*", ".", "+", "?", ",", "-", "[", "]", "{", "}", "|", "^"} {
 		query = strings.Replace(query, x, fmt.Sprintf(`\%s`, x), -1)
 	}
 	return query
@@ -11,28 +11,46 @@ import (
 )
 
 const loginAttemptExhaustionQuery = `
-    SELECT count(id) FROM login_attempts
-        WHERE username = $1
-        AND created_on < NOW()
-        AND successful IS false
-        AND created_on > (NOW() - (15 * interval '1 minute'))
+    SELECT
+        (
+            SELECT count(id) FROM login_attempts
+            WHERE username = $1
+            AND successful IS false
+            AND created_on > (NOW() - ($3 * interval '1 second'))
+            AND created_on > COALESCE((SELECT max(created_on) FROM login_attempts WHERE username = $1 AND successful IS true), '-infinity')
+        ),
+        (
+            SELECT count(id) FROM login_attempts
+            WHERE ip_address = CAST(NULLIF($2, '') AS inet)
+            AND successful IS false
+            AND created_on > (NOW() - ($3 * interval '1 second'))
+        ),
+        EXISTS(
+            SELECT id FROM login_lockouts
+            WHERE (username = $1 OR ip_address = CAST(NULLIF($2, '') AS inet))
+            AND locked_until > NOW()
+            AND archived_on IS NULL
+        )
 `
 
-func (pg *postgres) LoginAttemptsHaveBeenExhausted(db database.Querier, username string) (bool, error) {
-	var loginCount uint64
-	err := db.QueryRow(loginAttemptExhaustionQuery, username).Scan(&loginCount)
+func (pg *postgres) LoginAttemptsHaveBeenExhausted(db database.Querier, username string, ipAddress string) (result bool, err error) {
+	defer pg.observe("LoginAttemptsHaveBeenExhausted", time.Now(), &err, &result, username, ipAddress)
+	var usernameFailures, ipAddressFailures uint64
+	var lockedOut bool
+	err = db.QueryRow(loginAttemptExhaustionQuery, username, ipAddress, pg.loginThrottle.FailureWindow.Seconds()).Scan(&usernameFailures, &ipAddressFailures, &lockedOut)
 	if err != nil {
 		return false, err
 	}
-	return loginCount >= 10, err
+	return lockedOut || usernameFailures >= pg.loginThrottle.UsernameFailureLimit || ipAddressFailures >= pg.loginThrottle.IPAddressFailureLimit, err
 }
 
 const loginAttemptExistenceQuery = `SELECT EXISTS(SELECT id FROM login_attempts WHERE id = $1 and archived_on IS NULL);`
 
-func (pg *postgres) LoginAttemptExists(db database.Querier, id uint64) (bool, error) {
+func (pg *postgres) LoginAttemptExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("LoginAttemptExists", time.Now(), &err, &result, id)
 	var exists string
 
-	err := db.QueryRow(loginAttemptExistenceQuery, id).Scan(&exists)
+	err = db.QueryRow(loginAttemptExistenceQuery, id).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -47,7 +65,8 @@ const loginAttemptSelectionQuery = `
         id,
         username,
         successful,
-        created_on
+        created_on,
+        COALESCE(host(ip_address), '') AS ip_address
     FROM
         login_attempts
     WHERE
@@ -56,10 +75,11 @@ const loginAttemptSelectionQuery = `
         id = $1
 `
 
-func (pg *postgres) GetLoginAttempt(db database.Querier, id uint64) (*models.LoginAttempt, error) {
+func (pg *postgres) GetLoginAttempt(db database.Querier, id uint64) (result *models.LoginAttempt, err error) {
+	defer pg.observe("GetLoginAttempt", time.Now(), &err, &result, id)
 	l := &models.LoginAttempt{}
 
-	err := db.QueryRow(loginAttemptSelectionQuery, id).Scan(&l.ID, &l.Username, &l.Successful, &l.CreatedOn)
+	err = db.QueryRow(loginAttemptSelectionQuery, id).Scan(&l.ID, &l.Username, &l.Successful, &l.CreatedOn, &l.IPAddress)
 
 	return l, err
 }
@@ -72,6 +92,7 @@ func buildLoginAttemptListRetrievalQuery(qf *models.QueryFilter) (string, []inte
 			"username",
 			"successful",
 			"created_on",
+			"COALESCE(host(ip_address), '') AS ip_address",
 		).
 		From("login_attempts")
 
@@ -79,7 +100,8 @@ func buildLoginAttemptListRetrievalQuery(qf *models.QueryFilter) (string, []inte
 	return query, args
 }
 
-func (pg *postgres) GetLoginAttemptList(db database.Querier, qf *models.QueryFilter) ([]models.LoginAttempt, error) {
+func (pg *postgres) GetLoginAttemptList(db database.Querier, qf *models.QueryFilter) (result []models.LoginAttempt, err error) {
+	defer pg.observe("GetLoginAttemptList", time.Now(), &err, &result, qf)
 	var list []models.LoginAttempt
 	query, args := buildLoginAttemptListRetrievalQuery(qf)
 
@@ -95,6 +117,7 @@ func (pg *postgres) GetLoginAttemptList(db database.Querier, qf *models.QueryFil
 			&l.Username,
 			&l.Successful,
 			&l.CreatedOn,
+			&l.IPAddress,
 		)
 		if err != nil {
 			return nil, err
@@ -118,28 +141,37 @@ func buildLoginAttemptCountRetrievalQuery(qf *models.QueryFilter) (string, []int
 	return query, args
 }
 
-func (pg *postgres) GetLoginAttemptCount(db database.Querier, qf *models.QueryFilter) (uint64, error) {
+func (pg *postgres) GetLoginAttemptCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetLoginAttemptCount", time.Now(), &err, nil, qf)
 	var count uint64
 	query, args := buildLoginAttemptCountRetrievalQuery(qf)
-	err := db.QueryRow(query, args...).Scan(&count)
+	err = db.QueryRow(query, args...).Scan(&count)
 	return count, err
 }
 
 const loginAttemptCreationQuery = `
+    WITH cleared_lockouts AS (
+        UPDATE login_lockouts
+        SET archived_on = NOW(), updated_on = NOW()
+        WHERE $2 IS true
+        AND username = $1
+        AND archived_on IS NULL
+    )
     INSERT INTO login_attempts
         (
-            username, successful
+            username, successful, ip_address
         )
     VALUES
         (
-            $1, $2
+            $1, $2, CAST(NULLIF($3, '') AS inet)
         )
     RETURNING
         id, created_on;
 `
 
 func (pg *postgres) CreateLoginAttempt(db database.Querier, nu *models.LoginAttempt) (createdID uint64, createdOn time.Time, err error) {
-	err = db.QueryRow(loginAttemptCreationQuery, &nu.Username, &nu.Successful).Scan(&createdID, &createdOn)
+	defer pg.observe("CreateLoginAttempt", time.Now(), &err, &createdID, nu)
+	err = db.QueryRow(loginAttemptCreationQuery, &nu.Username, &nu.Successful, &nu.IPAddress).Scan(&createdID, &createdOn)
 	return createdID, createdOn, err
 }
 
@@ -148,14 +180,16 @@ const loginAttemptUpdateQuery = `
     SET
         username = $1,
         successful = $2,
+        ip_address = CAST(NULLIF($3, '') AS inet),
         updated_on = NOW()
-    WHERE id = $3
+    WHERE id = $4
     RETURNING updated_on;
 `
 
-func (pg *postgres) UpdateLoginAttempt(db database.Querier, updated *models.LoginAttempt) (time.Time, error) {
+func (pg *postgres) UpdateLoginAttempt(db database.Querier, updated *models.LoginAttempt) (result time.Time, err error) {
+	defer pg.observe("UpdateLoginAttempt", time.Now(), &err, &result, updated)
 	var t time.Time
-	err := db.QueryRow(loginAttemptUpdateQuery, &updated.Username, &updated.Successful, &updated.ID).Scan(&t)
+	err = db.QueryRow(loginAttemptUpdateQuery, &updated.Username, &updated.Successful, &updated.IPAddress, &updated.ID).Scan(&t)
 	return t, err
 }
 
@@ -167,6 +201,7 @@ const loginAttemptDeletionQuery = `
 `
 
 func (pg *postgres) DeleteLoginAttempt(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteLoginAttempt", time.Now(), &err, &t, id)
 	err = db.QueryRow(loginAttemptDeletionQuery, id).Scan(&t)
 	return t, err
 }
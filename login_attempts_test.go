@@ -15,21 +15,16 @@ import (
 	"gopkg.in/DATA-DOG/go-sqlmock.v1"
 )
 
-func setExpectationsForLoginAttemptExhaustionQuery(mock sqlmock.Sqlmock, username string, exhausted bool, shouldError bool) {
-	count := 0
-	if exhausted {
-		count = 666
-	}
-
-	var argToReturn interface{} = count
+func setExpectationsForLoginAttemptExhaustionQuery(mock sqlmock.Sqlmock, username string, ipAddress string, usernameFailures uint64, ipAddressFailures uint64, lockedOut bool, shouldError bool) {
+	var argToReturn interface{} = usernameFailures
 	if shouldError {
 		argToReturn = "hello"
 	}
 
-	exampleRows := sqlmock.NewRows([]string{""}).AddRow(argToReturn)
+	exampleRows := sqlmock.NewRows([]string{"", "", ""}).AddRow(argToReturn, ipAddressFailures, lockedOut)
 	query := formatQueryForSQLMock(loginAttemptExhaustionQuery)
 	mock.ExpectQuery(query).
-		WithArgs(username).
+		WithArgs(username, ipAddress, DefaultLoginThrottleConfig.FailureWindow.Seconds()).
 		WillReturnRows(exampleRows)
 }
 
@@ -40,11 +35,39 @@ func TestLoginAttemptsHaveBeenExhausted(t *testing.T) {
 	defer mockDB.Close()
 	client := NewPostgres()
 	exampleUsername := "username"
+	exampleIPAddress := "127.0.0.1"
 
 	t.Run("optimal behavior", func(*testing.T) {
 		expected := false
-		setExpectationsForLoginAttemptExhaustionQuery(mock, exampleUsername, expected, false)
-		actual, err := client.LoginAttemptsHaveBeenExhausted(mockDB, exampleUsername)
+		setExpectationsForLoginAttemptExhaustionQuery(mock, exampleUsername, exampleIPAddress, 1, 1, false, false)
+		actual, err := client.LoginAttemptsHaveBeenExhausted(mockDB, exampleUsername, exampleIPAddress)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("with too many failures for username", func(*testing.T) {
+		expected := true
+		setExpectationsForLoginAttemptExhaustionQuery(mock, exampleUsername, exampleIPAddress, DefaultLoginThrottleConfig.UsernameFailureLimit, 0, false, false)
+		actual, err := client.LoginAttemptsHaveBeenExhausted(mockDB, exampleUsername, exampleIPAddress)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("with too many failures for IP address", func(*testing.T) {
+		expected := true
+		setExpectationsForLoginAttemptExhaustionQuery(mock, exampleUsername, exampleIPAddress, 0, DefaultLoginThrottleConfig.IPAddressFailureLimit, false, false)
+		actual, err := client.LoginAttemptsHaveBeenExhausted(mockDB, exampleUsername, exampleIPAddress)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("with active lockout", func(*testing.T) {
+		expected := true
+		setExpectationsForLoginAttemptExhaustionQuery(mock, exampleUsername, exampleIPAddress, 0, 0, true, false)
+		actual, err := client.LoginAttemptsHaveBeenExhausted(mockDB, exampleUsername, exampleIPAddress)
 
 		assert.NoError(t, err)
 		assert.Equal(t, expected, actual)
@@ -52,14 +75,50 @@ func TestLoginAttemptsHaveBeenExhausted(t *testing.T) {
 
 	t.Run("with db error", func(*testing.T) {
 		expected := false
-		setExpectationsForLoginAttemptExhaustionQuery(mock, exampleUsername, expected, true)
-		actual, err := client.LoginAttemptsHaveBeenExhausted(mockDB, exampleUsername)
+		setExpectationsForLoginAttemptExhaustionQuery(mock, exampleUsername, exampleIPAddress, 0, 0, false, true)
+		actual, err := client.LoginAttemptsHaveBeenExhausted(mockDB, exampleUsername, exampleIPAddress)
 
 		assert.NotNil(t, err)
 		assert.Equal(t, expected, actual)
 	})
 }
 
+func TestLoginAttemptReadsNullIPAddress(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+	exampleQF := &models.QueryFilter{Limit: 25, Page: 1}
+	listQuery, _ := buildLoginAttemptListRetrievalQuery(exampleQF)
+
+	// IPAddress is a plain string, which a NULL ip_address can not be scanned
+	// into, so the read queries have postgres hand back an empty one instead.
+	for _, query := range []string{loginAttemptSelectionQuery, listQuery} {
+		assert.Contains(t, query, "COALESCE(host(ip_address), '') AS ip_address")
+	}
+
+	t.Run("single", func(t *testing.T) {
+		expected := &models.LoginAttempt{ID: 1}
+		setLoginAttemptReadQueryExpectation(t, mock, expected.ID, expected, nil)
+		actual, err := client.GetLoginAttempt(mockDB, expected.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "", actual.IPAddress)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("list", func(t *testing.T) {
+		example := &models.LoginAttempt{ID: 1}
+		setLoginAttemptListReadQueryExpectation(t, mock, exampleQF, example, nil, nil)
+		actual, err := client.GetLoginAttemptList(mockDB, exampleQF)
+
+		assert.NoError(t, err)
+		assert.Len(t, actual, 3)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
 func setLoginAttemptExistenceQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, shouldExist bool, err error) {
 	t.Helper()
 	query := formatQueryForSQLMock(loginAttemptExistenceQuery)
@@ -115,11 +174,13 @@ func setLoginAttemptReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id
 		"username",
 		"successful",
 		"created_on",
+		"ip_address",
 	}).AddRow(
 		toReturn.ID,
 		toReturn.Username,
 		toReturn.Successful,
 		toReturn.CreatedOn,
+		toReturn.IPAddress,
 	)
 	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
 }
@@ -149,21 +210,25 @@ func setLoginAttemptListReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock,
 		"username",
 		"successful",
 		"created_on",
+		"ip_address",
 	}).AddRow(
 		example.ID,
 		example.Username,
 		example.Successful,
 		example.CreatedOn,
+		example.IPAddress,
 	).AddRow(
 		example.ID,
 		example.Username,
 		example.Successful,
 		example.CreatedOn,
+		example.IPAddress,
 	).AddRow(
 		example.ID,
 		example.Username,
 		example.Successful,
 		example.CreatedOn,
+		example.IPAddress,
 	).RowError(1, rowErr)
 
 	query, _ := buildLoginAttemptListRetrievalQuery(qf)
@@ -285,6 +350,7 @@ func setLoginAttemptCreationQueryExpectation(t *testing.T, mock sqlmock.Sqlmock,
 		WithArgs(
 			toCreate.Username,
 			toCreate.Successful,
+			toCreate.IPAddress,
 		).
 		WillReturnRows(exampleRows).
 		WillReturnError(err)
@@ -321,6 +387,7 @@ func setLoginAttemptUpdateQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, t
 		WithArgs(
 			toUpdate.Username,
 			toUpdate.Successful,
+			toUpdate.IPAddress,
 			toUpdate.ID,
 		).
 		WillReturnRows(exampleRows).
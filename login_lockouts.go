@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/dairycart/dairycart/storage/database"
+	"github.com/dairycart/dairymodels/v1"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const loginLockoutSetQuery = `
+    INSERT INTO login_lockouts
+        (
+            username, ip_address, locked_until
+        )
+    VALUES
+        (
+            NULLIF($1, ''), CAST(NULLIF($2, '') AS inet), NOW() + ($3 * interval '1 second')
+        )
+    RETURNING
+        locked_until;
+`
+
+func (pg *postgres) SetLoginLockout(db database.Querier, username string, ipAddress string) (lockedUntil time.Time, err error) {
+	defer pg.observe("SetLoginLockout", time.Now(), &err, &lockedUntil, username, ipAddress)
+	err = db.QueryRow(loginLockoutSetQuery, username, ipAddress, pg.loginThrottle.LockoutDuration.Seconds()).Scan(&lockedUntil)
+	return lockedUntil, err
+}
+
+const loginLockoutExistenceQuery = `SELECT EXISTS(SELECT id FROM login_lockouts WHERE id = $1 and archived_on IS NULL);`
+
+func (pg *postgres) LoginLockoutExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("LoginLockoutExists", time.Now(), &err, &result, id)
+	var exists string
+
+	err = db.QueryRow(loginLockoutExistenceQuery, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return exists == "true", err
+}
+
+const loginLockoutSelectionQuery = `
+    SELECT
+        id,
+        username,
+        ip_address,
+        locked_until,
+        created_on,
+        updated_on,
+        archived_on
+    FROM
+        login_lockouts
+    WHERE
+        archived_on is null
+    AND
+        id = $1
+`
+
+func (pg *postgres) GetLoginLockout(db database.Querier, id uint64) (result *models.LoginLockout, err error) {
+	defer pg.observe("GetLoginLockout", time.Now(), &err, &result, id)
+	l := &models.LoginLockout{}
+
+	err = db.QueryRow(loginLockoutSelectionQuery, id).Scan(&l.ID, &l.Username, &l.IPAddress, &l.LockedUntil, &l.CreatedOn, &l.UpdatedOn, &l.ArchivedOn)
+
+	return l, err
+}
+
+func buildLoginLockoutListRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	sqlBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	queryBuilder := sqlBuilder.
+		Select(
+			"id",
+			"username",
+			"ip_address",
+			"locked_until",
+			"created_on",
+			"updated_on",
+			"archived_on",
+		).
+		From("login_lockouts")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, true).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetLoginLockoutList(db database.Querier, qf *models.QueryFilter) (result []models.LoginLockout, err error) {
+	defer pg.observe("GetLoginLockoutList", time.Now(), &err, &result, qf)
+	var list []models.LoginLockout
+	query, args := buildLoginLockoutListRetrievalQuery(qf)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var l models.LoginLockout
+		err := rows.Scan(
+			&l.ID,
+			&l.Username,
+			&l.IPAddress,
+			&l.LockedUntil,
+			&l.CreatedOn,
+			&l.UpdatedOn,
+			&l.ArchivedOn,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, l)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+func buildLoginLockoutCountRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("count(id)").
+		From("login_lockouts")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, false).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetLoginLockoutCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetLoginLockoutCount", time.Now(), &err, nil, qf)
+	var count uint64
+	query, args := buildLoginLockoutCountRetrievalQuery(qf)
+	err = db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+const loginLockoutCreationQuery = `
+    INSERT INTO login_lockouts
+        (
+            username, ip_address, locked_until
+        )
+    VALUES
+        (
+            $1, CAST(NULLIF($2, '') AS inet), $3
+        )
+    RETURNING
+        id, created_on;
+`
+
+func (pg *postgres) CreateLoginLockout(db database.Querier, nu *models.LoginLockout) (createdID uint64, createdOn time.Time, err error) {
+	defer pg.observe("CreateLoginLockout", time.Now(), &err, &createdID, nu)
+	err = db.QueryRow(loginLockoutCreationQuery, &nu.Username, &nu.IPAddress, &nu.LockedUntil).Scan(&createdID, &createdOn)
+	return createdID, createdOn, err
+}
+
+const loginLockoutUpdateQuery = `
+    UPDATE login_lockouts
+    SET
+        username = $1,
+        ip_address = CAST(NULLIF($2, '') AS inet),
+        locked_until = $3,
+        updated_on = NOW()
+    WHERE id = $4
+    RETURNING updated_on;
+`
+
+func (pg *postgres) UpdateLoginLockout(db database.Querier, updated *models.LoginLockout) (result time.Time, err error) {
+	defer pg.observe("UpdateLoginLockout", time.Now(), &err, &result, updated)
+	var t time.Time
+	err = db.QueryRow(loginLockoutUpdateQuery, &updated.Username, &updated.IPAddress, &updated.LockedUntil, &updated.ID).Scan(&t)
+	return t, err
+}
+
+const loginLockoutDeletionQuery = `
+    UPDATE login_lockouts
+    SET archived_on = NOW()
+    WHERE id = $1
+    RETURNING archived_on
+`
+
+func (pg *postgres) DeleteLoginLockout(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteLoginLockout", time.Now(), &err, &t, id)
+	err = db.QueryRow(loginLockoutDeletionQuery, id).Scan(&t)
+	return t, err
+}
@@ -0,0 +1,399 @@
+package postgres
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strconv"
+	"testing"
+
+	// internal dependencies
+	"github.com/dairycart/dairymodels/v1"
+
+	// external dependencies
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func setLoginLockoutSetQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, username string, ipAddress string, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(loginLockoutSetQuery)
+	exampleRows := sqlmock.NewRows([]string{"locked_until"}).AddRow(buildTestTime(t))
+	mock.ExpectQuery(query).
+		WithArgs(username, ipAddress, DefaultLoginThrottleConfig.LockoutDuration.Seconds()).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestSetLoginLockout(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+	exampleUsername := "username"
+	exampleIPAddress := "127.0.0.1"
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setLoginLockoutSetQueryExpectation(t, mock, exampleUsername, exampleIPAddress, nil)
+		expected := buildTestTime(t)
+		actual, err := client.SetLoginLockout(mockDB, exampleUsername, exampleIPAddress)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected lockout time did not match actual lockout time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with a database error", func(t *testing.T) {
+		setLoginLockoutSetQueryExpectation(t, mock, exampleUsername, exampleIPAddress, errors.New("pineapple on pizza"))
+		_, err := client.SetLoginLockout(mockDB, exampleUsername, exampleIPAddress)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setLoginLockoutExistenceQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, shouldExist bool, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(loginLockoutExistenceQuery)
+
+	mock.ExpectQuery(query).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(strconv.FormatBool(shouldExist))).
+		WillReturnError(err)
+}
+
+func TestLoginLockoutExists(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	client := NewPostgres()
+
+	t.Run("existing", func(t *testing.T) {
+		setLoginLockoutExistenceQueryExpectation(t, mock, exampleID, true, nil)
+		actual, err := client.LoginLockoutExists(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.True(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with no rows found", func(t *testing.T) {
+		setLoginLockoutExistenceQueryExpectation(t, mock, exampleID, true, sql.ErrNoRows)
+		actual, err := client.LoginLockoutExists(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.False(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with a database error", func(t *testing.T) {
+		setLoginLockoutExistenceQueryExpectation(t, mock, exampleID, true, errors.New("pineapple on pizza"))
+		actual, err := client.LoginLockoutExists(mockDB, exampleID)
+
+		assert.NotNil(t, err)
+		assert.False(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setLoginLockoutReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, toReturn *models.LoginLockout, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(loginLockoutSelectionQuery)
+
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"username",
+		"ip_address",
+		"locked_until",
+		"created_on",
+		"updated_on",
+		"archived_on",
+	}).AddRow(
+		toReturn.ID,
+		toReturn.Username,
+		toReturn.IPAddress,
+		toReturn.LockedUntil,
+		toReturn.CreatedOn,
+		toReturn.UpdatedOn,
+		toReturn.ArchivedOn,
+	)
+	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestGetLoginLockout(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	expected := &models.LoginLockout{ID: exampleID}
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setLoginLockoutReadQueryExpectation(t, mock, exampleID, expected, nil)
+		actual, err := client.GetLoginLockout(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected loginlockout did not match actual loginlockout")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setLoginLockoutListReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, qf *models.QueryFilter, example *models.LoginLockout, rowErr error, err error) {
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"username",
+		"ip_address",
+		"locked_until",
+		"created_on",
+		"updated_on",
+		"archived_on",
+	}).AddRow(
+		example.ID,
+		example.Username,
+		example.IPAddress,
+		example.LockedUntil,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).AddRow(
+		example.ID,
+		example.Username,
+		example.IPAddress,
+		example.LockedUntil,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).AddRow(
+		example.ID,
+		example.Username,
+		example.IPAddress,
+		example.LockedUntil,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).RowError(1, rowErr)
+
+	query, _ := buildLoginLockoutListRetrievalQuery(qf)
+
+	mock.ExpectQuery(formatQueryForSQLMock(query)).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestGetLoginLockoutList(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	example := &models.LoginLockout{ID: exampleID}
+	client := NewPostgres()
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setLoginLockoutListReadQueryExpectation(t, mock, exampleQF, example, nil, nil)
+		actual, err := client.GetLoginLockoutList(mockDB, exampleQF)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, actual, "list retrieval method should not return an empty slice")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error executing query", func(t *testing.T) {
+		setLoginLockoutListReadQueryExpectation(t, mock, exampleQF, example, nil, errors.New("pineapple on pizza"))
+		actual, err := client.GetLoginLockoutList(mockDB, exampleQF)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error scanning values", func(t *testing.T) {
+		exampleRows := sqlmock.NewRows([]string{"things"}).AddRow("stuff")
+		query, _ := buildLoginLockoutListRetrievalQuery(exampleQF)
+		mock.ExpectQuery(formatQueryForSQLMock(query)).
+			WillReturnRows(exampleRows)
+
+		actual, err := client.GetLoginLockoutList(mockDB, exampleQF)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with with row errors", func(t *testing.T) {
+		setLoginLockoutListReadQueryExpectation(t, mock, exampleQF, example, errors.New("pineapple on pizza"), nil)
+		actual, err := client.GetLoginLockoutList(mockDB, exampleQF)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func TestBuildLoginLockoutCountRetrievalQuery(t *testing.T) {
+	t.Parallel()
+
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+	expected := `SELECT count(id) FROM login_lockouts WHERE archived_on IS NULL LIMIT 25`
+	actual, _ := buildLoginLockoutCountRetrievalQuery(exampleQF)
+
+	assert.Equal(t, expected, actual, "expected and actual queries should match")
+}
+
+func setLoginLockoutCountRetrievalQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, qf *models.QueryFilter, count uint64, err error) {
+	t.Helper()
+	query, args := buildLoginLockoutCountRetrievalQuery(qf)
+	query = formatQueryForSQLMock(query)
+
+	var argsToExpect []driver.Value
+	for _, x := range args {
+		argsToExpect = append(argsToExpect, x)
+	}
+
+	exampleRow := sqlmock.NewRows([]string{"count"}).AddRow(count)
+	mock.ExpectQuery(query).WithArgs(argsToExpect...).WillReturnRows(exampleRow).WillReturnError(err)
+}
+
+func TestGetLoginLockoutCount(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+	expected := uint64(123)
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setLoginLockoutCountRetrievalQueryExpectation(t, mock, exampleQF, expected, nil)
+		actual, err := client.GetLoginLockoutCount(mockDB, exampleQF)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "count retrieval method should return the expected value")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setLoginLockoutCreationQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toCreate *models.LoginLockout, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(loginLockoutCreationQuery)
+	tt := buildTestTime(t)
+	exampleRows := sqlmock.NewRows([]string{"id", "created_on"}).AddRow(uint64(1), tt)
+	mock.ExpectQuery(query).
+		WithArgs(
+			toCreate.Username,
+			toCreate.IPAddress,
+			toCreate.LockedUntil,
+		).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestCreateLoginLockout(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	expectedID := uint64(1)
+	exampleInput := &models.LoginLockout{ID: expectedID}
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setLoginLockoutCreationQueryExpectation(t, mock, exampleInput, nil)
+		expectedCreatedOn := buildTestTime(t)
+
+		actualID, actualCreatedOn, err := client.CreateLoginLockout(mockDB, exampleInput)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedID, actualID, "expected and actual IDs don't match")
+		assert.Equal(t, expectedCreatedOn, actualCreatedOn, "expected creation time did not match actual creation time")
+
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setLoginLockoutUpdateQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toUpdate *models.LoginLockout, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(loginLockoutUpdateQuery)
+	exampleRows := sqlmock.NewRows([]string{"updated_on"}).AddRow(buildTestTime(t))
+	mock.ExpectQuery(query).
+		WithArgs(
+			toUpdate.Username,
+			toUpdate.IPAddress,
+			toUpdate.LockedUntil,
+			toUpdate.ID,
+		).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestUpdateLoginLockoutByID(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleInput := &models.LoginLockout{ID: uint64(1)}
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setLoginLockoutUpdateQueryExpectation(t, mock, exampleInput, nil)
+		expected := buildTestTime(t)
+		actual, err := client.UpdateLoginLockout(mockDB, exampleInput)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected deletion time did not match actual deletion time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setLoginLockoutDeletionQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(loginLockoutDeletionQuery)
+	exampleRows := sqlmock.NewRows([]string{"archived_on"}).AddRow(buildTestTime(t))
+	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestDeleteLoginLockoutByID(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setLoginLockoutDeletionQueryExpectation(t, mock, exampleID, nil)
+		expected := buildTestTime(t)
+		actual, err := client.DeleteLoginLockout(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected deletion time did not match actual deletion time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		setLoginLockoutDeletionQueryExpectation(t, mock, exampleID, nil)
+		expected := buildTestTime(t)
+		tx, err := mockDB.Begin()
+		assert.NoError(t, err, "no error should be returned setting up a transaction in the mock DB")
+		actual, err := client.DeleteLoginLockout(tx, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected deletion time did not match actual deletion time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
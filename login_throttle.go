@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"time"
+)
+
+// LoginThrottleConfig controls how many failed logins are tolerated, per
+// username and per IP address, before further attempts are refused.
+type LoginThrottleConfig struct {
+	UsernameFailureLimit  uint64
+	IPAddressFailureLimit uint64
+	FailureWindow         time.Duration
+	LockoutDuration       time.Duration
+}
+
+var DefaultLoginThrottleConfig = LoginThrottleConfig{
+	UsernameFailureLimit:  10,
+	IPAddressFailureLimit: 50,
+	FailureWindow:         15 * time.Minute,
+	LockoutDuration:       15 * time.Minute,
+}
+
+// SetLoginThrottleConfig replaces the login throttling thresholds. Zero fields
+// keep their default value.
+func (pg *postgres) SetLoginThrottleConfig(cfg LoginThrottleConfig) {
+	if cfg.UsernameFailureLimit == 0 {
+		cfg.UsernameFailureLimit = DefaultLoginThrottleConfig.UsernameFailureLimit
+	}
+	if cfg.IPAddressFailureLimit == 0 {
+		cfg.IPAddressFailureLimit = DefaultLoginThrottleConfig.IPAddressFailureLimit
+	}
+	if cfg.FailureWindow == 0 {
+		cfg.FailureWindow = DefaultLoginThrottleConfig.FailureWindow
+	}
+	if cfg.LockoutDuration == 0 {
+		cfg.LockoutDuration = DefaultLoginThrottleConfig.LockoutDuration
+	}
+	pg.loginThrottle = cfg
+}
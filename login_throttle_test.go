@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetLoginThrottleConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("normal usecase", func(*testing.T) {
+		client := NewPostgres()
+		expected := LoginThrottleConfig{
+			UsernameFailureLimit:  3,
+			IPAddressFailureLimit: 20,
+			FailureWindow:         time.Minute,
+			LockoutDuration:       time.Hour,
+		}
+		client.SetLoginThrottleConfig(expected)
+		assert.Equal(t, expected, client.loginThrottle)
+	})
+
+	t.Run("with zero values", func(*testing.T) {
+		client := NewPostgres()
+		client.SetLoginThrottleConfig(LoginThrottleConfig{UsernameFailureLimit: 3})
+
+		expected := DefaultLoginThrottleConfig
+		expected.UsernameFailureLimit = 3
+		assert.Equal(t, expected, client.loginThrottle)
+	})
+}
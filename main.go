@@ -1,6 +1,11 @@
 package postgres
 
 import (
+	"io"
+	"os"
+	"sync"
+	"time"
+
 	"github.com/dairycart/dairycart/storage/database"
 	"github.com/dairycart/dairymodels/v1"
 
@@ -11,12 +16,36 @@ import (
 
 var _ database.Storer = (*postgres)(nil)
 
-type postgres struct{}
+type postgres struct {
+	loginThrottle   LoginThrottleConfig
+	outboxRetry     OutboxRetryConfig
+	webhookLogs     WebhookLogConfig
+	webhookFailures WebhookFailureConfig
+	migrationOutput io.Writer
+	migration       MigrationConfig
+	logger          Logger
+	sleep           func(time.Duration)
+	observers       []QueryObserver
+	slowQueryLog    SlowQueryLogConfig
+
+	statementCachesMu sync.Mutex
+	statementCaches   []*PreparedStatementCache
+}
 
-var Postgres = &postgres{}
+var Postgres = NewPostgres()
 
 func NewPostgres() *postgres {
-	return &postgres{}
+	return &postgres{
+		loginThrottle:   DefaultLoginThrottleConfig,
+		outboxRetry:     DefaultOutboxRetryConfig,
+		webhookLogs:     DefaultWebhookLogConfig,
+		webhookFailures: DefaultWebhookFailureConfig,
+		migrationOutput: os.Stdout,
+		migration:       DefaultMigrationConfig,
+		logger:          defaultLogger,
+		sleep:           time.Sleep,
+		slowQueryLog:    DefaultSlowQueryLogConfig,
+	}
 }
 
 func applyQueryFilterToQueryBuilder(queryBuilder squirrel.SelectBuilder, qf *models.QueryFilter, includeOffset bool) squirrel.SelectBuilder {
@@ -2,104 +2,479 @@ package postgres
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"io/fs"
+	"sort"
+	"strconv"
 	"strings"
-	"time"
 
 	"github.com/dairycart/postgres/migrations"
 
-	"github.com/mattes/migrate"
-	_ "github.com/mattes/migrate/database/postgres"
-	"github.com/mattes/migrate/source/go-bindata"
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/spf13/viper"
 )
 
 const (
-	maxConnectionAttempts = 5
-
 	migrateExampleDataKey = "migrate_example_data"
+	migrateDryRunKey      = "migrate_dry_run"
 	databaseConnectionKey = "connection_details"
+
+	exampleDataMigrationName = "example_data"
 )
 
-func loadMigrationData(dbURL string, loadExampleData bool) (*migrate.Migrate, error) {
-	s := bindata.Resource(migrations.AssetNames(), func(name string) ([]byte, error) {
-		if strings.Contains(name, "example_data") && loadExampleData {
-			return migrations.Asset(name)
-		}
-		return migrations.Asset(name)
-	})
-	d, err := bindata.WithInstance(s)
+// The example data migration used to be numbered 9999999999 so that it always
+// ran last. Schema migrations added after a database was seeded then sorted
+// below it and were never applied, so it now sits right after the schema it
+// was written against and later migrations run on top of it like they would
+// on real data. Databases seeded before the renumbering are still at
+// legacyExampleDataVersion with exactly that schema, and are moved to
+// exampleDataVersion before migrating.
+const (
+	exampleDataVersion       uint = 1512371454
+	legacyExampleDataVersion uint = 9999999999
+)
+
+// MigrationState describes where a database stands relative to the embedded
+// migrations.
+type MigrationState struct {
+	// Version is the last applied migration, or zero if none has been applied.
+	Version uint
+	// Dirty means the last migration failed partway through and the schema
+	// needs manual repair followed by ForceVersion.
+	Dirty bool
+	// Pending lists the versions that Migrate would apply, in order.
+	Pending []uint
+}
+
+// PlannedMigration is a single migration file that a migration run would
+// execute.
+type PlannedMigration struct {
+	Version uint
+	Name    string
+	Up      bool
+	SQL     string
+}
+
+type migrationFile struct {
+	version  uint
+	name     string
+	up       bool
+	filename string
+}
+
+// migrationFS exposes the embedded migrations, hiding the example data unless
+// it was asked for.
+type migrationFS struct {
+	loadExampleData bool
+}
+
+func (m migrationFS) hidden(name string) bool {
+	return !m.loadExampleData && strings.Contains(name, exampleDataMigrationName)
+}
+
+func (m migrationFS) Open(name string) (fs.File, error) {
+	if m.hidden(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return migrations.FS.Open(name)
+}
+
+func (m migrationFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(migrations.FS, name)
 	if err != nil {
 		return nil, err
 	}
+	var visible []fs.DirEntry
+	for _, e := range entries {
+		if !m.hidden(e.Name()) {
+			visible = append(visible, e)
+		}
+	}
+	return visible, nil
+}
 
-	return migrate.NewWithSourceInstance("go-bindata", d, dbURL)
+func migrationFileNames(loadExampleData bool) []string {
+	entries, _ := migrationFS{loadExampleData: loadExampleData}.ReadDir(".")
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
 }
 
-func prepareForMigration(db *sql.DB, dbURL string, loadExampleData bool) (*migrate.Migrate, error) {
-	log.Printf("preparing to migrate postgres database at url: '%s'\n", dbURL)
-	err := databaseIsAvailable(db)
+func parseMigrationFileName(name string) (migrationFile, bool) {
+	parts := strings.SplitN(name, "_", 2)
+	if len(parts) != 2 {
+		return migrationFile{}, false
+	}
+	version, err := strconv.ParseUint(parts[0], 10, 64)
 	if err != nil {
-		return nil, err
+		return migrationFile{}, false
 	}
 
-	m, err := loadMigrationData(dbURL, loadExampleData)
-	if err != nil {
-		return nil, err
+	f := migrationFile{version: uint(version), filename: name}
+	switch {
+	case strings.HasSuffix(parts[1], ".up.sql"):
+		f.up = true
+		f.name = strings.TrimSuffix(parts[1], ".up.sql")
+	case strings.HasSuffix(parts[1], ".down.sql"):
+		f.name = strings.TrimSuffix(parts[1], ".down.sql")
+	default:
+		return migrationFile{}, false
 	}
+	return f, true
+}
 
-	return m, nil
+// migrationFiles returns the up and down files of every embedded migration,
+// keyed by version, along with the versions in ascending order.
+func migrationFiles(loadExampleData bool) (ups map[uint]migrationFile, downs map[uint]migrationFile, versions []uint) {
+	ups, downs = map[uint]migrationFile{}, map[uint]migrationFile{}
+	for _, name := range migrationFileNames(loadExampleData) {
+		f, ok := parseMigrationFileName(name)
+		if !ok {
+			continue
+		}
+		if f.up {
+			ups[f.version] = f
+			versions = append(versions, f.version)
+		} else {
+			downs[f.version] = f
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return ups, downs, versions
+}
+
+func indexOfVersion(versions []uint, version uint) int {
+	for i, v := range versions {
+		if v == version {
+			return i
+		}
+	}
+	return -1
 }
 
-func databaseIsAvailable(db *sql.DB) error {
-	numberOfUnsuccessfulAttempts := 0
-	databaseIsNotMigrated := true
-	for databaseIsNotMigrated {
-		err := db.Ping()
+func buildMigrationPlan(files map[uint]migrationFile, versions []uint) ([]PlannedMigration, error) {
+	var plan []PlannedMigration
+	for _, v := range versions {
+		f, ok := files[v]
+		if !ok {
+			return nil, fmt.Errorf("migration %d has no matching file", v)
+		}
+		body, err := fs.ReadFile(migrations.FS, f.filename)
 		if err != nil {
-			log.Printf("ping failed, waiting half a second for the database")
-			time.Sleep(500 * time.Millisecond)
-			numberOfUnsuccessfulAttempts++
+			return nil, err
+		}
+		plan = append(plan, PlannedMigration{Version: f.version, Name: f.name, Up: f.up, SQL: string(body)})
+	}
+	return plan, nil
+}
+
+// planMigrationTo lists the migrations needed to move from the current version
+// to the target version. A target of zero rolls every migration back.
+func planMigrationTo(current uint, target uint, loadExampleData bool) ([]PlannedMigration, error) {
+	ups, downs, versions := migrationFiles(loadExampleData)
+	if target != 0 && indexOfVersion(versions, target) == -1 {
+		return nil, fmt.Errorf("unknown migration version %d", target)
+	}
 
-			if numberOfUnsuccessfulAttempts == maxConnectionAttempts {
-				return fmt.Errorf("failed to connect to the database: %v\n", err)
+	var todo []uint
+	if target >= current {
+		for _, v := range versions {
+			if v > current && v <= target {
+				todo = append(todo, v)
 			}
-		} else {
-			break
+		}
+		return buildMigrationPlan(ups, todo)
+	}
+
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i] <= current && versions[i] > target {
+			todo = append(todo, versions[i])
 		}
 	}
-	return nil
+	return buildMigrationPlan(downs, todo)
 }
 
-func (pg *postgres) Migrate(db *sql.DB, cfg *viper.Viper) error {
-	dbURL := cfg.GetString(databaseConnectionKey)
-	loadExampleData := cfg.GetBool(migrateExampleDataKey)
+// planMigrationSteps lists the migrations that moving n steps from the current
+// version would run. Positive n migrates up, negative n rolls back.
+func planMigrationSteps(current uint, n int, loadExampleData bool) ([]PlannedMigration, error) {
+	_, _, versions := migrationFiles(loadExampleData)
+	position := -1
+	if current != 0 {
+		if position = indexOfVersion(versions, current); position == -1 {
+			return nil, fmt.Errorf("unknown migration version %d", current)
+		}
+	}
+
+	targetPosition := position + n
+	if targetPosition >= len(versions) || targetPosition < -1 {
+		return nil, fmt.Errorf("cannot move %d steps from version %d", n, current)
+	}
+
+	var target uint
+	if targetPosition >= 0 {
+		target = versions[targetPosition]
+	}
+	return planMigrationTo(current, target, loadExampleData)
+}
 
-	m, err := prepareForMigration(db, dbURL, loadExampleData)
+func writeMigrationPlan(w io.Writer, plan []PlannedMigration) {
+	if len(plan) == 0 {
+		fmt.Fprintln(w, "-- no migrations to run")
+		return
+	}
+	for _, p := range plan {
+		direction := "down"
+		if p.Up {
+			direction = "up"
+		}
+		fmt.Fprintf(w, "-- %d_%s.%s.sql\n%s\n\n", p.Version, p.Name, direction, strings.TrimSpace(p.SQL))
+	}
+}
+
+func loadMigrationData(dbURL string, loadExampleData bool) (*migrate.Migrate, error) {
+	d, err := iofs.New(migrationFS{loadExampleData: loadExampleData}, ".")
 	if err != nil {
+		return nil, err
+	}
+
+	return migrate.NewWithSourceInstance("iofs", d, dbURL)
+}
+
+func (pg *postgres) prepareForMigration(db *sql.DB, cfg *viper.Viper, loadExampleData bool) (*migrate.Migrate, error) {
+	conn, err := ConnectionConfigFromViper(cfg)
+	if err != nil {
+		return nil, err
+	}
+	pg.logger.Printf("preparing to migrate postgres database at url: '%s'\n", conn)
+	if err = pg.databaseIsAvailable(db); err != nil {
+		return nil, err
+	}
+
+	m, err := loadMigrationData(conn.DSN(), loadExampleData)
+	if err != nil {
+		// driver errors can echo the connection URL back
+		return nil, errors.New(redactDSN(err.Error()))
+	}
+	m.Log = migrateLogger{pg.logger}
+	m.LockTimeout = pg.migration.LockTimeout
+
+	return m, nil
+}
+
+func currentMigrationVersion(m *migrate.Migrate) (version uint, dirty bool, err error) {
+	version, dirty, err = m.Version()
+	if err == migrate.ErrNilVersion {
+		return 0, false, nil
+	}
+	if version == legacyExampleDataVersion {
+		version = exampleDataVersion
+	}
+	return version, dirty, err
+}
+
+// renumberLegacyExampleData moves a database seeded before the example data
+// migration was renumbered onto its new version, so that golang-migrate can
+// find it in the source and apply the schema migrations that follow it.
+func (pg *postgres) renumberLegacyExampleData(m *migrate.Migrate) error {
+	version, dirty, err := m.Version()
+	if err == migrate.ErrNilVersion {
+		return nil
+	}
+	if err != nil || dirty || version != legacyExampleDataVersion {
 		return err
 	}
+	pg.logger.Printf("moving example data from migration version %d to %d", legacyExampleDataVersion, exampleDataVersion)
+	return m.Force(int(exampleDataVersion))
+}
+
+// closeMigration releases the migration source and database connection held
+// by m, reporting a failure to do so unless err already holds an error.
+func closeMigration(m *migrate.Migrate, err *error) {
+	sourceErr, databaseErr := m.Close()
+	if *err != nil {
+		return
+	}
+	if sourceErr != nil {
+		*err = sourceErr
+	} else if databaseErr != nil {
+		*err = errors.New(redactDSN(databaseErr.Error()))
+	}
+}
 
-	err = m.Up()
+func ignoreNoChange(err error) error {
 	if err == migrate.ErrNoChange {
 		return nil
 	}
 	return err
 }
 
-func (pg *postgres) Downgrade(db *sql.DB, cfg *viper.Viper) error {
-	dbURL := cfg.GetString(databaseConnectionKey)
+// versionAfterPlan returns the version a database at current ends up on once
+// plan has been carried out.
+func versionAfterPlan(current uint, plan []PlannedMigration, loadExampleData bool) uint {
+	if len(plan) == 0 {
+		return current
+	}
+	last := plan[len(plan)-1]
+	if last.Up {
+		return last.Version
+	}
+	_, _, versions := migrationFiles(loadExampleData)
+	if i := indexOfVersion(versions, last.Version); i > 0 {
+		return versions[i-1]
+	}
+	return 0
+}
+
+// runMigration executes run while holding the migration lock and then checks
+// that the database ended up at the version plan predicted. In dry-run mode it
+// only prints the SQL the planned migrations would execute.
+func (pg *postgres) runMigration(db *sql.DB, cfg *viper.Viper, plan func(current uint, loadExampleData bool) ([]PlannedMigration, error), run func(m *migrate.Migrate) error) (err error) {
 	loadExampleData := cfg.GetBool(migrateExampleDataKey)
 
-	m, err := prepareForMigration(db, dbURL, loadExampleData)
+	m, err := pg.prepareForMigration(db, cfg, loadExampleData)
 	if err != nil {
 		return err
 	}
+	defer closeMigration(m, &err)
 
-	err = m.Down()
-	if err == migrate.ErrNoChange {
+	planFromCurrentVersion := func() (uint, []PlannedMigration, error) {
+		current, dirty, err := currentMigrationVersion(m)
+		if err != nil {
+			return 0, nil, err
+		}
+		if dirty {
+			return 0, nil, fmt.Errorf("database is dirty at version %d, use ForceVersion after repairing it", current)
+		}
+		planned, err := plan(current, loadExampleData)
+		return current, planned, err
+	}
+
+	if cfg.GetBool(migrateDryRunKey) {
+		_, planned, err := planFromCurrentVersion()
+		if err != nil {
+			return err
+		}
+		writeMigrationPlan(pg.migrationOutput, planned)
 		return nil
 	}
-	return err
+
+	return pg.withMigrationLock(db, func() error {
+		if err := pg.renumberLegacyExampleData(m); err != nil {
+			return err
+		}
+
+		// another instance may have migrated while this one waited for the
+		// lock, so the plan is only worked out once the lock is held
+		current, planned, err := planFromCurrentVersion()
+		if err != nil {
+			return err
+		}
+		err = ignoreNoChange(run(m))
+		// even a failed run may have changed part of the schema
+		pg.invalidatePreparedStatements()
+		if err != nil {
+			return err
+		}
+
+		expected := versionAfterPlan(current, planned, loadExampleData)
+		actual, dirty, err := currentMigrationVersion(m)
+		if err != nil {
+			return err
+		}
+		if dirty || actual != expected {
+			return fmt.Errorf("expected database to be at migration version %d, found version %d (dirty: %t)", expected, actual, dirty)
+		}
+		pg.logger.Printf("database is at migration version %d", actual)
+		return nil
+	})
+}
+
+func (pg *postgres) Migrate(db *sql.DB, cfg *viper.Viper) error {
+	return pg.runMigration(db, cfg,
+		func(current uint, loadExampleData bool) ([]PlannedMigration, error) {
+			_, _, versions := migrationFiles(loadExampleData)
+			if len(versions) == 0 {
+				return nil, nil
+			}
+			return planMigrationTo(current, versions[len(versions)-1], loadExampleData)
+		},
+		func(m *migrate.Migrate) error { return m.Up() },
+	)
+}
+
+// Downgrade rolls back the most recently applied migration.
+func (pg *postgres) Downgrade(db *sql.DB, cfg *viper.Viper) error {
+	return pg.Steps(db, cfg, -1)
+}
+
+// MigrateTo migrates up or down until the given version is the latest one
+// applied.
+func (pg *postgres) MigrateTo(db *sql.DB, cfg *viper.Viper, version uint) error {
+	return pg.runMigration(db, cfg,
+		func(current uint, loadExampleData bool) ([]PlannedMigration, error) {
+			return planMigrationTo(current, version, loadExampleData)
+		},
+		func(m *migrate.Migrate) error { return m.Migrate(version) },
+	)
+}
+
+// Steps applies the next n migrations, or rolls back the last -n migrations
+// when n is negative.
+func (pg *postgres) Steps(db *sql.DB, cfg *viper.Viper, n int) error {
+	return pg.runMigration(db, cfg,
+		func(current uint, loadExampleData bool) ([]PlannedMigration, error) {
+			return planMigrationSteps(current, n, loadExampleData)
+		},
+		func(m *migrate.Migrate) error { return m.Steps(n) },
+	)
+}
+
+// MigrationStatus reports the current schema version, whether the last
+// migration left it dirty, and which migrations have yet to run.
+func (pg *postgres) MigrationStatus(db *sql.DB, cfg *viper.Viper) (_ *MigrationState, err error) {
+	loadExampleData := cfg.GetBool(migrateExampleDataKey)
+
+	m, err := pg.prepareForMigration(db, cfg, loadExampleData)
+	if err != nil {
+		return nil, err
+	}
+	defer closeMigration(m, &err)
+
+	state := &MigrationState{}
+	state.Version, state.Dirty, err = currentMigrationVersion(m)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _, versions := migrationFiles(loadExampleData)
+	for _, v := range versions {
+		if v > state.Version {
+			state.Pending = append(state.Pending, v)
+		}
+	}
+	return state, nil
+}
+
+// ForceVersion records version as the current schema version and clears the
+// dirty flag without running any migration. Use it once a failed migration
+// has been repaired by hand. A version of -1 marks the database as having no
+// migrations applied.
+func (pg *postgres) ForceVersion(db *sql.DB, cfg *viper.Viper, version int) (err error) {
+	loadExampleData := cfg.GetBool(migrateExampleDataKey)
+
+	m, err := pg.prepareForMigration(db, cfg, loadExampleData)
+	if err != nil {
+		return err
+	}
+	defer closeMigration(m, &err)
+
+	return pg.withMigrationLock(db, func() error {
+		return m.Force(version)
+	})
 }
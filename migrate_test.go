@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func plannedVersions(plan []PlannedMigration) []uint {
+	var out []uint
+	for _, p := range plan {
+		out = append(out, p.Version)
+	}
+	return out
+}
+
+func TestMigrationFileNames(t *testing.T) {
+	t.Parallel()
+
+	t.Run("without example data", func(*testing.T) {
+		for _, name := range migrationFileNames(false) {
+			assert.NotContains(t, name, exampleDataMigrationName)
+		}
+	})
+
+	t.Run("hides example data from the migration source", func(*testing.T) {
+		_, err := migrationFS{}.Open("1512371454_example_data.up.sql")
+		assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+		f, err := migrationFS{loadExampleData: true}.Open("1512371454_example_data.up.sql")
+		require.NoError(t, err)
+		f.Close()
+	})
+
+	t.Run("with example data", func(*testing.T) {
+		_, _, versions := migrationFiles(true)
+		assert.Contains(t, versions, exampleDataVersion)
+		assert.NotEqual(t, exampleDataVersion, versions[len(versions)-1], "schema migrations should run after the example data")
+	})
+}
+
+func TestParseMigrationFileName(t *testing.T) {
+	t.Parallel()
+
+	t.Run("up migration", func(*testing.T) {
+		actual, ok := parseMigrationFileName("1498638543_auth.up.sql")
+		assert.True(t, ok)
+		assert.Equal(t, migrationFile{version: 1498638543, name: "auth", up: true, filename: "1498638543_auth.up.sql"}, actual)
+	})
+
+	t.Run("down migration", func(*testing.T) {
+		actual, ok := parseMigrationFileName("1498638543_auth.down.sql")
+		assert.True(t, ok)
+		assert.False(t, actual.up)
+		assert.Equal(t, "auth", actual.name)
+	})
+
+	t.Run("with invalid names", func(*testing.T) {
+		for _, name := range []string{"migrations.go", "auth.up.sql", "1498638543_auth.sql"} {
+			_, ok := parseMigrationFileName(name)
+			assert.False(t, ok, "expected %q to be rejected", name)
+		}
+	})
+}
+
+func TestPlanMigrationTo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("migrating up", func(*testing.T) {
+		plan, err := planMigrationTo(1495495688, 1498638543, false)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{1496132528, 1498638543}, plannedVersions(plan))
+		for _, p := range plan {
+			assert.True(t, p.Up)
+			assert.NotEmpty(t, p.SQL)
+		}
+	})
+
+	t.Run("migrating down", func(*testing.T) {
+		plan, err := planMigrationTo(1498638543, 1495495688, false)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{1498638543, 1496132528}, plannedVersions(plan))
+		for _, p := range plan {
+			assert.False(t, p.Up)
+		}
+	})
+
+	t.Run("rolling everything back", func(*testing.T) {
+		plan, err := planMigrationTo(1496132528, 0, false)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{1496132528, 1495495688}, plannedVersions(plan))
+	})
+
+	t.Run("already at target", func(*testing.T) {
+		plan, err := planMigrationTo(1496132528, 1496132528, false)
+		require.NoError(t, err)
+		assert.Empty(t, plan)
+	})
+
+	t.Run("with unknown target", func(*testing.T) {
+		_, err := planMigrationTo(0, 12345, false)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestPlanMigrationSteps(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stepping up from an empty database", func(*testing.T) {
+		plan, err := planMigrationSteps(0, 2, false)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{1495495688, 1496132528}, plannedVersions(plan))
+	})
+
+	t.Run("stepping down", func(*testing.T) {
+		plan, err := planMigrationSteps(1498638543, -1, false)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{1498638543}, plannedVersions(plan))
+		assert.False(t, plan[0].Up)
+	})
+
+	t.Run("stepping past the first migration", func(*testing.T) {
+		_, err := planMigrationSteps(1495495688, -2, false)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("stepping past the last migration", func(*testing.T) {
+		_, _, versions := migrationFiles(false)
+		_, err := planMigrationSteps(versions[len(versions)-1], 1, false)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestWriteMigrationPlan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("normal usecase", func(*testing.T) {
+		var buf bytes.Buffer
+		writeMigrationPlan(&buf, []PlannedMigration{{Version: 1, Name: "things", Up: true, SQL: "CREATE TABLE things ();\n"}})
+		assert.Equal(t, "-- 1_things.up.sql\nCREATE TABLE things ();\n\n", buf.String())
+	})
+
+	t.Run("with nothing to do", func(*testing.T) {
+		var buf bytes.Buffer
+		writeMigrationPlan(&buf, nil)
+		assert.Equal(t, "-- no migrations to run\n", buf.String())
+	})
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// migrationLockKey identifies the advisory lock held while migrating, so only
+// one instance migrates a given database at a time.
+const migrationLockKey int64 = 0x6461697279636172
+
+const migrationLockPollInterval = 250 * time.Millisecond
+
+// Logger receives progress messages while connecting and migrating.
+// *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+var defaultLogger Logger = redactingLogger{log.New(os.Stderr, "", log.LstdFlags)}
+
+// SetLogger replaces where connection and migration progress is reported.
+// Connection passwords are masked before messages reach l. A nil logger
+// restores the default, which writes to stderr.
+func (pg *postgres) SetLogger(l Logger) {
+	if l == nil {
+		pg.logger = defaultLogger
+		return
+	}
+	pg.logger = redactingLogger{l}
+}
+
+// migrateLogger adapts a Logger to what the migrate library expects.
+type migrateLogger struct {
+	Logger
+}
+
+func (migrateLogger) Verbose() bool { return false }
+
+// MigrationConfig controls how long to wait for the database to accept
+// connections and for other instances to finish migrating.
+type MigrationConfig struct {
+	// LockTimeout is how long to wait for another instance to release the
+	// migration lock before giving up.
+	LockTimeout time.Duration
+	// ConnectAttempts is how many times the database is pinged before
+	// giving up.
+	ConnectAttempts uint
+	// ConnectBaseDelay is the wait after the first failed ping. It doubles
+	// after every further failure, up to ConnectMaxDelay.
+	ConnectBaseDelay time.Duration
+	ConnectMaxDelay  time.Duration
+}
+
+var DefaultMigrationConfig = MigrationConfig{
+	LockTimeout:      5 * time.Minute,
+	ConnectAttempts:  10,
+	ConnectBaseDelay: 250 * time.Millisecond,
+	ConnectMaxDelay:  10 * time.Second,
+}
+
+// SetMigrationConfig replaces the migration timeouts. Zero fields keep their
+// default value.
+func (pg *postgres) SetMigrationConfig(cfg MigrationConfig) {
+	if cfg.LockTimeout == 0 {
+		cfg.LockTimeout = DefaultMigrationConfig.LockTimeout
+	}
+	if cfg.ConnectAttempts == 0 {
+		cfg.ConnectAttempts = DefaultMigrationConfig.ConnectAttempts
+	}
+	if cfg.ConnectBaseDelay == 0 {
+		cfg.ConnectBaseDelay = DefaultMigrationConfig.ConnectBaseDelay
+	}
+	if cfg.ConnectMaxDelay == 0 {
+		cfg.ConnectMaxDelay = DefaultMigrationConfig.ConnectMaxDelay
+	}
+	pg.migration = cfg
+}
+
+// backoffDelay returns how long to wait after the given number of failed
+// attempts, doubling from base and never exceeding max.
+func backoffDelay(failures uint, base time.Duration, max time.Duration) time.Duration {
+	delay := base
+	for i := uint(1); i < failures; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
+
+func (pg *postgres) databaseIsAvailable(db *sql.DB) error {
+	var err error
+	for failures := uint(1); ; failures++ {
+		if err = db.Ping(); err == nil {
+			return nil
+		}
+		if failures >= pg.migration.ConnectAttempts {
+			return fmt.Errorf("failed to connect to the database after %d attempts: %v", failures, err)
+		}
+		delay := backoffDelay(failures, pg.migration.ConnectBaseDelay, pg.migration.ConnectMaxDelay)
+		pg.logger.Printf("ping failed (attempt %d of %d), retrying in %s: %v", failures, pg.migration.ConnectAttempts, delay, err)
+		pg.sleep(delay)
+	}
+}
+
+// withMigrationLock runs fn while holding the migration advisory lock. The
+// lock belongs to a single connection, so one is reserved for the duration.
+func (pg *postgres) withMigrationLock(db *sql.DB, fn func() error) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(pg.migration.LockTimeout)
+	for waiting := false; ; waiting = true {
+		var acquired bool
+		if err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, migrationLockKey).Scan(&acquired); err != nil {
+			return err
+		}
+		if acquired {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for another instance to finish migrating", pg.migration.LockTimeout)
+		}
+		if !waiting {
+			pg.logger.Printf("another instance is migrating the database, waiting up to %s", pg.migration.LockTimeout)
+		}
+		pg.sleep(migrationLockPollInterval)
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+
+	return fn()
+}
@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+func TestSetMigrationConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("normal usecase", func(*testing.T) {
+		client := NewPostgres()
+		expected := MigrationConfig{
+			LockTimeout:      time.Second,
+			ConnectAttempts:  3,
+			ConnectBaseDelay: time.Millisecond,
+			ConnectMaxDelay:  time.Second,
+		}
+		client.SetMigrationConfig(expected)
+		assert.Equal(t, expected, client.migration)
+	})
+
+	t.Run("with zero values", func(*testing.T) {
+		client := NewPostgres()
+		client.SetMigrationConfig(MigrationConfig{ConnectAttempts: 3})
+
+		expected := DefaultMigrationConfig
+		expected.ConnectAttempts = 3
+		assert.Equal(t, expected, client.migration)
+	})
+}
+
+func TestSetLogger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("normal usecase", func(*testing.T) {
+		client := NewPostgres()
+		l := &recordingLogger{}
+		client.SetLogger(l)
+		assert.Equal(t, redactingLogger{l}, client.logger)
+	})
+
+	t.Run("with nil logger", func(*testing.T) {
+		client := NewPostgres()
+		client.SetLogger(nil)
+		assert.Equal(t, defaultLogger, client.logger)
+	})
+}
+
+func TestBackoffDelay(t *testing.T) {
+	t.Parallel()
+
+	base, max := 100*time.Millisecond, time.Second
+	examples := map[uint]time.Duration{
+		1:  100 * time.Millisecond,
+		2:  200 * time.Millisecond,
+		3:  400 * time.Millisecond,
+		4:  800 * time.Millisecond,
+		5:  time.Second,
+		40: time.Second,
+	}
+	for failures, expected := range examples {
+		assert.Equal(t, expected, backoffDelay(failures, base, max), "unexpected delay after %d failures", failures)
+	}
+}
+
+func TestDatabaseIsAvailable(t *testing.T) {
+	t.Parallel()
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	t.Run("optimal behavior", func(*testing.T) {
+		client := NewPostgres()
+		var slept []time.Duration
+		client.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+		assert.NoError(t, client.databaseIsAvailable(mockDB))
+		assert.Empty(t, slept, "should not wait when the database is reachable")
+	})
+}
+
+func TestWithMigrationLock(t *testing.T) {
+	t.Parallel()
+	lockQuery := formatQueryForSQLMock(`SELECT pg_try_advisory_lock($1)`)
+	unlockQuery := formatQueryForSQLMock(`SELECT pg_advisory_unlock($1)`)
+
+	t.Run("waits for the lock", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+
+		client := NewPostgres()
+		l := &recordingLogger{}
+		client.SetLogger(l)
+		var slept []time.Duration
+		client.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+		mock.ExpectQuery(lockQuery).WithArgs(migrationLockKey).WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(false))
+		mock.ExpectQuery(lockQuery).WithArgs(migrationLockKey).WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(false))
+		mock.ExpectQuery(lockQuery).WithArgs(migrationLockKey).WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(true))
+		mock.ExpectExec(unlockQuery).WithArgs(migrationLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		ran := false
+		err = client.withMigrationLock(mockDB, func() error {
+			ran = true
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, ran)
+		assert.Equal(t, []time.Duration{migrationLockPollInterval, migrationLockPollInterval}, slept)
+		assert.Len(t, l.messages, 1, "waiting should only be reported once")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("releases the lock when fn fails", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+
+		client := NewPostgres()
+		mock.ExpectQuery(lockQuery).WithArgs(migrationLockKey).WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(true))
+		mock.ExpectExec(unlockQuery).WithArgs(migrationLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err = client.withMigrationLock(mockDB, func() error { return errors.New("pineapple on pizza") })
+
+		assert.NotNil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with timeout", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+
+		client := NewPostgres()
+		client.SetMigrationConfig(MigrationConfig{LockTimeout: time.Nanosecond})
+		client.sleep = func(time.Duration) {}
+		mock.ExpectQuery(lockQuery).WithArgs(migrationLockKey).WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(false))
+
+		ran := false
+		err = client.withMigrationLock(mockDB, func() error {
+			ran = true
+			return nil
+		})
+
+		assert.NotNil(t, err)
+		assert.False(t, ran)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error acquiring lock", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+
+		client := NewPostgres()
+		mock.ExpectQuery(lockQuery).WithArgs(migrationLockKey).WillReturnError(errors.New("pineapple on pizza"))
+
+		err = client.withMigrationLock(mockDB, func() error { return nil })
+
+		assert.NotNil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func TestVersionAfterPlan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with empty plan", func(*testing.T) {
+		assert.Equal(t, uint(1496132528), versionAfterPlan(1496132528, nil, false))
+	})
+
+	t.Run("migrating up", func(*testing.T) {
+		plan, err := planMigrationTo(0, 1498638543, false)
+		require.NoError(t, err)
+		assert.Equal(t, uint(1498638543), versionAfterPlan(0, plan, false))
+	})
+
+	t.Run("migrating down", func(*testing.T) {
+		plan, err := planMigrationSteps(1498638543, -2, false)
+		require.NoError(t, err)
+		assert.Equal(t, uint(1495495688), versionAfterPlan(1498638543, plan, false))
+	})
+
+	t.Run("rolling everything back", func(*testing.T) {
+		plan, err := planMigrationTo(1496132528, 0, false)
+		require.NoError(t, err)
+		assert.Zero(t, versionAfterPlan(1496132528, plan, false))
+	})
+}
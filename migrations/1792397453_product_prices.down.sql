@@ -0,0 +1 @@
+DROP TABLE product_prices;
\ No newline at end of file
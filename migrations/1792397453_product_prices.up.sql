@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS product_prices (
+    "id" bigserial,
+    "product_id" bigint NOT NULL,
+    "currency" text NOT NULL CONSTRAINT currency_must_be_iso_4217 CHECK(currency ~ '^[A-Z]{3}$'),
+    "price" numeric(15, 2) NOT NULL,
+    "starts_on" timestamp NOT NULL DEFAULT NOW(),
+    "expires_on" timestamp CONSTRAINT price_must_expire_after_it_starts CHECK(
+        expires_on IS NULL
+                OR
+        expires_on > starts_on
+    ),
+    "created_on" timestamp NOT NULL DEFAULT NOW(),
+    "updated_on" timestamp,
+    "archived_on" timestamp,
+    PRIMARY KEY ("id"),
+    FOREIGN KEY ("product_id") REFERENCES "products"("id")
+);
+CREATE UNIQUE INDEX product_prices_product_currency_start_idx ON product_prices (product_id, currency, starts_on) WHERE archived_on IS NULL;
@@ -0,0 +1,6 @@
+DROP INDEX products_sale_ends_on_idx;
+DROP INDEX products_sale_starts_on_idx;
+ALTER TABLE products
+    DROP CONSTRAINT sale_must_end_after_it_starts,
+    DROP COLUMN "sale_ends_on",
+    DROP COLUMN "sale_starts_on";
\ No newline at end of file
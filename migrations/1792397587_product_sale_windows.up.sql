@@ -0,0 +1,12 @@
+ALTER TABLE products
+    ADD COLUMN "sale_starts_on" timestamp,
+    ADD COLUMN "sale_ends_on" timestamp,
+    ADD CONSTRAINT sale_must_end_after_it_starts CHECK(
+        sale_starts_on IS NULL
+                OR
+        sale_ends_on IS NULL
+                OR
+        sale_ends_on > sale_starts_on
+    );
+CREATE INDEX products_sale_starts_on_idx ON products (sale_starts_on) WHERE sale_starts_on IS NOT NULL;
+CREATE INDEX products_sale_ends_on_idx ON products (sale_ends_on) WHERE sale_ends_on IS NOT NULL;
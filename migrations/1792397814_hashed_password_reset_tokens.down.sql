@@ -0,0 +1,5 @@
+DROP INDEX password_reset_tokens_expires_on_idx;
+DROP INDEX password_reset_tokens_user_id_idx;
+ALTER TABLE password_reset_tokens DROP COLUMN "invalidated_on";
+ALTER TABLE password_reset_tokens RENAME CONSTRAINT password_reset_tokens_token_hash_key TO password_reset_tokens_token_key;
+ALTER TABLE password_reset_tokens RENAME COLUMN "token_hash" TO "token";
\ No newline at end of file
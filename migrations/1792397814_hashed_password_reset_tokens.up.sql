@@ -0,0 +1,6 @@
+DELETE FROM password_reset_tokens;
+ALTER TABLE password_reset_tokens RENAME COLUMN "token" TO "token_hash";
+ALTER TABLE password_reset_tokens RENAME CONSTRAINT password_reset_tokens_token_key TO password_reset_tokens_token_hash_key;
+ALTER TABLE password_reset_tokens ADD COLUMN "invalidated_on" timestamp;
+CREATE INDEX password_reset_tokens_user_id_idx ON password_reset_tokens (user_id);
+CREATE INDEX password_reset_tokens_expires_on_idx ON password_reset_tokens (expires_on);
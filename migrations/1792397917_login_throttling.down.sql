@@ -0,0 +1,4 @@
+DROP TABLE login_lockouts;
+DROP INDEX login_attempts_ip_address_created_on_idx;
+DROP INDEX login_attempts_username_created_on_idx;
+ALTER TABLE login_attempts DROP COLUMN "ip_address";
\ No newline at end of file
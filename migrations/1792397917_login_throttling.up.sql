@@ -0,0 +1,21 @@
+ALTER TABLE login_attempts ADD COLUMN "ip_address" inet;
+CREATE INDEX login_attempts_username_created_on_idx ON login_attempts (username, created_on);
+CREATE INDEX login_attempts_ip_address_created_on_idx ON login_attempts (ip_address, created_on) WHERE ip_address IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS login_lockouts (
+    "id" bigserial,
+    "username" text,
+    "ip_address" inet,
+    "locked_until" timestamp NOT NULL,
+    "created_on" timestamp NOT NULL DEFAULT NOW(),
+    "updated_on" timestamp,
+    "archived_on" timestamp,
+    CONSTRAINT lockout_must_have_a_subject CHECK(
+        username IS NOT NULL
+                OR
+        ip_address IS NOT NULL
+    ),
+    PRIMARY KEY ("id")
+);
+CREATE INDEX login_lockouts_username_idx ON login_lockouts (username, locked_until) WHERE archived_on IS NULL;
+CREATE INDEX login_lockouts_ip_address_idx ON login_lockouts (ip_address, locked_until) WHERE archived_on IS NULL;
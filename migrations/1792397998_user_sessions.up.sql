@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS user_sessions (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "session_id_hash" text NOT NULL,
+    "user_agent" text NOT NULL DEFAULT '',
+    "ip_address" inet,
+    "last_seen_on" timestamp NOT NULL DEFAULT NOW(),
+    "expires_on" timestamp NOT NULL,
+    "created_on" timestamp NOT NULL DEFAULT NOW(),
+    "updated_on" timestamp,
+    "archived_on" timestamp,
+    UNIQUE ("session_id_hash"),
+    PRIMARY KEY ("id"),
+    FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX user_sessions_user_id_idx ON user_sessions (user_id) WHERE archived_on IS NULL;
@@ -0,0 +1,71 @@
+CREATE TABLE IF NOT EXISTS roles (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "description" text NOT NULL DEFAULT '',
+    "created_on" timestamp NOT NULL DEFAULT NOW(),
+    "updated_on" timestamp,
+    "archived_on" timestamp,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX roles_name_idx ON roles (name) WHERE archived_on IS NULL;
+
+CREATE TABLE IF NOT EXISTS permissions (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "description" text NOT NULL DEFAULT '',
+    "created_on" timestamp NOT NULL DEFAULT NOW(),
+    "updated_on" timestamp,
+    "archived_on" timestamp,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX permissions_name_idx ON permissions (name) WHERE archived_on IS NULL;
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    "id" bigserial,
+    "role_id" bigint NOT NULL,
+    "permission_id" bigint NOT NULL,
+    "created_on" timestamp NOT NULL DEFAULT NOW(),
+    "updated_on" timestamp,
+    "archived_on" timestamp,
+    PRIMARY KEY ("id"),
+    FOREIGN KEY ("role_id") REFERENCES "roles"("id"),
+    FOREIGN KEY ("permission_id") REFERENCES "permissions"("id")
+);
+CREATE UNIQUE INDEX role_permissions_role_permission_idx ON role_permissions (role_id, permission_id) WHERE archived_on IS NULL;
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "role_id" bigint NOT NULL,
+    "created_on" timestamp NOT NULL DEFAULT NOW(),
+    "updated_on" timestamp,
+    "archived_on" timestamp,
+    PRIMARY KEY ("id"),
+    FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    FOREIGN KEY ("role_id") REFERENCES "roles"("id")
+);
+CREATE UNIQUE INDEX user_roles_user_role_idx ON user_roles (user_id, role_id) WHERE archived_on IS NULL;
+
+INSERT INTO permissions (name, description) VALUES
+    ('products:write', 'create, update and archive products and product roots'),
+    ('inventory:write', 'adjust product quantities and availability'),
+    ('discounts:write', 'create, update and archive discounts'),
+    ('webhooks:manage', 'create, update and archive webhooks'),
+    ('users:manage', 'manage users, their roles and their sessions');
+
+INSERT INTO roles (name, description) VALUES
+    ('admin', 'full access to the store'),
+    ('catalog_manager', 'manages products and discounts'),
+    ('warehouse', 'manages inventory');
+
+INSERT INTO role_permissions (role_id, permission_id)
+    SELECT roles.id, permissions.id FROM roles, permissions
+    WHERE roles.name = 'admin'
+    OR (roles.name = 'catalog_manager' AND permissions.name IN ('products:write', 'inventory:write', 'discounts:write'))
+    OR (roles.name = 'warehouse' AND permissions.name = 'inventory:write');
+
+INSERT INTO user_roles (user_id, role_id)
+    SELECT users.id, roles.id FROM users, roles
+    WHERE users.is_admin IS TRUE
+    AND users.archived_on IS NULL
+    AND roles.name = 'admin';
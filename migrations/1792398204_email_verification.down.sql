@@ -0,0 +1,3 @@
+DROP TABLE email_verification_tokens;
+DROP INDEX users_email_idx;
+ALTER TABLE users DROP COLUMN "verified_on";
\ No newline at end of file
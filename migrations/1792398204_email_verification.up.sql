@@ -0,0 +1,20 @@
+ALTER TABLE users ADD COLUMN "verified_on" timestamp;
+CREATE UNIQUE INDEX users_email_idx ON users (lower(email)) WHERE archived_on IS NULL;
+
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "email" text NOT NULL,
+    "token_hash" text NOT NULL,
+    "created_on" timestamp NOT NULL DEFAULT NOW(),
+    "expires_on" timestamp NOT NULL DEFAULT NOW() + (24 * interval '1 hour'),
+    "verified_on" timestamp,
+    "invalidated_on" timestamp,
+    "updated_on" timestamp,
+    "archived_on" timestamp,
+    UNIQUE ("token_hash"),
+    PRIMARY KEY ("id"),
+    FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX email_verification_tokens_user_id_idx ON email_verification_tokens (user_id);
+CREATE INDEX email_verification_tokens_expires_on_idx ON email_verification_tokens (expires_on);
@@ -0,0 +1,14 @@
+CREATE TYPE webhook_event AS ENUM ('product_created', 'product_updated', 'product_archived');
+ALTER TABLE webhooks ADD COLUMN "event_type" webhook_event;
+UPDATE webhooks SET event_type = subscriptions.event_type::webhook_event
+    FROM (
+        SELECT DISTINCT ON (webhook_id) webhook_id, event_type
+        FROM webhook_subscriptions
+        WHERE event_type IN ('product_created', 'product_updated', 'product_archived')
+        ORDER BY webhook_id, archived_on IS NOT NULL, id
+    ) AS subscriptions
+    WHERE subscriptions.webhook_id = webhooks.id;
+UPDATE webhooks SET event_type = 'product_updated', archived_on = COALESCE(archived_on, NOW()) WHERE event_type IS NULL;
+ALTER TABLE webhooks ALTER COLUMN "event_type" SET NOT NULL;
+DROP TABLE webhook_subscriptions;
+DROP TABLE webhook_event_types;
\ No newline at end of file
@@ -0,0 +1,50 @@
+CREATE TABLE IF NOT EXISTS webhook_event_types (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "description" text NOT NULL DEFAULT '',
+    "created_on" timestamp NOT NULL DEFAULT NOW(),
+    "updated_on" timestamp,
+    "archived_on" timestamp,
+    UNIQUE ("name"),
+    PRIMARY KEY ("id")
+);
+
+INSERT INTO webhook_event_types (name, description) VALUES
+    ('product_created', 'a product was created'),
+    ('product_updated', 'a product was updated'),
+    ('product_archived', 'a product was archived'),
+    ('product_root_created', 'a product root was created'),
+    ('product_root_updated', 'a product root was updated'),
+    ('product_root_archived', 'a product root was archived'),
+    ('product_option_created', 'a product option was created'),
+    ('product_option_updated', 'a product option was updated'),
+    ('product_option_archived', 'a product option was archived'),
+    ('discount_created', 'a discount was created'),
+    ('discount_updated', 'a discount was updated'),
+    ('discount_archived', 'a discount was archived'),
+    ('user_created', 'a user was created'),
+    ('user_updated', 'a user was updated'),
+    ('user_archived', 'a user was archived'),
+    ('inventory_changed', 'the available quantity of a product changed'),
+    ('password_reset_requested', 'a user requested a password reset'),
+    ('password_reset_completed', 'a user completed a password reset');
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    "id" bigserial,
+    "webhook_id" bigint NOT NULL,
+    "event_type" text NOT NULL,
+    "created_on" timestamp NOT NULL DEFAULT NOW(),
+    "updated_on" timestamp,
+    "archived_on" timestamp,
+    PRIMARY KEY ("id"),
+    FOREIGN KEY ("webhook_id") REFERENCES "webhooks"("id"),
+    FOREIGN KEY ("event_type") REFERENCES "webhook_event_types"("name")
+);
+CREATE UNIQUE INDEX webhook_subscriptions_webhook_event_type_idx ON webhook_subscriptions (webhook_id, event_type) WHERE archived_on IS NULL;
+CREATE INDEX webhook_subscriptions_event_type_idx ON webhook_subscriptions (event_type) WHERE archived_on IS NULL;
+
+INSERT INTO webhook_subscriptions (webhook_id, event_type, archived_on)
+    SELECT id, event_type::text, archived_on FROM webhooks;
+
+ALTER TABLE webhooks DROP COLUMN "event_type";
+DROP TYPE webhook_event;
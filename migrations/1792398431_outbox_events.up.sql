@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS outbox_events (
+    "id" bigserial,
+    "event_type" text NOT NULL,
+    "payload" jsonb NOT NULL,
+    "attempts" integer NOT NULL DEFAULT 0,
+    "available_on" timestamp NOT NULL DEFAULT NOW(),
+    "claimed_until" timestamp,
+    "last_error" text,
+    "delivered_on" timestamp,
+    "created_on" timestamp NOT NULL DEFAULT NOW(),
+    "updated_on" timestamp,
+    "archived_on" timestamp,
+    PRIMARY KEY ("id"),
+    FOREIGN KEY ("event_type") REFERENCES "webhook_event_types"("name")
+);
+CREATE INDEX outbox_events_pending_idx ON outbox_events (available_on, id) WHERE delivered_on IS NULL AND archived_on IS NULL;
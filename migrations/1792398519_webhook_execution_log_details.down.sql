@@ -0,0 +1,7 @@
+DROP INDEX webhook_execution_logs_webhook_executed_on_idx;
+ALTER TABLE webhook_execution_logs DROP COLUMN "error_message";
+ALTER TABLE webhook_execution_logs DROP COLUMN "duration_ms";
+ALTER TABLE webhook_execution_logs DROP COLUMN "response_body";
+ALTER TABLE webhook_execution_logs DROP COLUMN "request_body";
+ALTER TABLE webhook_execution_logs DROP COLUMN "attempt";
+ALTER TABLE webhook_execution_logs DROP COLUMN "event_type";
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE webhook_execution_logs ADD COLUMN "event_type" text NOT NULL DEFAULT '';
+ALTER TABLE webhook_execution_logs ADD COLUMN "attempt" integer NOT NULL DEFAULT 1;
+ALTER TABLE webhook_execution_logs ADD COLUMN "request_body" text NOT NULL DEFAULT '';
+ALTER TABLE webhook_execution_logs ADD COLUMN "response_body" text NOT NULL DEFAULT '';
+ALTER TABLE webhook_execution_logs ADD COLUMN "duration_ms" integer NOT NULL DEFAULT 0;
+ALTER TABLE webhook_execution_logs ADD COLUMN "error_message" text NOT NULL DEFAULT '';
+CREATE INDEX webhook_execution_logs_webhook_executed_on_idx ON webhook_execution_logs (webhook_id, executed_on DESC);
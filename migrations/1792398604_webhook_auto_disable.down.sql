@@ -0,0 +1,3 @@
+ALTER TABLE webhooks DROP COLUMN "disabled_reason";
+ALTER TABLE webhooks DROP COLUMN "disabled_on";
+ALTER TABLE webhooks DROP COLUMN "consecutive_failures";
\ No newline at end of file
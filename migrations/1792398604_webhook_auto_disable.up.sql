@@ -0,0 +1,3 @@
+ALTER TABLE webhooks ADD COLUMN "consecutive_failures" integer NOT NULL DEFAULT 0;
+ALTER TABLE webhooks ADD COLUMN "disabled_on" timestamp;
+ALTER TABLE webhooks ADD COLUMN "disabled_reason" text NOT NULL DEFAULT '';
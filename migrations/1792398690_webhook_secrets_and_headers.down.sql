@@ -0,0 +1,2 @@
+DROP TABLE webhook_secrets;
+ALTER TABLE webhooks DROP COLUMN "headers";
\ No newline at end of file
@@ -0,0 +1,14 @@
+ALTER TABLE webhooks ADD COLUMN "headers" jsonb NOT NULL DEFAULT '{}' CHECK (jsonb_typeof("headers") = 'object');
+
+CREATE TABLE IF NOT EXISTS webhook_secrets (
+    "id" bigserial,
+    "webhook_id" bigint NOT NULL,
+    "sealed_secret" bytea NOT NULL,
+    "expires_on" timestamp,
+    "created_on" timestamp NOT NULL DEFAULT NOW(),
+    "updated_on" timestamp,
+    "archived_on" timestamp,
+    PRIMARY KEY ("id"),
+    FOREIGN KEY ("webhook_id") REFERENCES "webhooks"("id")
+);
+CREATE INDEX webhook_secrets_webhook_id_idx ON webhook_secrets (webhook_id) WHERE archived_on IS NULL;
@@ -0,0 +1 @@
+ALTER TABLE webhooks DROP COLUMN "filter";
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE webhooks ADD COLUMN "filter" jsonb NOT NULL DEFAULT '[]' CHECK (jsonb_typeof("filter") = 'array');
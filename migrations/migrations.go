@@ -0,0 +1,12 @@
+// Package migrations embeds the SQL migration files so they ship inside the
+// binary.
+package migrations
+
+import (
+	"embed"
+)
+
+// FS holds every migration file, named <version>_<name>.<up|down>.sql.
+//
+//go:embed *.sql
+var FS embed.FS
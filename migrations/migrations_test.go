@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"io/fs"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationFiles(t *testing.T) {
+	t.Parallel()
+
+	entries, err := fs.ReadDir(FS, ".")
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+
+	ups, downs := map[string]bool{}, map[string]bool{}
+	var versions []uint64
+	for _, e := range entries {
+		name := e.Name()
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			base := strings.TrimSuffix(name, ".up.sql")
+			ups[base] = true
+			version, err := strconv.ParseUint(strings.SplitN(base, "_", 2)[0], 10, 64)
+			require.NoError(t, err, "%s should start with a numeric version", name)
+			versions = append(versions, version)
+		case strings.HasSuffix(name, ".down.sql"):
+			downs[strings.TrimSuffix(name, ".down.sql")] = true
+		default:
+			t.Errorf("unexpected file %s", name)
+		}
+	}
+
+	t.Run("every up migration has a down migration", func(*testing.T) {
+		for base := range ups {
+			assert.True(t, downs[base], "%s.up.sql has no matching down migration", base)
+		}
+		for base := range downs {
+			assert.True(t, ups[base], "%s.down.sql has no matching up migration", base)
+		}
+	})
+
+	t.Run("versions are strictly increasing", func(*testing.T) {
+		for i := 1; i < len(versions); i++ {
+			assert.True(t, versions[i] > versions[i-1], "version %d must be greater than %d", versions[i], versions[i-1])
+		}
+	})
+}
@@ -0,0 +1,178 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+// roundTripDatabaseKey names the environment variable holding the connection
+// string of a throwaway postgres database. The round trip suite is skipped
+// when it is unset. Everything it creates lives in a temporary schema that is
+// dropped afterwards.
+const roundTripDatabaseKey = "MIGRATIONS_ROUND_TRIP_DATABASE_URL"
+
+// catalogQueries describe every kind of schema object a down migration has to
+// clean up. Each query takes the schema name as $1 and returns one text column
+// per object.
+var catalogQueries = []string{
+	`SELECT 'table ' || table_name FROM information_schema.tables WHERE table_schema = $1`,
+	`SELECT 'column ' || table_name || '.' || column_name || ' ' || udt_name || ' nullable=' || is_nullable || ' default=' || COALESCE(column_default, '')
+		FROM information_schema.columns WHERE table_schema = $1`,
+	`SELECT 'index ' || indexname || ': ' || indexdef FROM pg_indexes WHERE schemaname = $1`,
+	`SELECT 'constraint ' || conrelid::regclass::text || '.' || conname || ': ' || pg_get_constraintdef(oid)
+		FROM pg_constraint WHERE connamespace = $1::text::regnamespace`,
+	`SELECT 'enum ' || t.typname || ' (' || string_agg(e.enumlabel, ', ' ORDER BY e.enumsortorder) || ')'
+		FROM pg_type t JOIN pg_enum e ON e.enumtypid = t.oid
+		WHERE t.typnamespace = $1::text::regnamespace GROUP BY t.typname`,
+	`SELECT 'sequence ' || sequence_name FROM information_schema.sequences WHERE sequence_schema = $1`,
+}
+
+type catalogSnapshot map[string]bool
+
+func takeCatalogSnapshot(db *sql.DB, schema string) (catalogSnapshot, error) {
+	snapshot := catalogSnapshot{}
+	for _, query := range catalogQueries {
+		rows, err := db.Query(query, schema)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var object string
+			if err := rows.Scan(&object); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			snapshot[object] = true
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+	}
+	return snapshot, nil
+}
+
+// diffCatalogSnapshots returns the objects present only after a migration
+// round trip (leftovers) and those present only before it (missing).
+func diffCatalogSnapshots(before, after catalogSnapshot) (leftover []string, missing []string) {
+	for object := range after {
+		if !before[object] {
+			leftover = append(leftover, object)
+		}
+	}
+	for object := range before {
+		if !after[object] {
+			missing = append(missing, object)
+		}
+	}
+	sort.Strings(leftover)
+	sort.Strings(missing)
+	return leftover, missing
+}
+
+func describeLeftover(object string) string {
+	if strings.HasPrefix(object, "enum ") {
+		return "leftover enum type " + strings.TrimPrefix(object, "enum ")
+	}
+	return "leftover " + object
+}
+
+func TestDiffCatalogSnapshots(t *testing.T) {
+	t.Parallel()
+
+	before := catalogSnapshot{"table users": true, "index users_email_idx": true}
+	after := catalogSnapshot{"table users": true, "enum discount_type (percentage, flat_amount)": true}
+
+	leftover, missing := diffCatalogSnapshots(before, after)
+	assert.Equal(t, []string{"enum discount_type (percentage, flat_amount)"}, leftover)
+	assert.Equal(t, []string{"index users_email_idx"}, missing)
+	assert.Equal(t, "leftover enum type discount_type (percentage, flat_amount)", describeLeftover(leftover[0]))
+
+	leftover, missing = diffCatalogSnapshots(before, before)
+	assert.Empty(t, leftover)
+	assert.Empty(t, missing)
+}
+
+func migrationBasenames(t *testing.T) []string {
+	t.Helper()
+	entries, err := fs.ReadDir(FS, ".")
+	require.NoError(t, err)
+
+	var basenames []string
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".up.sql") {
+			basenames = append(basenames, strings.TrimSuffix(e.Name(), ".up.sql"))
+		}
+	}
+	sort.Strings(basenames)
+	return basenames
+}
+
+func execMigrationFile(db *sql.DB, filename string) error {
+	body, err := fs.ReadFile(FS, filename)
+	if err != nil {
+		return err
+	}
+	if _, err = db.Exec(string(body)); err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	return nil
+}
+
+// TestMigrationRoundTrips applies every migration in order and, after each
+// one, rolls it back and compares the catalog with the one taken before it
+// was applied. Any difference means the down migration does not exactly
+// reverse the up migration.
+func TestMigrationRoundTrips(t *testing.T) {
+	dbURL := os.Getenv(roundTripDatabaseKey)
+	if dbURL == "" {
+		t.Skipf("set %s to a throwaway postgres database to run the migration round trip suite", roundTripDatabaseKey)
+	}
+
+	db, err := sql.Open("postgres", dbURL)
+	require.NoError(t, err)
+	defer db.Close()
+	// search_path is per connection, so every statement has to share one
+	db.SetMaxOpenConns(1)
+
+	schema := fmt.Sprintf("migration_round_trip_%d", time.Now().UnixNano())
+	_, err = db.Exec(fmt.Sprintf("CREATE SCHEMA %s", schema))
+	require.NoError(t, err)
+	defer db.Exec(fmt.Sprintf("DROP SCHEMA %s CASCADE", schema))
+	_, err = db.Exec(fmt.Sprintf("SET search_path TO %s", schema))
+	require.NoError(t, err)
+
+	for _, basename := range migrationBasenames(t) {
+		before, err := takeCatalogSnapshot(db, schema)
+		require.NoError(t, err)
+
+		require.NoError(t, execMigrationFile(db, basename+".up.sql"))
+		require.NoError(t, execMigrationFile(db, basename+".down.sql"))
+
+		after, err := takeCatalogSnapshot(db, schema)
+		require.NoError(t, err)
+
+		leftover, missing := diffCatalogSnapshots(before, after)
+		for _, object := range leftover {
+			t.Errorf("%s.down.sql: %s", basename, describeLeftover(object))
+		}
+		for _, object := range missing {
+			t.Errorf("%s.down.sql: removed %s, which existed before the up migration", basename, object)
+		}
+
+		// reapply so the next migration starts from the schema it expects
+		require.NoError(t, execMigrationFile(db, basename+".up.sql"))
+	}
+}
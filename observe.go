@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dairycart/dairymodels/v1"
+
+	"github.com/lib/pq"
+)
+
+// QueryObservation describes a single call to one of the storer methods.
+type QueryObservation struct {
+	// Operation is the storer method name, e.g. GetProductList.
+	Operation string
+	Start     time.Time
+	Duration  time.Duration
+	// Rows is how many rows the method returned or changed, as far as its
+	// results tell. Failed calls and methods that only return an error
+	// report zero.
+	Rows int64
+	Err  error
+	// ErrorClass groups Err into a small set suitable for metric labels: ""
+	// on success, "not_found", "connection", "timeout", the postgres error
+	// class name (e.g. "integrity_constraint_violation") or "other".
+	ErrorClass string
+}
+
+// QueryObserver is notified after every storer method returns. Observers run
+// synchronously on the calling goroutine, so they should be quick.
+//
+// Observations carry their start time and duration rather than being
+// reported at the start and end of a call, so an OpenTelemetry adapter
+// creates spans with trace.WithTimestamp(o.Start) and ends them with
+// trace.WithTimestamp(o.Start.Add(o.Duration)), and a Prometheus adapter
+// observes o.Duration.Seconds() on a histogram labelled by o.Operation and
+// o.ErrorClass.
+type QueryObserver interface {
+	ObserveQuery(QueryObservation)
+}
+
+// QueryObserverFunc adapts a function to a QueryObserver.
+type QueryObserverFunc func(QueryObservation)
+
+func (f QueryObserverFunc) ObserveQuery(o QueryObservation) {
+	f(o)
+}
+
+// AddQueryObserver registers o to be notified of every storer call. It is not
+// safe to call while the storer is in use.
+func (pg *postgres) AddQueryObserver(o QueryObserver) {
+	pg.observers = append(pg.observers, o)
+}
+
+// QueryRecorder is a QueryObserver that keeps every observation in memory.
+// It is meant for tests.
+type QueryRecorder struct {
+	mu           sync.Mutex
+	observations []QueryObservation
+}
+
+func (r *QueryRecorder) ObserveQuery(o QueryObservation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.observations = append(r.observations, o)
+}
+
+// Observations returns a copy of everything recorded so far, oldest first.
+func (r *QueryRecorder) Observations() []QueryObservation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]QueryObservation(nil), r.observations...)
+}
+
+// SlowQueryLogConfig controls the built-in slow query log, which reports
+// storer calls that take at least Threshold to the logger. A zero Threshold
+// turns it off. String and byte arguments are never logged, since they may
+// hold passwords, tokens or personal data.
+type SlowQueryLogConfig struct {
+	Threshold time.Duration
+}
+
+// DefaultSlowQueryLogConfig leaves the slow query log off, so nothing is
+// logged until a threshold is configured.
+var DefaultSlowQueryLogConfig = SlowQueryLogConfig{}
+
+// SetSlowQueryLogConfig replaces the slow query log settings.
+func (pg *postgres) SetSlowQueryLogConfig(cfg SlowQueryLogConfig) {
+	pg.slowQueryLog = cfg
+}
+
+// classifyError maps err to one of the QueryObservation error classes.
+func classifyError(err error) string {
+	if err == nil {
+		return ""
+	}
+	if err == sql.ErrNoRows {
+		return "not_found"
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	if isConnectionError(err) {
+		return "connection"
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		if pqErr.Code.Name() == "query_canceled" {
+			return "timeout"
+		}
+		return pqErr.Code.Class().Name()
+	}
+	return "other"
+}
+
+// resultRows estimates how many rows a method touched from a pointer to its
+// first result: the length of a returned slice, the count of affected rows
+// for an int64, and otherwise one for any non-zero value.
+func resultRows(result interface{}) int64 {
+	if result == nil {
+		return 0
+	}
+	v := reflect.ValueOf(result)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return 0
+	}
+	v = v.Elem()
+	switch {
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8:
+		return int64(v.Len())
+	case v.Kind() == reflect.Int64 && v.Type() == reflect.TypeOf(int64(0)):
+		return v.Int()
+	case v.IsZero(), v.Kind() == reflect.Slice && v.Len() == 0:
+		return 0
+	}
+	return 1
+}
+
+// redactArgument formats a storer method argument for the slow query log.
+// Identifiers, numbers, times and query filters are shown as is; anything
+// that could carry user data only has its type shown.
+func redactArgument(arg interface{}) string {
+	switch v := arg.(type) {
+	case nil:
+		return "nil"
+	case bool, int, int64, uint, uint64, time.Time, time.Duration, []uint64:
+		return fmt.Sprint(v)
+	case *models.QueryFilter:
+		if v == nil {
+			return "nil"
+		}
+		return fmt.Sprintf("%+v", *v)
+	case []byte:
+		return fmt.Sprintf("[redacted %d bytes]", len(v))
+	}
+	return fmt.Sprintf("[redacted %T]", arg)
+}
+
+// observe is deferred at the top of every storer method. err and result point
+// at the method's named results, so they hold the returned values by the time
+// it runs.
+func (pg *postgres) observe(operation string, start time.Time, err *error, result interface{}, args ...interface{}) {
+	if len(pg.observers) == 0 && pg.slowQueryLog.Threshold == 0 {
+		return
+	}
+
+	o := QueryObservation{
+		Operation:  operation,
+		Start:      start,
+		Duration:   time.Since(start),
+		Err:        *err,
+		ErrorClass: classifyError(*err),
+	}
+	if o.Err == nil {
+		o.Rows = resultRows(result)
+	}
+	for _, observer := range pg.observers {
+		observer.ObserveQuery(o)
+	}
+
+	if pg.slowQueryLog.Threshold > 0 && o.Duration >= pg.slowQueryLog.Threshold {
+		redacted := make([]string, len(args))
+		for i, arg := range args {
+			redacted[i] = redactArgument(arg)
+		}
+		pg.logger.Printf("slow query: %s(%s) took %s, rows=%d error=%q", operation, strings.Join(redacted, ", "), o.Duration, o.Rows, o.ErrorClass)
+	}
+}
@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dairycart/dairymodels/v1"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestSetSlowQueryLogConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("normal usecase", func(*testing.T) {
+		client := NewPostgres()
+		expected := SlowQueryLogConfig{Threshold: time.Millisecond}
+		client.SetSlowQueryLogConfig(expected)
+		assert.Equal(t, expected, client.slowQueryLog)
+	})
+
+	t.Run("off by default", func(*testing.T) {
+		client := NewPostgres()
+		assert.Equal(t, time.Duration(0), client.slowQueryLog.Threshold)
+	})
+}
+
+func TestClassifyError(t *testing.T) {
+	t.Parallel()
+
+	examples := map[string]error{
+		"":                               nil,
+		"not_found":                      sql.ErrNoRows,
+		"timeout":                        fmt.Errorf("wrapped: %w", context.DeadlineExceeded),
+		"connection":                     &net.OpError{Op: "dial", Err: errors.New("connection refused")},
+		"integrity_constraint_violation": &pq.Error{Code: "23505"},
+		"other":                          errors.New("pineapple on pizza"),
+	}
+	for expected, err := range examples {
+		assert.Equal(t, expected, classifyError(err), "unexpected class for %v", err)
+	}
+	assert.Equal(t, "timeout", classifyError(&pq.Error{Code: "57014"}))
+}
+
+func TestResultRows(t *testing.T) {
+	t.Parallel()
+
+	list := []models.Webhook{{ID: 1}, {ID: 2}}
+	var empty []models.Webhook
+	webhook := &models.Webhook{}
+	var missing *models.Webhook
+	affected := int64(7)
+	exists := true
+	archivedOn := time.Now()
+	export := []byte(`{}`)
+
+	assert.Equal(t, int64(2), resultRows(&list))
+	assert.Equal(t, int64(0), resultRows(&empty))
+	assert.Equal(t, int64(1), resultRows(&webhook))
+	assert.Equal(t, int64(0), resultRows(&missing))
+	assert.Equal(t, int64(7), resultRows(&affected))
+	assert.Equal(t, int64(1), resultRows(&exists))
+	assert.Equal(t, int64(1), resultRows(&archivedOn))
+	assert.Equal(t, int64(1), resultRows(&export))
+	assert.Equal(t, int64(0), resultRows(nil))
+}
+
+func TestRedactArgument(t *testing.T) {
+	t.Parallel()
+
+	examples := map[string]interface{}{
+		"123":                            uint64(123),
+		"true":                           true,
+		"5s":                             5 * time.Second,
+		"[1 2]":                          []uint64{1, 2},
+		"nil":                            nil,
+		"[redacted string]":              "hunter2",
+		"[redacted 7 bytes]":             []byte("hunter2"),
+		"[redacted []string]":            []string{"product_created"},
+		"[redacted *models.User]":        &models.User{Password: "hunter2"},
+		"{Page:1 Limit:25 CreatedAfter:": &models.QueryFilter{Limit: 25, Page: 1},
+	}
+	for expected, arg := range examples {
+		assert.True(t, strings.HasPrefix(redactArgument(arg), expected), "unexpected formatting of %T: %s", arg, redactArgument(arg))
+	}
+}
+
+func TestObserve(t *testing.T) {
+	t.Parallel()
+
+	t.Run("records storer calls", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+
+		client := NewPostgres()
+		recorder := &QueryRecorder{}
+		client.AddQueryObserver(recorder)
+		exampleQF := &models.QueryFilter{Limit: 25, Page: 1}
+
+		setWebhookListReadQueryExpectation(t, mock, exampleQF, &models.Webhook{ID: 1}, nil, nil)
+		list, err := client.GetWebhookList(mockDB, exampleQF)
+		require.NoError(t, err)
+
+		setWebhookListReadQueryExpectation(t, mock, exampleQF, &models.Webhook{ID: 1}, nil, errors.New("pineapple on pizza"))
+		_, err = client.GetWebhookList(mockDB, exampleQF)
+		require.NotNil(t, err)
+
+		observations := recorder.Observations()
+		require.Len(t, observations, 2)
+		assert.Equal(t, "GetWebhookList", observations[0].Operation)
+		assert.Equal(t, int64(len(list)), observations[0].Rows)
+		assert.Empty(t, observations[0].ErrorClass)
+		assert.False(t, observations[0].Start.IsZero())
+
+		assert.Equal(t, "GetWebhookList", observations[1].Operation)
+		assert.Equal(t, int64(0), observations[1].Rows)
+		assert.Equal(t, "other", observations[1].ErrorClass)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("logs slow calls with redacted arguments", func(*testing.T) {
+		client := NewPostgres()
+		l := &recordingLogger{}
+		client.SetLogger(l)
+		client.SetSlowQueryLogConfig(SlowQueryLogConfig{Threshold: time.Second})
+
+		var err error
+		user := &models.User{}
+		client.observe("GetUserByUsername", time.Now(), &err, &user, "frank")
+		require.Empty(t, l.messages, "fast calls should not be logged")
+
+		client.observe("GetUserByUsername", time.Now().Add(-2*time.Second), &err, &user, "frank")
+		require.Len(t, l.messages, 1)
+		assert.Contains(t, l.messages[0], "slow query: GetUserByUsername([redacted string]) took")
+		assert.NotContains(t, l.messages[0], "frank")
+	})
+
+	t.Run("with slow query log off", func(*testing.T) {
+		client := NewPostgres()
+		l := &recordingLogger{}
+		client.SetLogger(l)
+
+		var err error
+		client.observe("GetProductList", time.Now().Add(-time.Hour), &err, nil)
+		assert.Empty(t, l.messages)
+	})
+}
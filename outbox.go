@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"time"
+)
+
+// OutboxRetryConfig controls how failed outbox event deliveries are retried.
+// The delay before the next attempt doubles with every failure, starting at
+// BaseDelay and never exceeding MaxDelay. Events that fail MaxAttempts times
+// are archived and no longer claimed, as are events whose worker let the
+// lease on its last attempt expire.
+type OutboxRetryConfig struct {
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	MaxAttempts uint64
+}
+
+var DefaultOutboxRetryConfig = OutboxRetryConfig{
+	BaseDelay:   30 * time.Second,
+	MaxDelay:    time.Hour,
+	MaxAttempts: 10,
+}
+
+// SetOutboxRetryConfig replaces the outbox retry policy. Zero fields keep
+// their default value.
+func (pg *postgres) SetOutboxRetryConfig(cfg OutboxRetryConfig) {
+	if cfg.BaseDelay == 0 {
+		cfg.BaseDelay = DefaultOutboxRetryConfig.BaseDelay
+	}
+	if cfg.MaxDelay == 0 {
+		cfg.MaxDelay = DefaultOutboxRetryConfig.MaxDelay
+	}
+	if cfg.MaxAttempts == 0 {
+		cfg.MaxAttempts = DefaultOutboxRetryConfig.MaxAttempts
+	}
+	pg.outboxRetry = cfg
+}
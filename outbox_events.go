@@ -0,0 +1,333 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/dairycart/dairycart/storage/database"
+	"github.com/dairycart/dairymodels/v1"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const outboxEventAppendQuery = `
+    INSERT INTO outbox_events
+        (
+            event_type, payload
+        )
+    VALUES
+        (
+            $1, $2
+        )
+    RETURNING
+        id, created_on;
+`
+
+func (pg *postgres) AppendOutboxEvent(db database.Querier, eventType string, payload []byte) (newID uint64, createdOn time.Time, err error) {
+	defer pg.observe("AppendOutboxEvent", time.Now(), &err, &newID, eventType, payload)
+	err = db.QueryRow(outboxEventAppendQuery, eventType, payload).Scan(&newID, &createdOn)
+	return newID, createdOn, err
+}
+
+const outboxEventClaimQuery = `
+    WITH exhausted AS (
+        UPDATE outbox_events
+        SET
+            archived_on = NOW(),
+            updated_on = NOW()
+        WHERE delivered_on IS NULL
+        AND archived_on IS NULL
+        AND attempts >= $3
+        AND (claimed_until IS NULL OR claimed_until < NOW())
+    )
+    UPDATE outbox_events
+    SET
+        claimed_until = NOW() + ($2::float8 * interval '1 second'),
+        attempts = attempts + 1,
+        updated_on = NOW()
+    WHERE id IN (
+        SELECT id
+        FROM outbox_events
+        WHERE delivered_on IS NULL
+        AND archived_on IS NULL
+        AND attempts < $3
+        AND available_on <= NOW()
+        AND (claimed_until IS NULL OR claimed_until < NOW())
+        ORDER BY available_on, id
+        LIMIT $1
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING
+        id,
+        event_type,
+        payload,
+        attempts,
+        available_on,
+        claimed_until,
+        last_error,
+        delivered_on,
+        created_on,
+        updated_on,
+        archived_on
+`
+
+func (pg *postgres) ClaimOutboxEvents(db database.Querier, limit uint64, lease time.Duration) (result []models.OutboxEvent, err error) {
+	defer pg.observe("ClaimOutboxEvents", time.Now(), &err, &result, limit, lease)
+	var list []models.OutboxEvent
+
+	rows, err := db.Query(outboxEventClaimQuery, limit, lease.Seconds(), pg.outboxRetry.MaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var o models.OutboxEvent
+		err := rows.Scan(
+			&o.ID,
+			&o.EventType,
+			&o.Payload,
+			&o.Attempts,
+			&o.AvailableOn,
+			&o.ClaimedUntil,
+			&o.LastError,
+			&o.DeliveredOn,
+			&o.CreatedOn,
+			&o.UpdatedOn,
+			&o.ArchivedOn,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, o)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+const outboxEventDeliveredQuery = `
+    UPDATE outbox_events
+    SET
+        delivered_on = NOW(),
+        claimed_until = NULL,
+        updated_on = NOW()
+    WHERE id = $1
+    AND delivered_on IS NULL
+    AND attempts = $2
+    AND claimed_until > NOW()
+    RETURNING delivered_on
+`
+
+// MarkOutboxEventDelivered records that the event claimed for the given
+// attempt was delivered. Every claim counts as a new attempt, so attempt tells
+// this claim apart from later ones. It returns sql.ErrNoRows once the lease
+// has expired or another worker has claimed the event since, and leaves the
+// event alone.
+func (pg *postgres) MarkOutboxEventDelivered(db database.Querier, id uint64, attempt uint32) (t time.Time, err error) {
+	defer pg.observe("MarkOutboxEventDelivered", time.Now(), &err, &t, id, attempt)
+	err = db.QueryRow(outboxEventDeliveredQuery, id, attempt).Scan(&t)
+	return t, err
+}
+
+const outboxEventFailedQuery = `
+    UPDATE outbox_events
+    SET
+        last_error = $2,
+        claimed_until = NULL,
+        available_on = NOW() + LEAST($3::float8 * power(2, GREATEST(attempts - 1, 0)), $4::float8) * interval '1 second',
+        archived_on = CASE WHEN attempts >= $5 THEN NOW() ELSE archived_on END,
+        updated_on = NOW()
+    WHERE id = $1
+    AND delivered_on IS NULL
+    AND attempts = $6
+    AND claimed_until > NOW()
+    RETURNING available_on
+`
+
+// MarkOutboxEventFailed schedules the event claimed for the given attempt
+// to be retried, with the same claim check as MarkOutboxEventDelivered.
+func (pg *postgres) MarkOutboxEventFailed(db database.Querier, id uint64, attempt uint32, reason string) (retryOn time.Time, err error) {
+	defer pg.observe("MarkOutboxEventFailed", time.Now(), &err, &retryOn, id, attempt, reason)
+	err = db.QueryRow(outboxEventFailedQuery, id, reason, pg.outboxRetry.BaseDelay.Seconds(), pg.outboxRetry.MaxDelay.Seconds(), pg.outboxRetry.MaxAttempts, attempt).Scan(&retryOn)
+	return retryOn, err
+}
+
+const outboxEventExistenceQuery = `SELECT EXISTS(SELECT id FROM outbox_events WHERE id = $1 and archived_on IS NULL);`
+
+func (pg *postgres) OutboxEventExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("OutboxEventExists", time.Now(), &err, &result, id)
+	var exists string
+
+	err = db.QueryRow(outboxEventExistenceQuery, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return exists == "true", err
+}
+
+const outboxEventSelectionQuery = `
+    SELECT
+        id,
+        event_type,
+        payload,
+        attempts,
+        available_on,
+        claimed_until,
+        last_error,
+        delivered_on,
+        created_on,
+        updated_on,
+        archived_on
+    FROM
+        outbox_events
+    WHERE
+        archived_on is null
+    AND
+        id = $1
+`
+
+func (pg *postgres) GetOutboxEvent(db database.Querier, id uint64) (result *models.OutboxEvent, err error) {
+	defer pg.observe("GetOutboxEvent", time.Now(), &err, &result, id)
+	o := &models.OutboxEvent{}
+
+	err = db.QueryRow(outboxEventSelectionQuery, id).Scan(&o.ID, &o.EventType, &o.Payload, &o.Attempts, &o.AvailableOn, &o.ClaimedUntil, &o.LastError, &o.DeliveredOn, &o.CreatedOn, &o.UpdatedOn, &o.ArchivedOn)
+
+	return o, err
+}
+
+func buildOutboxEventListRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	sqlBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	queryBuilder := sqlBuilder.
+		Select(
+			"id",
+			"event_type",
+			"payload",
+			"attempts",
+			"available_on",
+			"claimed_until",
+			"last_error",
+			"delivered_on",
+			"created_on",
+			"updated_on",
+			"archived_on",
+		).
+		From("outbox_events")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, true).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetOutboxEventList(db database.Querier, qf *models.QueryFilter) (result []models.OutboxEvent, err error) {
+	defer pg.observe("GetOutboxEventList", time.Now(), &err, &result, qf)
+	var list []models.OutboxEvent
+	query, args := buildOutboxEventListRetrievalQuery(qf)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var o models.OutboxEvent
+		err := rows.Scan(
+			&o.ID,
+			&o.EventType,
+			&o.Payload,
+			&o.Attempts,
+			&o.AvailableOn,
+			&o.ClaimedUntil,
+			&o.LastError,
+			&o.DeliveredOn,
+			&o.CreatedOn,
+			&o.UpdatedOn,
+			&o.ArchivedOn,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, o)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+func buildOutboxEventCountRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("count(id)").
+		From("outbox_events")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, false).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetOutboxEventCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetOutboxEventCount", time.Now(), &err, nil, qf)
+	var count uint64
+	query, args := buildOutboxEventCountRetrievalQuery(qf)
+	err = db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+const outboxEventCreationQuery = `
+    INSERT INTO outbox_events
+        (
+            event_type, payload, attempts, available_on, claimed_until, last_error, delivered_on
+        )
+    VALUES
+        (
+            $1, $2, $3, $4, $5, $6, $7
+        )
+    RETURNING
+        id, created_on;
+`
+
+func (pg *postgres) CreateOutboxEvent(db database.Querier, nu *models.OutboxEvent) (createdID uint64, createdOn time.Time, err error) {
+	defer pg.observe("CreateOutboxEvent", time.Now(), &err, &createdID, nu)
+	err = db.QueryRow(outboxEventCreationQuery, &nu.EventType, &nu.Payload, &nu.Attempts, &nu.AvailableOn, &nu.ClaimedUntil, &nu.LastError, &nu.DeliveredOn).Scan(&createdID, &createdOn)
+	return createdID, createdOn, err
+}
+
+const outboxEventUpdateQuery = `
+    UPDATE outbox_events
+    SET
+        event_type = $1,
+        payload = $2,
+        attempts = $3,
+        available_on = $4,
+        claimed_until = $5,
+        last_error = $6,
+        delivered_on = $7,
+        updated_on = NOW()
+    WHERE id = $8
+    RETURNING updated_on;
+`
+
+func (pg *postgres) UpdateOutboxEvent(db database.Querier, updated *models.OutboxEvent) (result time.Time, err error) {
+	defer pg.observe("UpdateOutboxEvent", time.Now(), &err, &result, updated)
+	var t time.Time
+	err = db.QueryRow(outboxEventUpdateQuery, &updated.EventType, &updated.Payload, &updated.Attempts, &updated.AvailableOn, &updated.ClaimedUntil, &updated.LastError, &updated.DeliveredOn, &updated.ID).Scan(&t)
+	return t, err
+}
+
+const outboxEventDeletionQuery = `
+    UPDATE outbox_events
+    SET archived_on = NOW()
+    WHERE id = $1
+    RETURNING archived_on
+`
+
+func (pg *postgres) DeleteOutboxEvent(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteOutboxEvent", time.Now(), &err, &t, id)
+	err = db.QueryRow(outboxEventDeletionQuery, id).Scan(&t)
+	return t, err
+}
@@ -0,0 +1,590 @@
+package postgres
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	// internal dependencies
+	"github.com/dairycart/dairymodels/v1"
+
+	// external dependencies
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestAppendOutboxEvent(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+	query := formatQueryForSQLMock(outboxEventAppendQuery)
+	exampleEventType := "product_created"
+	examplePayload := []byte(`{"id": 1}`)
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		expectedID := uint64(1)
+		expectedCreatedOn := buildTestTime(t)
+		mock.ExpectQuery(query).
+			WithArgs(exampleEventType, examplePayload).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_on"}).AddRow(expectedID, expectedCreatedOn))
+		actualID, actualCreatedOn, err := client.AppendOutboxEvent(mockDB, exampleEventType, examplePayload)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedID, actualID, "expected and actual IDs don't match")
+		assert.Equal(t, expectedCreatedOn, actualCreatedOn, "expected creation time did not match actual creation time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with a database error", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(exampleEventType, examplePayload).
+			WillReturnError(errors.New("pineapple on pizza"))
+		_, _, err := client.AppendOutboxEvent(mockDB, exampleEventType, examplePayload)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setOutboxEventClaimQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, limit uint64, lease time.Duration, example *models.OutboxEvent, rowErr error, err error) {
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"event_type",
+		"payload",
+		"attempts",
+		"available_on",
+		"claimed_until",
+		"last_error",
+		"delivered_on",
+		"created_on",
+		"updated_on",
+		"archived_on",
+	}).AddRow(
+		example.ID,
+		example.EventType,
+		example.Payload,
+		example.Attempts,
+		example.AvailableOn,
+		example.ClaimedUntil,
+		example.LastError,
+		example.DeliveredOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).AddRow(
+		example.ID,
+		example.EventType,
+		example.Payload,
+		example.Attempts,
+		example.AvailableOn,
+		example.ClaimedUntil,
+		example.LastError,
+		example.DeliveredOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).RowError(1, rowErr)
+
+	mock.ExpectQuery(formatQueryForSQLMock(outboxEventClaimQuery)).
+		WithArgs(limit, lease.Seconds(), DefaultOutboxRetryConfig.MaxAttempts).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestClaimOutboxEvents(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+
+	exampleLimit := uint64(10)
+	exampleLease := time.Minute
+	example := &models.OutboxEvent{EventType: "product_created", Payload: []byte(`{}`)}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setOutboxEventClaimQueryExpectation(t, mock, exampleLimit, exampleLease, example, nil, nil)
+		actual, err := client.ClaimOutboxEvents(mockDB, exampleLimit, exampleLease)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, actual, "claim method should not return an empty slice")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error executing query", func(t *testing.T) {
+		setOutboxEventClaimQueryExpectation(t, mock, exampleLimit, exampleLease, example, nil, errors.New("pineapple on pizza"))
+		actual, err := client.ClaimOutboxEvents(mockDB, exampleLimit, exampleLease)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with with row errors", func(t *testing.T) {
+		setOutboxEventClaimQueryExpectation(t, mock, exampleLimit, exampleLease, example, errors.New("pineapple on pizza"), nil)
+		actual, err := client.ClaimOutboxEvents(mockDB, exampleLimit, exampleLease)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func TestMarkOutboxEventDelivered(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+	exampleID := uint64(1)
+	exampleAttempt := uint32(2)
+	query := formatQueryForSQLMock(outboxEventDeliveredQuery)
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		expected := buildTestTime(t)
+		mock.ExpectQuery(query).
+			WithArgs(exampleID, exampleAttempt).
+			WillReturnRows(sqlmock.NewRows([]string{"delivered_on"}).AddRow(expected))
+		actual, err := client.MarkOutboxEventDelivered(mockDB, exampleID, exampleAttempt)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected delivery time did not match actual delivery time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("after losing the claim", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(exampleID, exampleAttempt).
+			WillReturnError(sql.ErrNoRows)
+		_, err := client.MarkOutboxEventDelivered(mockDB, exampleID, exampleAttempt)
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func TestMarkOutboxEventFailed(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+	client.SetOutboxRetryConfig(OutboxRetryConfig{BaseDelay: time.Second, MaxDelay: time.Minute, MaxAttempts: 5})
+	exampleID := uint64(1)
+	exampleAttempt := uint32(2)
+	exampleReason := "receiver returned 503"
+	query := formatQueryForSQLMock(outboxEventFailedQuery)
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		expected := buildTestTime(t)
+		mock.ExpectQuery(query).
+			WithArgs(exampleID, exampleReason, float64(1), float64(60), uint64(5), exampleAttempt).
+			WillReturnRows(sqlmock.NewRows([]string{"available_on"}).AddRow(expected))
+		actual, err := client.MarkOutboxEventFailed(mockDB, exampleID, exampleAttempt, exampleReason)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected retry time did not match actual retry time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("after losing the claim", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(exampleID, exampleReason, float64(1), float64(60), uint64(5), exampleAttempt).
+			WillReturnError(sql.ErrNoRows)
+		_, err := client.MarkOutboxEventFailed(mockDB, exampleID, exampleAttempt, exampleReason)
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func TestOutboxEventCompletionRequiresTheClaim(t *testing.T) {
+	t.Parallel()
+
+	for name, query := range map[string]string{"delivered": outboxEventDeliveredQuery, "failed": outboxEventFailedQuery} {
+		assert.Contains(t, query, "AND claimed_until > NOW()", "%s should not apply once the lease has expired", name)
+		assert.Regexp(t, `AND attempts = \$\d+`, query, "%s should not apply to a later claim", name)
+	}
+}
+
+func setOutboxEventExistenceQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, shouldExist bool, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(outboxEventExistenceQuery)
+
+	mock.ExpectQuery(query).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(strconv.FormatBool(shouldExist))).
+		WillReturnError(err)
+}
+
+func TestOutboxEventExists(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	client := NewPostgres()
+
+	t.Run("existing", func(t *testing.T) {
+		setOutboxEventExistenceQueryExpectation(t, mock, exampleID, true, nil)
+		actual, err := client.OutboxEventExists(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.True(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with no rows found", func(t *testing.T) {
+		setOutboxEventExistenceQueryExpectation(t, mock, exampleID, true, sql.ErrNoRows)
+		actual, err := client.OutboxEventExists(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.False(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with a database error", func(t *testing.T) {
+		setOutboxEventExistenceQueryExpectation(t, mock, exampleID, true, errors.New("pineapple on pizza"))
+		actual, err := client.OutboxEventExists(mockDB, exampleID)
+
+		assert.NotNil(t, err)
+		assert.False(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setOutboxEventReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, toReturn *models.OutboxEvent, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(outboxEventSelectionQuery)
+
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"event_type",
+		"payload",
+		"attempts",
+		"available_on",
+		"claimed_until",
+		"last_error",
+		"delivered_on",
+		"created_on",
+		"updated_on",
+		"archived_on",
+	}).AddRow(
+		toReturn.ID,
+		toReturn.EventType,
+		toReturn.Payload,
+		toReturn.Attempts,
+		toReturn.AvailableOn,
+		toReturn.ClaimedUntil,
+		toReturn.LastError,
+		toReturn.DeliveredOn,
+		toReturn.CreatedOn,
+		toReturn.UpdatedOn,
+		toReturn.ArchivedOn,
+	)
+	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestGetOutboxEvent(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	expected := &models.OutboxEvent{ID: exampleID}
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setOutboxEventReadQueryExpectation(t, mock, exampleID, expected, nil)
+		actual, err := client.GetOutboxEvent(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected outboxevent did not match actual outboxevent")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setOutboxEventListReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, qf *models.QueryFilter, example *models.OutboxEvent, rowErr error, err error) {
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"event_type",
+		"payload",
+		"attempts",
+		"available_on",
+		"claimed_until",
+		"last_error",
+		"delivered_on",
+		"created_on",
+		"updated_on",
+		"archived_on",
+	}).AddRow(
+		example.ID,
+		example.EventType,
+		example.Payload,
+		example.Attempts,
+		example.AvailableOn,
+		example.ClaimedUntil,
+		example.LastError,
+		example.DeliveredOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).AddRow(
+		example.ID,
+		example.EventType,
+		example.Payload,
+		example.Attempts,
+		example.AvailableOn,
+		example.ClaimedUntil,
+		example.LastError,
+		example.DeliveredOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).AddRow(
+		example.ID,
+		example.EventType,
+		example.Payload,
+		example.Attempts,
+		example.AvailableOn,
+		example.ClaimedUntil,
+		example.LastError,
+		example.DeliveredOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).RowError(1, rowErr)
+
+	query, _ := buildOutboxEventListRetrievalQuery(qf)
+
+	mock.ExpectQuery(formatQueryForSQLMock(query)).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestGetOutboxEventList(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	example := &models.OutboxEvent{ID: exampleID}
+	client := NewPostgres()
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setOutboxEventListReadQueryExpectation(t, mock, exampleQF, example, nil, nil)
+		actual, err := client.GetOutboxEventList(mockDB, exampleQF)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, actual, "list retrieval method should not return an empty slice")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error executing query", func(t *testing.T) {
+		setOutboxEventListReadQueryExpectation(t, mock, exampleQF, example, nil, errors.New("pineapple on pizza"))
+		actual, err := client.GetOutboxEventList(mockDB, exampleQF)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error scanning values", func(t *testing.T) {
+		exampleRows := sqlmock.NewRows([]string{"things"}).AddRow("stuff")
+		query, _ := buildOutboxEventListRetrievalQuery(exampleQF)
+		mock.ExpectQuery(formatQueryForSQLMock(query)).
+			WillReturnRows(exampleRows)
+
+		actual, err := client.GetOutboxEventList(mockDB, exampleQF)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with with row errors", func(t *testing.T) {
+		setOutboxEventListReadQueryExpectation(t, mock, exampleQF, example, errors.New("pineapple on pizza"), nil)
+		actual, err := client.GetOutboxEventList(mockDB, exampleQF)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func TestBuildOutboxEventCountRetrievalQuery(t *testing.T) {
+	t.Parallel()
+
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+	expected := `SELECT count(id) FROM outbox_events WHERE archived_on IS NULL LIMIT 25`
+	actual, _ := buildOutboxEventCountRetrievalQuery(exampleQF)
+
+	assert.Equal(t, expected, actual, "expected and actual queries should match")
+}
+
+func setOutboxEventCountRetrievalQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, qf *models.QueryFilter, count uint64, err error) {
+	t.Helper()
+	query, args := buildOutboxEventCountRetrievalQuery(qf)
+	query = formatQueryForSQLMock(query)
+
+	var argsToExpect []driver.Value
+	for _, x := range args {
+		argsToExpect = append(argsToExpect, x)
+	}
+
+	exampleRow := sqlmock.NewRows([]string{"count"}).AddRow(count)
+	mock.ExpectQuery(query).WithArgs(argsToExpect...).WillReturnRows(exampleRow).WillReturnError(err)
+}
+
+func TestGetOutboxEventCount(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+	expected := uint64(123)
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setOutboxEventCountRetrievalQueryExpectation(t, mock, exampleQF, expected, nil)
+		actual, err := client.GetOutboxEventCount(mockDB, exampleQF)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "count retrieval method should return the expected value")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setOutboxEventCreationQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toCreate *models.OutboxEvent, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(outboxEventCreationQuery)
+	tt := buildTestTime(t)
+	exampleRows := sqlmock.NewRows([]string{"id", "created_on"}).AddRow(uint64(1), tt)
+	mock.ExpectQuery(query).
+		WithArgs(
+			toCreate.EventType,
+			toCreate.Payload,
+			toCreate.Attempts,
+			toCreate.AvailableOn,
+			toCreate.ClaimedUntil,
+			toCreate.LastError,
+			toCreate.DeliveredOn,
+		).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestCreateOutboxEvent(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	expectedID := uint64(1)
+	exampleInput := &models.OutboxEvent{ID: expectedID}
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setOutboxEventCreationQueryExpectation(t, mock, exampleInput, nil)
+		expectedCreatedOn := buildTestTime(t)
+
+		actualID, actualCreatedOn, err := client.CreateOutboxEvent(mockDB, exampleInput)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedID, actualID, "expected and actual IDs don't match")
+		assert.Equal(t, expectedCreatedOn, actualCreatedOn, "expected creation time did not match actual creation time")
+
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setOutboxEventUpdateQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toUpdate *models.OutboxEvent, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(outboxEventUpdateQuery)
+	exampleRows := sqlmock.NewRows([]string{"updated_on"}).AddRow(buildTestTime(t))
+	mock.ExpectQuery(query).
+		WithArgs(
+			toUpdate.EventType,
+			toUpdate.Payload,
+			toUpdate.Attempts,
+			toUpdate.AvailableOn,
+			toUpdate.ClaimedUntil,
+			toUpdate.LastError,
+			toUpdate.DeliveredOn,
+			toUpdate.ID,
+		).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestUpdateOutboxEventByID(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleInput := &models.OutboxEvent{ID: uint64(1)}
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setOutboxEventUpdateQueryExpectation(t, mock, exampleInput, nil)
+		expected := buildTestTime(t)
+		actual, err := client.UpdateOutboxEvent(mockDB, exampleInput)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected deletion time did not match actual deletion time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setOutboxEventDeletionQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(outboxEventDeletionQuery)
+	exampleRows := sqlmock.NewRows([]string{"archived_on"}).AddRow(buildTestTime(t))
+	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestDeleteOutboxEventByID(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setOutboxEventDeletionQueryExpectation(t, mock, exampleID, nil)
+		expected := buildTestTime(t)
+		actual, err := client.DeleteOutboxEvent(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected deletion time did not match actual deletion time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		setOutboxEventDeletionQueryExpectation(t, mock, exampleID, nil)
+		expected := buildTestTime(t)
+		tx, err := mockDB.Begin()
+		assert.NoError(t, err, "no error should be returned setting up a transaction in the mock DB")
+		actual, err := client.DeleteOutboxEvent(tx, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected deletion time did not match actual deletion time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
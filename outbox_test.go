@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetOutboxRetryConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("normal usecase", func(*testing.T) {
+		client := NewPostgres()
+		expected := OutboxRetryConfig{
+			BaseDelay:   time.Second,
+			MaxDelay:    time.Minute,
+			MaxAttempts: 3,
+		}
+		client.SetOutboxRetryConfig(expected)
+		assert.Equal(t, expected, client.outboxRetry)
+	})
+
+	t.Run("with zero values", func(*testing.T) {
+		client := NewPostgres()
+		client.SetOutboxRetryConfig(OutboxRetryConfig{MaxAttempts: 3})
+
+		expected := DefaultOutboxRetryConfig
+		expected.MaxAttempts = 3
+		assert.Equal(t, expected, client.outboxRetry)
+	})
+}
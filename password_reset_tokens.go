@@ -10,12 +10,13 @@ import (
 	"github.com/Masterminds/squirrel"
 )
 
-const passwordResetTokenExistenceQueryByUserID = `SELECT EXISTS(SELECT id FROM password_reset_tokens WHERE user_id = $1 AND NOW() < expires_on);`
+const passwordResetTokenExistenceQueryByUserID = `SELECT EXISTS(SELECT id FROM password_reset_tokens WHERE user_id = $1 AND NOW() < expires_on AND password_reset_on IS NULL AND invalidated_on IS NULL);`
 
-func (pg *postgres) PasswordResetTokenForUserIDExists(db database.Querier, id uint64) (bool, error) {
+func (pg *postgres) PasswordResetTokenForUserIDExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("PasswordResetTokenForUserIDExists", time.Now(), &err, &result, id)
 	var exists string
 
-	err := db.QueryRow(passwordResetTokenExistenceQueryByUserID, id).Scan(&exists)
+	err = db.QueryRow(passwordResetTokenExistenceQueryByUserID, id).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -25,12 +26,13 @@ func (pg *postgres) PasswordResetTokenForUserIDExists(db database.Querier, id ui
 	return exists == "true", err
 }
 
-const passwordResetTokenExistenceQueryByToken = `SELECT EXISTS(SELECT id FROM password_reset_tokens WHERE token = $1 AND NOW() < expires_on);`
+const passwordResetTokenExistenceQueryByToken = `SELECT EXISTS(SELECT id FROM password_reset_tokens WHERE token_hash = $1 AND NOW() < expires_on AND password_reset_on IS NULL AND invalidated_on IS NULL);`
 
-func (pg *postgres) PasswordResetTokenWithTokenExists(db database.Querier, token string) (bool, error) {
+func (pg *postgres) PasswordResetTokenWithTokenExists(db database.Querier, tokenHash string) (result bool, err error) {
+	defer pg.observe("PasswordResetTokenWithTokenExists", time.Now(), &err, &result, tokenHash)
 	var exists string
 
-	err := db.QueryRow(passwordResetTokenExistenceQueryByToken, token).Scan(&exists)
+	err = db.QueryRow(passwordResetTokenExistenceQueryByToken, tokenHash).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -40,12 +42,50 @@ func (pg *postgres) PasswordResetTokenWithTokenExists(db database.Querier, token
 	return exists == "true", err
 }
 
+const passwordResetTokenConsumptionQuery = `
+    WITH consumed AS (
+        UPDATE password_reset_tokens
+        SET password_reset_on = NOW()
+        WHERE token_hash = $1
+        AND NOW() < expires_on
+        AND password_reset_on IS NULL
+        AND invalidated_on IS NULL
+        RETURNING id, user_id, password_reset_on
+    ), invalidated AS (
+        UPDATE password_reset_tokens
+        SET invalidated_on = NOW()
+        WHERE user_id IN (SELECT user_id FROM consumed)
+        AND id NOT IN (SELECT id FROM consumed)
+        AND password_reset_on IS NULL
+        AND invalidated_on IS NULL
+    )
+    SELECT user_id, password_reset_on FROM consumed
+`
+
+func (pg *postgres) ConsumePasswordResetToken(db database.Querier, tokenHash string) (userID uint64, resetOn time.Time, err error) {
+	defer pg.observe("ConsumePasswordResetToken", time.Now(), &err, &userID, tokenHash)
+	err = db.QueryRow(passwordResetTokenConsumptionQuery, tokenHash).Scan(&userID, &resetOn)
+	return userID, resetOn, err
+}
+
+const passwordResetTokenExpiredDeletionQuery = `DELETE FROM password_reset_tokens WHERE expires_on < NOW()`
+
+func (pg *postgres) DeleteExpiredPasswordResetTokens(db database.Querier) (result int64, err error) {
+	defer pg.observe("DeleteExpiredPasswordResetTokens", time.Now(), &err, &result)
+	res, err := db.Exec(passwordResetTokenExpiredDeletionQuery)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
 const passwordResetTokenExistenceQuery = `SELECT EXISTS(SELECT id FROM password_reset_tokens WHERE id = $1 and archived_on IS NULL);`
 
-func (pg *postgres) PasswordResetTokenExists(db database.Querier, id uint64) (bool, error) {
+func (pg *postgres) PasswordResetTokenExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("PasswordResetTokenExists", time.Now(), &err, &result, id)
 	var exists string
 
-	err := db.QueryRow(passwordResetTokenExistenceQuery, id).Scan(&exists)
+	err = db.QueryRow(passwordResetTokenExistenceQuery, id).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -59,10 +99,11 @@ const passwordResetTokenSelectionQuery = `
     SELECT
         id,
         user_id,
-        token,
+        token_hash,
         created_on,
         expires_on,
-        password_reset_on
+        password_reset_on,
+        invalidated_on
     FROM
         password_reset_tokens
     WHERE
@@ -71,10 +112,11 @@ const passwordResetTokenSelectionQuery = `
         id = $1
 `
 
-func (pg *postgres) GetPasswordResetToken(db database.Querier, id uint64) (*models.PasswordResetToken, error) {
+func (pg *postgres) GetPasswordResetToken(db database.Querier, id uint64) (result *models.PasswordResetToken, err error) {
+	defer pg.observe("GetPasswordResetToken", time.Now(), &err, &result, id)
 	p := &models.PasswordResetToken{}
 
-	err := db.QueryRow(passwordResetTokenSelectionQuery, id).Scan(&p.ID, &p.UserID, &p.Token, &p.CreatedOn, &p.ExpiresOn, &p.PasswordResetOn)
+	err = db.QueryRow(passwordResetTokenSelectionQuery, id).Scan(&p.ID, &p.UserID, &p.TokenHash, &p.CreatedOn, &p.ExpiresOn, &p.PasswordResetOn, &p.InvalidatedOn)
 
 	return p, err
 }
@@ -85,10 +127,11 @@ func buildPasswordResetTokenListRetrievalQuery(qf *models.QueryFilter) (string,
 		Select(
 			"id",
 			"user_id",
-			"token",
+			"token_hash",
 			"created_on",
 			"expires_on",
 			"password_reset_on",
+			"invalidated_on",
 		).
 		From("password_reset_tokens")
 
@@ -96,7 +139,8 @@ func buildPasswordResetTokenListRetrievalQuery(qf *models.QueryFilter) (string,
 	return query, args
 }
 
-func (pg *postgres) GetPasswordResetTokenList(db database.Querier, qf *models.QueryFilter) ([]models.PasswordResetToken, error) {
+func (pg *postgres) GetPasswordResetTokenList(db database.Querier, qf *models.QueryFilter) (result []models.PasswordResetToken, err error) {
+	defer pg.observe("GetPasswordResetTokenList", time.Now(), &err, &result, qf)
 	var list []models.PasswordResetToken
 	query, args := buildPasswordResetTokenListRetrievalQuery(qf)
 
@@ -110,10 +154,11 @@ func (pg *postgres) GetPasswordResetTokenList(db database.Querier, qf *models.Qu
 		err := rows.Scan(
 			&p.ID,
 			&p.UserID,
-			&p.Token,
+			&p.TokenHash,
 			&p.CreatedOn,
 			&p.ExpiresOn,
 			&p.PasswordResetOn,
+			&p.InvalidatedOn,
 		)
 		if err != nil {
 			return nil, err
@@ -137,28 +182,30 @@ func buildPasswordResetTokenCountRetrievalQuery(qf *models.QueryFilter) (string,
 	return query, args
 }
 
-func (pg *postgres) GetPasswordResetTokenCount(db database.Querier, qf *models.QueryFilter) (uint64, error) {
+func (pg *postgres) GetPasswordResetTokenCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetPasswordResetTokenCount", time.Now(), &err, nil, qf)
 	var count uint64
 	query, args := buildPasswordResetTokenCountRetrievalQuery(qf)
-	err := db.QueryRow(query, args...).Scan(&count)
+	err = db.QueryRow(query, args...).Scan(&count)
 	return count, err
 }
 
 const passwordResetTokenCreationQuery = `
     INSERT INTO password_reset_tokens
         (
-            user_id, token, expires_on, password_reset_on
+            user_id, token_hash, expires_on, password_reset_on, invalidated_on
         )
     VALUES
         (
-            $1, $2, $3, $4
+            $1, $2, $3, $4, $5
         )
     RETURNING
         id, created_on;
 `
 
 func (pg *postgres) CreatePasswordResetToken(db database.Querier, nu *models.PasswordResetToken) (createdID uint64, createdOn time.Time, err error) {
-	err = db.QueryRow(passwordResetTokenCreationQuery, &nu.UserID, &nu.Token, &nu.ExpiresOn, &nu.PasswordResetOn).Scan(&createdID, &createdOn)
+	defer pg.observe("CreatePasswordResetToken", time.Now(), &err, &createdID, nu)
+	err = db.QueryRow(passwordResetTokenCreationQuery, &nu.UserID, &nu.TokenHash, &nu.ExpiresOn, &nu.PasswordResetOn, &nu.InvalidatedOn).Scan(&createdID, &createdOn)
 	return createdID, createdOn, err
 }
 
@@ -166,17 +213,19 @@ const passwordResetTokenUpdateQuery = `
     UPDATE password_reset_tokens
     SET
         user_id = $1,
-        token = $2,
+        token_hash = $2,
         expires_on = $3,
         password_reset_on = $4,
+        invalidated_on = $5,
         updated_on = NOW()
-    WHERE id = $5
+    WHERE id = $6
     RETURNING updated_on;
 `
 
-func (pg *postgres) UpdatePasswordResetToken(db database.Querier, updated *models.PasswordResetToken) (time.Time, error) {
+func (pg *postgres) UpdatePasswordResetToken(db database.Querier, updated *models.PasswordResetToken) (result time.Time, err error) {
+	defer pg.observe("UpdatePasswordResetToken", time.Now(), &err, &result, updated)
 	var t time.Time
-	err := db.QueryRow(passwordResetTokenUpdateQuery, &updated.UserID, &updated.Token, &updated.ExpiresOn, &updated.PasswordResetOn, &updated.ID).Scan(&t)
+	err = db.QueryRow(passwordResetTokenUpdateQuery, &updated.UserID, &updated.TokenHash, &updated.ExpiresOn, &updated.PasswordResetOn, &updated.InvalidatedOn, &updated.ID).Scan(&t)
 	return t, err
 }
 
@@ -188,6 +237,7 @@ const passwordResetTokenDeletionQuery = `
 `
 
 func (pg *postgres) DeletePasswordResetToken(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeletePasswordResetToken", time.Now(), &err, &t, id)
 	err = db.QueryRow(passwordResetTokenDeletionQuery, id).Scan(&t)
 	return t, err
 }
@@ -61,12 +61,12 @@ func TestPasswordResetTokenForUserIDExists(t *testing.T) {
 	})
 }
 
-func setPasswordResetTokenExistenceQueryByTokenExpectation(t *testing.T, mock sqlmock.Sqlmock, token string, shouldExist bool, err error) {
+func setPasswordResetTokenExistenceQueryByTokenExpectation(t *testing.T, mock sqlmock.Sqlmock, tokenHash string, shouldExist bool, err error) {
 	t.Helper()
 	query := formatQueryForSQLMock(passwordResetTokenExistenceQueryByToken)
 
 	mock.ExpectQuery(query).
-		WithArgs(token).
+		WithArgs(tokenHash).
 		WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(strconv.FormatBool(shouldExist))).
 		WillReturnError(err)
 }
@@ -107,6 +107,69 @@ func TestPasswordResetTokenWithTokenExists(t *testing.T) {
 	})
 }
 
+func setPasswordResetTokenConsumptionQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, tokenHash string, userID uint64, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(passwordResetTokenConsumptionQuery)
+	exampleRows := sqlmock.NewRows([]string{"user_id", "password_reset_on"}).AddRow(userID, buildTestTime(t))
+	mock.ExpectQuery(query).WithArgs(tokenHash).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestConsumePasswordResetToken(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleTokenHash := "deadbeef"
+	exampleUserID := uint64(1)
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setPasswordResetTokenConsumptionQueryExpectation(t, mock, exampleTokenHash, exampleUserID, nil)
+		expected := buildTestTime(t)
+		actualUserID, actualResetOn, err := client.ConsumePasswordResetToken(mockDB, exampleTokenHash)
+
+		assert.NoError(t, err)
+		assert.Equal(t, exampleUserID, actualUserID, "expected user ID did not match actual user ID")
+		assert.Equal(t, expected, actualResetOn, "expected reset time did not match actual reset time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with an expired or already used token", func(t *testing.T) {
+		setPasswordResetTokenConsumptionQueryExpectation(t, mock, exampleTokenHash, exampleUserID, sql.ErrNoRows)
+		_, _, err := client.ConsumePasswordResetToken(mockDB, exampleTokenHash)
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func TestDeleteExpiredPasswordResetTokens(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+	query := formatQueryForSQLMock(passwordResetTokenExpiredDeletionQuery)
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 3))
+		actual, err := client.DeleteExpiredPasswordResetTokens(mockDB)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), actual, "expected deleted token count did not match actual count")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with a database error", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnError(errors.New("pineapple on pizza"))
+		actual, err := client.DeleteExpiredPasswordResetTokens(mockDB)
+
+		assert.NotNil(t, err)
+		assert.Zero(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
 func setPasswordResetTokenExistenceQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, shouldExist bool, err error) {
 	t.Helper()
 	query := formatQueryForSQLMock(passwordResetTokenExistenceQuery)
@@ -160,17 +223,19 @@ func setPasswordResetTokenReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmoc
 	exampleRows := sqlmock.NewRows([]string{
 		"id",
 		"user_id",
-		"token",
+		"token_hash",
 		"created_on",
 		"expires_on",
 		"password_reset_on",
+		"invalidated_on",
 	}).AddRow(
 		toReturn.ID,
 		toReturn.UserID,
-		toReturn.Token,
+		toReturn.TokenHash,
 		toReturn.CreatedOn,
 		toReturn.ExpiresOn,
 		toReturn.PasswordResetOn,
+		toReturn.InvalidatedOn,
 	)
 	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
 }
@@ -198,31 +263,35 @@ func setPasswordResetTokenListReadQueryExpectation(t *testing.T, mock sqlmock.Sq
 	exampleRows := sqlmock.NewRows([]string{
 		"id",
 		"user_id",
-		"token",
+		"token_hash",
 		"created_on",
 		"expires_on",
 		"password_reset_on",
+		"invalidated_on",
 	}).AddRow(
 		example.ID,
 		example.UserID,
-		example.Token,
+		example.TokenHash,
 		example.CreatedOn,
 		example.ExpiresOn,
 		example.PasswordResetOn,
+		example.InvalidatedOn,
 	).AddRow(
 		example.ID,
 		example.UserID,
-		example.Token,
+		example.TokenHash,
 		example.CreatedOn,
 		example.ExpiresOn,
 		example.PasswordResetOn,
+		example.InvalidatedOn,
 	).AddRow(
 		example.ID,
 		example.UserID,
-		example.Token,
+		example.TokenHash,
 		example.CreatedOn,
 		example.ExpiresOn,
 		example.PasswordResetOn,
+		example.InvalidatedOn,
 	).RowError(1, rowErr)
 
 	query, _ := buildPasswordResetTokenListRetrievalQuery(qf)
@@ -343,9 +412,10 @@ func setPasswordResetTokenCreationQueryExpectation(t *testing.T, mock sqlmock.Sq
 	mock.ExpectQuery(query).
 		WithArgs(
 			toCreate.UserID,
-			toCreate.Token,
+			toCreate.TokenHash,
 			toCreate.ExpiresOn,
 			toCreate.PasswordResetOn,
+			toCreate.InvalidatedOn,
 		).
 		WillReturnRows(exampleRows).
 		WillReturnError(err)
@@ -381,9 +451,10 @@ func setPasswordResetTokenUpdateQueryExpectation(t *testing.T, mock sqlmock.Sqlm
 	mock.ExpectQuery(query).
 		WithArgs(
 			toUpdate.UserID,
-			toUpdate.Token,
+			toUpdate.TokenHash,
 			toUpdate.ExpiresOn,
 			toUpdate.PasswordResetOn,
+			toUpdate.InvalidatedOn,
 			toUpdate.ID,
 		).
 		WillReturnRows(exampleRows).
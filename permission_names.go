@@ -0,0 +1,10 @@
+package postgres
+
+// Permission names seeded by the roles and permissions migration.
+const (
+	PermissionWriteProducts  = "products:write"
+	PermissionWriteInventory = "inventory:write"
+	PermissionWriteDiscounts = "discounts:write"
+	PermissionManageWebhooks = "webhooks:manage"
+	PermissionManageUsers    = "users:manage"
+)
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/dairycart/dairycart/storage/database"
+	"github.com/dairycart/dairymodels/v1"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const permissionExistenceQuery = `SELECT EXISTS(SELECT id FROM permissions WHERE id = $1 and archived_on IS NULL);`
+
+func (pg *postgres) PermissionExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("PermissionExists", time.Now(), &err, &result, id)
+	var exists string
+
+	err = db.QueryRow(permissionExistenceQuery, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return exists == "true", err
+}
+
+const permissionSelectionQuery = `
+    SELECT
+        id,
+        name,
+        description,
+        created_on,
+        updated_on,
+        archived_on
+    FROM
+        permissions
+    WHERE
+        archived_on is null
+    AND
+        id = $1
+`
+
+func (pg *postgres) GetPermission(db database.Querier, id uint64) (result *models.Permission, err error) {
+	defer pg.observe("GetPermission", time.Now(), &err, &result, id)
+	p := &models.Permission{}
+
+	err = db.QueryRow(permissionSelectionQuery, id).Scan(&p.ID, &p.Name, &p.Description, &p.CreatedOn, &p.UpdatedOn, &p.ArchivedOn)
+
+	return p, err
+}
+
+func buildPermissionListRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	sqlBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	queryBuilder := sqlBuilder.
+		Select(
+			"id",
+			"name",
+			"description",
+			"created_on",
+			"updated_on",
+			"archived_on",
+		).
+		From("permissions")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, true).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetPermissionList(db database.Querier, qf *models.QueryFilter) (result []models.Permission, err error) {
+	defer pg.observe("GetPermissionList", time.Now(), &err, &result, qf)
+	var list []models.Permission
+	query, args := buildPermissionListRetrievalQuery(qf)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p models.Permission
+		err := rows.Scan(
+			&p.ID,
+			&p.Name,
+			&p.Description,
+			&p.CreatedOn,
+			&p.UpdatedOn,
+			&p.ArchivedOn,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+func buildPermissionCountRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("count(id)").
+		From("permissions")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, false).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetPermissionCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetPermissionCount", time.Now(), &err, nil, qf)
+	var count uint64
+	query, args := buildPermissionCountRetrievalQuery(qf)
+	err = db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+const permissionCreationQuery = `
+    INSERT INTO permissions
+        (
+            name, description
+        )
+    VALUES
+        (
+            $1, $2
+        )
+    RETURNING
+        id, created_on;
+`
+
+func (pg *postgres) CreatePermission(db database.Querier, nu *models.Permission) (createdID uint64, createdOn time.Time, err error) {
+	defer pg.observe("CreatePermission", time.Now(), &err, &createdID, nu)
+	err = db.QueryRow(permissionCreationQuery, &nu.Name, &nu.Description).Scan(&createdID, &createdOn)
+	return createdID, createdOn, err
+}
+
+const permissionUpdateQuery = `
+    UPDATE permissions
+    SET
+        name = $1,
+        description = $2,
+        updated_on = NOW()
+    WHERE id = $3
+    RETURNING updated_on;
+`
+
+func (pg *postgres) UpdatePermission(db database.Querier, updated *models.Permission) (result time.Time, err error) {
+	defer pg.observe("UpdatePermission", time.Now(), &err, &result, updated)
+	var t time.Time
+	err = db.QueryRow(permissionUpdateQuery, &updated.Name, &updated.Description, &updated.ID).Scan(&t)
+	return t, err
+}
+
+const permissionDeletionQuery = `
+    UPDATE permissions
+    SET archived_on = NOW()
+    WHERE id = $1
+    RETURNING archived_on
+`
+
+func (pg *postgres) DeletePermission(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeletePermission", time.Now(), &err, &t, id)
+	err = db.QueryRow(permissionDeletionQuery, id).Scan(&t)
+	return t, err
+}
@@ -0,0 +1,353 @@
+package postgres
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strconv"
+	"testing"
+
+	// internal dependencies
+	"github.com/dairycart/dairymodels/v1"
+
+	// external dependencies
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func setPermissionExistenceQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, shouldExist bool, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(permissionExistenceQuery)
+
+	mock.ExpectQuery(query).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(strconv.FormatBool(shouldExist))).
+		WillReturnError(err)
+}
+
+func TestPermissionExists(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	client := NewPostgres()
+
+	t.Run("existing", func(t *testing.T) {
+		setPermissionExistenceQueryExpectation(t, mock, exampleID, true, nil)
+		actual, err := client.PermissionExists(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.True(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with no rows found", func(t *testing.T) {
+		setPermissionExistenceQueryExpectation(t, mock, exampleID, true, sql.ErrNoRows)
+		actual, err := client.PermissionExists(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.False(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with a database error", func(t *testing.T) {
+		setPermissionExistenceQueryExpectation(t, mock, exampleID, true, errors.New("pineapple on pizza"))
+		actual, err := client.PermissionExists(mockDB, exampleID)
+
+		assert.NotNil(t, err)
+		assert.False(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setPermissionReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, toReturn *models.Permission, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(permissionSelectionQuery)
+
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"name",
+		"description",
+		"created_on",
+		"updated_on",
+		"archived_on",
+	}).AddRow(
+		toReturn.ID,
+		toReturn.Name,
+		toReturn.Description,
+		toReturn.CreatedOn,
+		toReturn.UpdatedOn,
+		toReturn.ArchivedOn,
+	)
+	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestGetPermission(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	expected := &models.Permission{ID: exampleID}
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setPermissionReadQueryExpectation(t, mock, exampleID, expected, nil)
+		actual, err := client.GetPermission(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected permission did not match actual permission")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setPermissionListReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, qf *models.QueryFilter, example *models.Permission, rowErr error, err error) {
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"name",
+		"description",
+		"created_on",
+		"updated_on",
+		"archived_on",
+	}).AddRow(
+		example.ID,
+		example.Name,
+		example.Description,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).AddRow(
+		example.ID,
+		example.Name,
+		example.Description,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).AddRow(
+		example.ID,
+		example.Name,
+		example.Description,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).RowError(1, rowErr)
+
+	query, _ := buildPermissionListRetrievalQuery(qf)
+
+	mock.ExpectQuery(formatQueryForSQLMock(query)).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestGetPermissionList(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	example := &models.Permission{ID: exampleID}
+	client := NewPostgres()
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setPermissionListReadQueryExpectation(t, mock, exampleQF, example, nil, nil)
+		actual, err := client.GetPermissionList(mockDB, exampleQF)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, actual, "list retrieval method should not return an empty slice")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error executing query", func(t *testing.T) {
+		setPermissionListReadQueryExpectation(t, mock, exampleQF, example, nil, errors.New("pineapple on pizza"))
+		actual, err := client.GetPermissionList(mockDB, exampleQF)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error scanning values", func(t *testing.T) {
+		exampleRows := sqlmock.NewRows([]string{"things"}).AddRow("stuff")
+		query, _ := buildPermissionListRetrievalQuery(exampleQF)
+		mock.ExpectQuery(formatQueryForSQLMock(query)).
+			WillReturnRows(exampleRows)
+
+		actual, err := client.GetPermissionList(mockDB, exampleQF)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with with row errors", func(t *testing.T) {
+		setPermissionListReadQueryExpectation(t, mock, exampleQF, example, errors.New("pineapple on pizza"), nil)
+		actual, err := client.GetPermissionList(mockDB, exampleQF)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func TestBuildPermissionCountRetrievalQuery(t *testing.T) {
+	t.Parallel()
+
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+	expected := `SELECT count(id) FROM permissions WHERE archived_on IS NULL LIMIT 25`
+	actual, _ := buildPermissionCountRetrievalQuery(exampleQF)
+
+	assert.Equal(t, expected, actual, "expected and actual queries should match")
+}
+
+func setPermissionCountRetrievalQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, qf *models.QueryFilter, count uint64, err error) {
+	t.Helper()
+	query, args := buildPermissionCountRetrievalQuery(qf)
+	query = formatQueryForSQLMock(query)
+
+	var argsToExpect []driver.Value
+	for _, x := range args {
+		argsToExpect = append(argsToExpect, x)
+	}
+
+	exampleRow := sqlmock.NewRows([]string{"count"}).AddRow(count)
+	mock.ExpectQuery(query).WithArgs(argsToExpect...).WillReturnRows(exampleRow).WillReturnError(err)
+}
+
+func TestGetPermissionCount(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+	expected := uint64(123)
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setPermissionCountRetrievalQueryExpectation(t, mock, exampleQF, expected, nil)
+		actual, err := client.GetPermissionCount(mockDB, exampleQF)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "count retrieval method should return the expected value")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setPermissionCreationQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toCreate *models.Permission, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(permissionCreationQuery)
+	tt := buildTestTime(t)
+	exampleRows := sqlmock.NewRows([]string{"id", "created_on"}).AddRow(uint64(1), tt)
+	mock.ExpectQuery(query).
+		WithArgs(
+			toCreate.Name,
+			toCreate.Description,
+		).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestCreatePermission(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	expectedID := uint64(1)
+	exampleInput := &models.Permission{ID: expectedID}
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setPermissionCreationQueryExpectation(t, mock, exampleInput, nil)
+		expectedCreatedOn := buildTestTime(t)
+
+		actualID, actualCreatedOn, err := client.CreatePermission(mockDB, exampleInput)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedID, actualID, "expected and actual IDs don't match")
+		assert.Equal(t, expectedCreatedOn, actualCreatedOn, "expected creation time did not match actual creation time")
+
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setPermissionUpdateQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toUpdate *models.Permission, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(permissionUpdateQuery)
+	exampleRows := sqlmock.NewRows([]string{"updated_on"}).AddRow(buildTestTime(t))
+	mock.ExpectQuery(query).
+		WithArgs(
+			toUpdate.Name,
+			toUpdate.Description,
+			toUpdate.ID,
+		).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestUpdatePermissionByID(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleInput := &models.Permission{ID: uint64(1)}
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setPermissionUpdateQueryExpectation(t, mock, exampleInput, nil)
+		expected := buildTestTime(t)
+		actual, err := client.UpdatePermission(mockDB, exampleInput)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected deletion time did not match actual deletion time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setPermissionDeletionQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(permissionDeletionQuery)
+	exampleRows := sqlmock.NewRows([]string{"archived_on"}).AddRow(buildTestTime(t))
+	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestDeletePermissionByID(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setPermissionDeletionQueryExpectation(t, mock, exampleID, nil)
+		expected := buildTestTime(t)
+		actual, err := client.DeletePermission(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected deletion time did not match actual deletion time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		setPermissionDeletionQueryExpectation(t, mock, exampleID, nil)
+		expected := buildTestTime(t)
+		tx, err := mockDB.Begin()
+		assert.NoError(t, err, "no error should be returned setting up a transaction in the mock DB")
+		actual, err := client.DeletePermission(tx, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected deletion time did not match actual deletion time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
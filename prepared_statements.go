@@ -0,0 +1,270 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dairycart/dairycart/storage/database"
+
+	"github.com/lib/pq"
+)
+
+// preparedQueries are the static hot-path queries worth preparing. Queries
+// built at call time, like the filtered list queries, always run unprepared.
+var preparedQueries = []string{
+	productSelectionQuery,
+	productQueryBySKU,
+	productExistenceQuery,
+	productWithSKUExistenceQuery,
+	productQueryByProductRootID,
+	productRootSelectionQuery,
+	productOptionQueryByProductRootID,
+	productOptionValueRetrievalQueryByOptionID,
+	productImageQueryByProductID,
+	productPriceQueryByProductIDAndCurrency,
+	userSelectionQuery,
+	userQueryByUsername,
+	userQueryByEmail,
+	userCredentialsQueryByUsername,
+	userWithUsernameExistenceQuery,
+	userWithEmailExistenceQuery,
+}
+
+// prepareRetryAfter is how long a query that failed to prepare runs
+// unprepared before preparing it is tried again.
+const prepareRetryAfter = time.Minute
+
+var (
+	_ database.Querier = (*PreparedStatementCache)(nil)
+	_ ContextQuerier   = (*PreparedStatementCache)(nil)
+)
+
+// PreparedStatementCache is a Querier over a connection pool that runs the
+// hot-path queries as prepared statements, so postgres parses and plans them
+// once per connection rather than on every call. Everything else is passed
+// straight to the pool.
+type PreparedStatementCache struct {
+	db       *sql.DB
+	eligible map[string]bool
+	pg       *postgres
+	now      func() time.Time
+
+	mu         sync.Mutex
+	stmts      map[string]*sql.Stmt
+	failedOn   map[string]time.Time
+	generation uint64
+	closed     bool
+}
+
+// PrepareStatements opts db into prepared statements for the hot-path
+// queries. Pass the returned cache wherever a Querier is expected. Statements
+// are prepared on first use, and are discarded and prepared again after
+// every migration run by this postgres instance. Close the cache once it is
+// no longer used.
+func (pg *postgres) PrepareStatements(db *sql.DB) *PreparedStatementCache {
+	c := &PreparedStatementCache{
+		db:       db,
+		eligible: map[string]bool{},
+		pg:       pg,
+		now:      time.Now,
+		stmts:    map[string]*sql.Stmt{},
+		failedOn: map[string]time.Time{},
+	}
+	for _, query := range preparedQueries {
+		c.eligible[query] = true
+	}
+
+	pg.statementCachesMu.Lock()
+	pg.statementCaches = append(pg.statementCaches, c)
+	pg.statementCachesMu.Unlock()
+	return c
+}
+
+// invalidatePreparedStatements discards every statement prepared through
+// PrepareStatements, since a schema change can leave their plans stale.
+func (pg *postgres) invalidatePreparedStatements() {
+	pg.statementCachesMu.Lock()
+	defer pg.statementCachesMu.Unlock()
+	for _, c := range pg.statementCaches {
+		c.Invalidate()
+	}
+}
+
+// forgetStatementCache stops invalidating c after migrations.
+func (pg *postgres) forgetStatementCache(c *PreparedStatementCache) {
+	pg.statementCachesMu.Lock()
+	defer pg.statementCachesMu.Unlock()
+	for i, cache := range pg.statementCaches {
+		if cache == c {
+			pg.statementCaches = append(pg.statementCaches[:i], pg.statementCaches[i+1:]...)
+			return
+		}
+	}
+}
+
+// stmt returns the prepared statement for query, preparing it if needed. It
+// returns nil for queries that should not be prepared, or when preparing
+// fails, in which case the caller runs the query unprepared and reports
+// whatever error postgres gives. A failed prepare is not tried again for
+// prepareRetryAfter.
+//
+// Preparing takes a round trip, so it happens without holding the lock. If
+// another call prepared the same query meanwhile, its statement is kept, and
+// a statement prepared across an invalidation is not kept at all.
+func (c *PreparedStatementCache) stmt(ctx context.Context, query string) *sql.Stmt {
+	if !c.eligible[query] {
+		return nil
+	}
+
+	c.mu.Lock()
+	if s, ok := c.stmts[query]; ok {
+		c.mu.Unlock()
+		return s
+	}
+	failedOn, failed := c.failedOn[query]
+	if c.closed || (failed && c.now().Sub(failedOn) < prepareRetryAfter) {
+		c.mu.Unlock()
+		return nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	s, err := c.db.PrepareContext(ctx, query)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		c.failedOn[query] = c.now()
+		return nil
+	}
+	delete(c.failedOn, query)
+	if c.closed || c.generation != generation {
+		s.Close()
+		return nil
+	}
+	if existing, ok := c.stmts[query]; ok {
+		s.Close()
+		return existing
+	}
+	c.stmts[query] = s
+	return s
+}
+
+// discard closes and forgets the statement for query, if it is still s.
+func (c *PreparedStatementCache) discard(query string, s *sql.Stmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stmts[query] == s {
+		delete(c.stmts, query)
+		s.Close()
+	}
+}
+
+// Invalidate closes every prepared statement. They are prepared again the
+// next time they are used. Call it after changing the schema outside of
+// Migrate.
+func (c *PreparedStatementCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	for query, s := range c.stmts {
+		s.Close()
+		delete(c.stmts, query)
+	}
+	for query := range c.failedOn {
+		delete(c.failedOn, query)
+	}
+}
+
+// Close closes every prepared statement and stops the cache from preparing
+// more, so queries still made through it run unprepared. The underlying pool
+// stays open.
+func (c *PreparedStatementCache) Close() error {
+	c.pg.forgetStatementCache(c)
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	c.Invalidate()
+	return nil
+}
+
+// isStalePlanError reports whether err means a prepared statement no longer
+// matches the schema, or no longer exists on the server, and has to be
+// prepared again.
+func isStalePlanError(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	if !ok {
+		return false
+	}
+	switch pqErr.Code.Name() {
+	case "invalid_sql_statement_name":
+		return true
+	case "feature_not_supported":
+		return strings.Contains(pqErr.Message, "cached plan must not change result type")
+	}
+	return false
+}
+
+// statementConn returns a connection of its own for queries the cache runs
+// unprepared. Prepared statements pick their connection themselves, so it
+// returns nil for those.
+func (c *PreparedStatementCache) statementConn(ctx context.Context, query string) (*sql.Conn, error) {
+	if c.eligible[query] {
+		return nil, nil
+	}
+	return c.db.Conn(ctx)
+}
+
+func (c *PreparedStatementCache) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.ExecContext(context.Background(), query, args...)
+}
+
+func (c *PreparedStatementCache) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.QueryContext(context.Background(), query, args...)
+}
+
+func (c *PreparedStatementCache) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.QueryRowContext(context.Background(), query, args...)
+}
+
+func (c *PreparedStatementCache) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	s := c.stmt(ctx, query)
+	if s == nil {
+		return c.db.ExecContext(ctx, query, args...)
+	}
+	res, err := s.ExecContext(ctx, args...)
+	if isStalePlanError(err) {
+		c.discard(query, s)
+		return c.db.ExecContext(ctx, query, args...)
+	}
+	return res, err
+}
+
+func (c *PreparedStatementCache) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	s := c.stmt(ctx, query)
+	if s == nil {
+		return c.db.QueryContext(ctx, query, args...)
+	}
+	rows, err := s.QueryContext(ctx, args...)
+	if isStalePlanError(err) {
+		c.discard(query, s)
+		return c.db.QueryContext(ctx, query, args...)
+	}
+	return rows, err
+}
+
+func (c *PreparedStatementCache) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	s := c.stmt(ctx, query)
+	if s == nil {
+		return c.db.QueryRowContext(ctx, query, args...)
+	}
+	row := s.QueryRowContext(ctx, args...)
+	if isStalePlanError(row.Err()) {
+		c.discard(query, s)
+		return c.db.QueryRowContext(ctx, query, args...)
+	}
+	return row
+}
@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dairycart/dairycart/storage/database"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func credentialRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "username", "password", "salt", "password_last_changed_on"}).
+		AddRow(1, "username", "password", []byte("salt"), nil)
+}
+
+func TestPreparedStatementCache(t *testing.T) {
+	t.Parallel()
+	query := formatQueryForSQLMock(userCredentialsQueryByUsername)
+
+	t.Run("prepares hot-path queries once", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+		client := NewPostgres()
+		cache := client.PrepareStatements(mockDB)
+
+		prepared := mock.ExpectPrepare(query)
+		prepared.ExpectQuery().WithArgs("username").WillReturnRows(credentialRows())
+		prepared.ExpectQuery().WithArgs("username").WillReturnRows(credentialRows())
+
+		for i := 0; i < 2; i++ {
+			_, err = client.GetUserCredentials(cache, "username")
+			assert.NoError(t, err)
+		}
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("runs other queries unprepared", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+		client := NewPostgres()
+		cache := client.PrepareStatements(mockDB)
+
+		mock.ExpectExec(formatQueryForSQLMock(userSessionRevocationQueryByUserID)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+
+		_, err = client.RevokeUserSessionsForUser(cache, 1)
+		assert.NoError(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("prepares again after a stale plan", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+		client := NewPostgres()
+		cache := client.PrepareStatements(mockDB)
+
+		stale := &pq.Error{Code: "0A000", Message: "cached plan must not change result type"}
+		prepared := mock.ExpectPrepare(query)
+		prepared.ExpectQuery().WithArgs("username").WillReturnError(stale)
+		prepared.WillBeClosed()
+		mock.ExpectQuery(query).WithArgs("username").WillReturnRows(credentialRows())
+		mock.ExpectPrepare(query).ExpectQuery().WithArgs("username").WillReturnRows(credentialRows())
+
+		for i := 0; i < 2; i++ {
+			_, err = client.GetUserCredentials(cache, "username")
+			assert.NoError(t, err)
+		}
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error preparing", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+		client := NewPostgres()
+		cache := client.PrepareStatements(mockDB)
+
+		mock.ExpectPrepare(query).WillReturnError(errors.New("pineapple on pizza"))
+		mock.ExpectQuery(query).WithArgs("username").WillReturnError(errors.New("pineapple on pizza"))
+
+		_, err = client.GetUserCredentials(cache, "username")
+		assert.NotNil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("does not prepare again right after an error", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+		client := NewPostgres()
+		cache := client.PrepareStatements(mockDB)
+		now := buildTestTime(t)
+		cache.now = func() time.Time { return now }
+
+		mock.ExpectPrepare(query).WillReturnError(errors.New("pineapple on pizza"))
+		mock.ExpectQuery(query).WithArgs("username").WillReturnError(errors.New("pineapple on pizza"))
+		mock.ExpectQuery(query).WithArgs("username").WillReturnRows(credentialRows())
+		mock.ExpectPrepare(query).ExpectQuery().WithArgs("username").WillReturnRows(credentialRows())
+
+		_, err = client.GetUserCredentials(cache, "username")
+		assert.NotNil(t, err)
+		_, err = client.GetUserCredentials(cache, "username")
+		assert.NoError(t, err)
+		now = now.Add(prepareRetryAfter)
+		_, err = client.GetUserCredentials(cache, "username")
+		assert.NoError(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+	t.Run("invalidating after migrations", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+		client := NewPostgres()
+		cache := client.PrepareStatements(mockDB)
+
+		prepared := mock.ExpectPrepare(query)
+		prepared.ExpectQuery().WithArgs("username").WillReturnRows(credentialRows())
+		prepared.WillBeClosed()
+		mock.ExpectPrepare(query).ExpectQuery().WithArgs("username").WillReturnRows(credentialRows())
+
+		_, err = client.GetUserCredentials(cache, "username")
+		assert.NoError(t, err)
+		client.invalidatePreparedStatements()
+		_, err = client.GetUserCredentials(cache, "username")
+		assert.NoError(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+	t.Run("closing", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+		client := NewPostgres()
+		cache := client.PrepareStatements(mockDB)
+
+		prepared := mock.ExpectPrepare(query)
+		prepared.ExpectQuery().WithArgs("username").WillReturnRows(credentialRows())
+		prepared.WillBeClosed()
+		mock.ExpectQuery(query).WithArgs("username").WillReturnRows(credentialRows())
+
+		_, err = client.GetUserCredentials(cache, "username")
+		assert.NoError(t, err)
+		assert.NoError(t, cache.Close())
+		assert.Empty(t, client.statementCaches)
+		_, err = client.GetUserCredentials(cache, "username")
+		assert.NoError(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func TestIsStalePlanError(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isStalePlanError(&pq.Error{Code: "0A000", Message: "cached plan must not change result type"}))
+	assert.True(t, isStalePlanError(&pq.Error{Code: "26000", Message: `prepared statement "1" does not exist`}))
+	assert.False(t, isStalePlanError(&pq.Error{Code: "0A000", Message: "cannot use window functions here"}))
+	assert.False(t, isStalePlanError(errors.New("pineapple on pizza")))
+	assert.False(t, isStalePlanError(nil))
+}
+
+// benchmarkDatabaseKey names the environment variable holding the connection
+// string of a database migrated with example data. The prepared statement
+// benchmarks are skipped when it is unset.
+const benchmarkDatabaseKey = "BENCHMARK_DATABASE_URL"
+
+func benchmarkHotPathQueries(b *testing.B, prepared bool) {
+	dbURL := os.Getenv(benchmarkDatabaseKey)
+	if dbURL == "" {
+		b.Skipf("set %s to a database migrated with example data to run the prepared statement benchmarks", benchmarkDatabaseKey)
+	}
+	db, err := sql.Open("postgres", dbURL)
+	require.NoError(b, err)
+	defer db.Close()
+
+	client := NewPostgres()
+	var querier database.Querier = db
+	if prepared {
+		cache := client.PrepareStatements(db)
+		defer cache.Close()
+		querier = cache
+	}
+
+	calls := map[string]func() error{
+		"GetProduct": func() error {
+			_, err := client.GetProduct(querier, 1)
+			return err
+		},
+		"GetProductBySKU": func() error {
+			_, err := client.GetProductBySKU(querier, "one-armed-bandit")
+			return err
+		},
+		"GetUserByUsername": func() error {
+			_, err := client.GetUserByUsername(querier, "benchmark")
+			return err
+		},
+	}
+	for name, call := range calls {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := call(); err != nil && err != sql.ErrNoRows {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkHotPathQueriesUnprepared(b *testing.B) {
+	benchmarkHotPathQueries(b, false)
+}
+
+func BenchmarkHotPathQueriesPrepared(b *testing.B) {
+	benchmarkHotPathQueries(b, true)
+}
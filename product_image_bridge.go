@@ -12,10 +12,11 @@ import (
 
 const productImageBridgeExistenceQuery = `SELECT EXISTS(SELECT id FROM product_image_bridge WHERE id = $1 and archived_on IS NULL);`
 
-func (pg *postgres) ProductImageBridgeExists(db database.Querier, id uint64) (bool, error) {
+func (pg *postgres) ProductImageBridgeExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("ProductImageBridgeExists", time.Now(), &err, &result, id)
 	var exists string
 
-	err := db.QueryRow(productImageBridgeExistenceQuery, id).Scan(&exists)
+	err = db.QueryRow(productImageBridgeExistenceQuery, id).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -38,10 +39,11 @@ const productImageBridgeSelectionQuery = `
         id = $1
 `
 
-func (pg *postgres) GetProductImageBridge(db database.Querier, id uint64) (*models.ProductImageBridge, error) {
+func (pg *postgres) GetProductImageBridge(db database.Querier, id uint64) (result *models.ProductImageBridge, err error) {
+	defer pg.observe("GetProductImageBridge", time.Now(), &err, &result, id)
 	p := &models.ProductImageBridge{}
 
-	err := db.QueryRow(productImageBridgeSelectionQuery, id).Scan(&p.ID, &p.ProductID, &p.ProductImageID)
+	err = db.QueryRow(productImageBridgeSelectionQuery, id).Scan(&p.ID, &p.ProductID, &p.ProductImageID)
 
 	return p, err
 }
@@ -60,7 +62,8 @@ func buildProductImageBridgeListRetrievalQuery(qf *models.QueryFilter) (string,
 	return query, args
 }
 
-func (pg *postgres) GetProductImageBridgeList(db database.Querier, qf *models.QueryFilter) ([]models.ProductImageBridge, error) {
+func (pg *postgres) GetProductImageBridgeList(db database.Querier, qf *models.QueryFilter) (result []models.ProductImageBridge, err error) {
+	defer pg.observe("GetProductImageBridgeList", time.Now(), &err, &result, qf)
 	var list []models.ProductImageBridge
 	query, args := buildProductImageBridgeListRetrievalQuery(qf)
 
@@ -98,10 +101,11 @@ func buildProductImageBridgeCountRetrievalQuery(qf *models.QueryFilter) (string,
 	return query, args
 }
 
-func (pg *postgres) GetProductImageBridgeCount(db database.Querier, qf *models.QueryFilter) (uint64, error) {
+func (pg *postgres) GetProductImageBridgeCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetProductImageBridgeCount", time.Now(), &err, nil, qf)
 	var count uint64
 	query, args := buildProductImageBridgeCountRetrievalQuery(qf)
-	err := db.QueryRow(query, args...).Scan(&count)
+	err = db.QueryRow(query, args...).Scan(&count)
 	return count, err
 }
 
@@ -119,6 +123,7 @@ const productImageBridgeCreationQuery = `
 `
 
 func (pg *postgres) CreateProductImageBridge(db database.Querier, nu *models.ProductImageBridge) (createdID uint64, createdOn time.Time, err error) {
+	defer pg.observe("CreateProductImageBridge", time.Now(), &err, &createdID, nu)
 	err = db.QueryRow(productImageBridgeCreationQuery, &nu.ProductID, &nu.ProductImageID).Scan(&createdID, &createdOn)
 	return createdID, createdOn, err
 }
@@ -133,9 +138,10 @@ const productImageBridgeUpdateQuery = `
     RETURNING updated_on;
 `
 
-func (pg *postgres) UpdateProductImageBridge(db database.Querier, updated *models.ProductImageBridge) (time.Time, error) {
+func (pg *postgres) UpdateProductImageBridge(db database.Querier, updated *models.ProductImageBridge) (result time.Time, err error) {
+	defer pg.observe("UpdateProductImageBridge", time.Now(), &err, &result, updated)
 	var t time.Time
-	err := db.QueryRow(productImageBridgeUpdateQuery, &updated.ProductID, &updated.ProductImageID, &updated.ID).Scan(&t)
+	err = db.QueryRow(productImageBridgeUpdateQuery, &updated.ProductID, &updated.ProductImageID, &updated.ID).Scan(&t)
 	return t, err
 }
 
@@ -147,6 +153,7 @@ const productImageBridgeDeletionQuery = `
 `
 
 func (pg *postgres) DeleteProductImageBridge(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteProductImageBridge", time.Now(), &err, &t, id)
 	err = db.QueryRow(productImageBridgeDeletionQuery, id).Scan(&t)
 	return t, err
 }
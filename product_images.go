@@ -20,6 +20,7 @@ const assignProductImageIDToProductQuery = `
 `
 
 func (pg *postgres) SetPrimaryProductImageForProduct(db database.Querier, productID, imageID uint64) (t time.Time, err error) {
+	defer pg.observe("SetPrimaryProductImageForProduct", time.Now(), &err, &t, productID, imageID)
 	err = db.QueryRow(assignProductImageIDToProductQuery, imageID, productID).Scan(&t)
 	return t, err
 }
@@ -43,7 +44,8 @@ const productImageQueryByProductID = `
         product_id = $1
 `
 
-func (pg *postgres) GetProductImagesByProductID(db database.Querier, productID uint64) ([]models.ProductImage, error) {
+func (pg *postgres) GetProductImagesByProductID(db database.Querier, productID uint64) (result []models.ProductImage, err error) {
+	defer pg.observe("GetProductImagesByProductID", time.Now(), &err, &result, productID)
 	var list []models.ProductImage
 
 	rows, err := db.Query(productImageQueryByProductID, productID)
@@ -79,10 +81,11 @@ func (pg *postgres) GetProductImagesByProductID(db database.Querier, productID u
 
 const productImageExistenceQuery = `SELECT EXISTS(SELECT id FROM product_images WHERE id = $1 and archived_on IS NULL);`
 
-func (pg *postgres) ProductImageExists(db database.Querier, id uint64) (bool, error) {
+func (pg *postgres) ProductImageExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("ProductImageExists", time.Now(), &err, &result, id)
 	var exists string
 
-	err := db.QueryRow(productImageExistenceQuery, id).Scan(&exists)
+	err = db.QueryRow(productImageExistenceQuery, id).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -111,10 +114,11 @@ const productImageSelectionQuery = `
         id = $1
 `
 
-func (pg *postgres) GetProductImage(db database.Querier, id uint64) (*models.ProductImage, error) {
+func (pg *postgres) GetProductImage(db database.Querier, id uint64) (result *models.ProductImage, err error) {
+	defer pg.observe("GetProductImage", time.Now(), &err, &result, id)
 	p := &models.ProductImage{}
 
-	err := db.QueryRow(productImageSelectionQuery, id).Scan(&p.ID, &p.ProductRootID, &p.ThumbnailURL, &p.MainURL, &p.OriginalURL, &p.SourceURL, &p.CreatedOn, &p.UpdatedOn, &p.ArchivedOn)
+	err = db.QueryRow(productImageSelectionQuery, id).Scan(&p.ID, &p.ProductRootID, &p.ThumbnailURL, &p.MainURL, &p.OriginalURL, &p.SourceURL, &p.CreatedOn, &p.UpdatedOn, &p.ArchivedOn)
 
 	return p, err
 }
@@ -139,7 +143,8 @@ func buildProductImageListRetrievalQuery(qf *models.QueryFilter) (string, []inte
 	return query, args
 }
 
-func (pg *postgres) GetProductImageList(db database.Querier, qf *models.QueryFilter) ([]models.ProductImage, error) {
+func (pg *postgres) GetProductImageList(db database.Querier, qf *models.QueryFilter) (result []models.ProductImage, err error) {
+	defer pg.observe("GetProductImageList", time.Now(), &err, &result, qf)
 	var list []models.ProductImage
 	query, args := buildProductImageListRetrievalQuery(qf)
 
@@ -183,10 +188,11 @@ func buildProductImageCountRetrievalQuery(qf *models.QueryFilter) (string, []int
 	return query, args
 }
 
-func (pg *postgres) GetProductImageCount(db database.Querier, qf *models.QueryFilter) (uint64, error) {
+func (pg *postgres) GetProductImageCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetProductImageCount", time.Now(), &err, nil, qf)
 	var count uint64
 	query, args := buildProductImageCountRetrievalQuery(qf)
-	err := db.QueryRow(query, args...).Scan(&count)
+	err = db.QueryRow(query, args...).Scan(&count)
 	return count, err
 }
 
@@ -204,6 +210,7 @@ const productImageCreationQuery = `
 `
 
 func (pg *postgres) CreateProductImage(db database.Querier, nu *models.ProductImage) (createdID uint64, createdOn time.Time, err error) {
+	defer pg.observe("CreateProductImage", time.Now(), &err, &createdID, nu)
 	err = db.QueryRow(productImageCreationQuery, &nu.ProductRootID, &nu.ThumbnailURL, &nu.MainURL, &nu.OriginalURL, &nu.SourceURL).Scan(&createdID, &createdOn)
 	return createdID, createdOn, err
 }
@@ -221,9 +228,10 @@ const productImageUpdateQuery = `
     RETURNING updated_on;
 `
 
-func (pg *postgres) UpdateProductImage(db database.Querier, updated *models.ProductImage) (time.Time, error) {
+func (pg *postgres) UpdateProductImage(db database.Querier, updated *models.ProductImage) (result time.Time, err error) {
+	defer pg.observe("UpdateProductImage", time.Now(), &err, &result, updated)
 	var t time.Time
-	err := db.QueryRow(productImageUpdateQuery, &updated.ProductRootID, &updated.ThumbnailURL, &updated.MainURL, &updated.OriginalURL, &updated.SourceURL, &updated.ID).Scan(&t)
+	err = db.QueryRow(productImageUpdateQuery, &updated.ProductRootID, &updated.ThumbnailURL, &updated.MainURL, &updated.OriginalURL, &updated.SourceURL, &updated.ID).Scan(&t)
 	return t, err
 }
 
@@ -235,6 +243,7 @@ const productImageDeletionQuery = `
 `
 
 func (pg *postgres) DeleteProductImage(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteProductImage", time.Now(), &err, &t, id)
 	err = db.QueryRow(productImageDeletionQuery, id).Scan(&t)
 	return t, err
 }
@@ -12,10 +12,11 @@ import (
 
 const productOptionValueForOptionIDExistenceQuery = `SELECT EXISTS(SELECT id FROM product_option_values WHERE product_option_id = $1 AND value = $2 and archived_on IS NULL);`
 
-func (pg *postgres) ProductOptionValueForOptionIDExists(db database.Querier, optionID uint64, value string) (bool, error) {
+func (pg *postgres) ProductOptionValueForOptionIDExists(db database.Querier, optionID uint64, value string) (result bool, err error) {
+	defer pg.observe("ProductOptionValueForOptionIDExists", time.Now(), &err, &result, optionID, value)
 	var exists string
 
-	err := db.QueryRow(productOptionValueForOptionIDExistenceQuery, optionID, value).Scan(&exists)
+	err = db.QueryRow(productOptionValueForOptionIDExistenceQuery, optionID, value).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -33,6 +34,7 @@ const productOptionValueArchiveQueryByOptionID = `
 `
 
 func (pg *postgres) ArchiveProductOptionValuesForOption(db database.Querier, optionID uint64) (t time.Time, err error) {
+	defer pg.observe("ArchiveProductOptionValuesForOption", time.Now(), &err, &t, optionID)
 	err = db.QueryRow(productOptionValueArchiveQueryByOptionID, optionID).Scan(&t)
 	return t, err
 }
@@ -53,7 +55,8 @@ const productOptionValueRetrievalQueryByOptionID = `
         product_option_id = $1
 `
 
-func (pg *postgres) GetProductOptionValuesForOption(db database.Querier, optionID uint64) ([]models.ProductOptionValue, error) {
+func (pg *postgres) GetProductOptionValuesForOption(db database.Querier, optionID uint64) (result []models.ProductOptionValue, err error) {
+	defer pg.observe("GetProductOptionValuesForOption", time.Now(), &err, &result, optionID)
 	var list []models.ProductOptionValue
 
 	rows, err := db.Query(productOptionValueRetrievalQueryByOptionID, optionID)
@@ -86,10 +89,11 @@ func (pg *postgres) GetProductOptionValuesForOption(db database.Querier, optionI
 
 const productOptionValueExistenceQuery = `SELECT EXISTS(SELECT id FROM product_option_values WHERE id = $1 and archived_on IS NULL);`
 
-func (pg *postgres) ProductOptionValueExists(db database.Querier, id uint64) (bool, error) {
+func (pg *postgres) ProductOptionValueExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("ProductOptionValueExists", time.Now(), &err, &result, id)
 	var exists string
 
-	err := db.QueryRow(productOptionValueExistenceQuery, id).Scan(&exists)
+	err = db.QueryRow(productOptionValueExistenceQuery, id).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -115,10 +119,11 @@ const productOptionValueSelectionQuery = `
         id = $1
 `
 
-func (pg *postgres) GetProductOptionValue(db database.Querier, id uint64) (*models.ProductOptionValue, error) {
+func (pg *postgres) GetProductOptionValue(db database.Querier, id uint64) (result *models.ProductOptionValue, err error) {
+	defer pg.observe("GetProductOptionValue", time.Now(), &err, &result, id)
 	p := &models.ProductOptionValue{}
 
-	err := db.QueryRow(productOptionValueSelectionQuery, id).Scan(&p.ID, &p.ProductOptionID, &p.Value, &p.CreatedOn, &p.UpdatedOn, &p.ArchivedOn)
+	err = db.QueryRow(productOptionValueSelectionQuery, id).Scan(&p.ID, &p.ProductOptionID, &p.Value, &p.CreatedOn, &p.UpdatedOn, &p.ArchivedOn)
 
 	return p, err
 }
@@ -140,7 +145,8 @@ func buildProductOptionValueListRetrievalQuery(qf *models.QueryFilter) (string,
 	return query, args
 }
 
-func (pg *postgres) GetProductOptionValueList(db database.Querier, qf *models.QueryFilter) ([]models.ProductOptionValue, error) {
+func (pg *postgres) GetProductOptionValueList(db database.Querier, qf *models.QueryFilter) (result []models.ProductOptionValue, err error) {
+	defer pg.observe("GetProductOptionValueList", time.Now(), &err, &result, qf)
 	var list []models.ProductOptionValue
 	query, args := buildProductOptionValueListRetrievalQuery(qf)
 
@@ -181,10 +187,11 @@ func buildProductOptionValueCountRetrievalQuery(qf *models.QueryFilter) (string,
 	return query, args
 }
 
-func (pg *postgres) GetProductOptionValueCount(db database.Querier, qf *models.QueryFilter) (uint64, error) {
+func (pg *postgres) GetProductOptionValueCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetProductOptionValueCount", time.Now(), &err, nil, qf)
 	var count uint64
 	query, args := buildProductOptionValueCountRetrievalQuery(qf)
-	err := db.QueryRow(query, args...).Scan(&count)
+	err = db.QueryRow(query, args...).Scan(&count)
 	return count, err
 }
 
@@ -202,6 +209,7 @@ const productOptionValueCreationQuery = `
 `
 
 func (pg *postgres) CreateProductOptionValue(db database.Querier, nu *models.ProductOptionValue) (createdID uint64, createdOn time.Time, err error) {
+	defer pg.observe("CreateProductOptionValue", time.Now(), &err, &createdID, nu)
 	err = db.QueryRow(productOptionValueCreationQuery, &nu.ProductOptionID, &nu.Value).Scan(&createdID, &createdOn)
 	return createdID, createdOn, err
 }
@@ -216,9 +224,10 @@ const productOptionValueUpdateQuery = `
     RETURNING updated_on;
 `
 
-func (pg *postgres) UpdateProductOptionValue(db database.Querier, updated *models.ProductOptionValue) (time.Time, error) {
+func (pg *postgres) UpdateProductOptionValue(db database.Querier, updated *models.ProductOptionValue) (result time.Time, err error) {
+	defer pg.observe("UpdateProductOptionValue", time.Now(), &err, &result, updated)
 	var t time.Time
-	err := db.QueryRow(productOptionValueUpdateQuery, &updated.ProductOptionID, &updated.Value, &updated.ID).Scan(&t)
+	err = db.QueryRow(productOptionValueUpdateQuery, &updated.ProductOptionID, &updated.Value, &updated.ID).Scan(&t)
 	return t, err
 }
 
@@ -230,6 +239,7 @@ const productOptionValueDeletionQuery = `
 `
 
 func (pg *postgres) DeleteProductOptionValue(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteProductOptionValue", time.Now(), &err, &t, id)
 	err = db.QueryRow(productOptionValueDeletionQuery, id).Scan(&t)
 	return t, err
 }
@@ -241,6 +251,7 @@ const productOptionValueWithProductRootIDDeletionQuery = `
 `
 
 func (pg *postgres) ArchiveProductOptionValuesWithProductRootID(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("ArchiveProductOptionValuesWithProductRootID", time.Now(), &err, &t, id)
 	err = db.QueryRow(productOptionValueWithProductRootIDDeletionQuery, id).Scan(&t)
 	return t, err
 }
@@ -12,10 +12,11 @@ import (
 
 const productOptionForNameAndProductIDExistenceQuery = `SELECT EXISTS(SELECT 1 FROM product_options WHERE name = $1 AND product_root_id = $2 and archived_on IS NULL)`
 
-func (pg *postgres) ProductOptionWithNameExistsForProductRoot(db database.Querier, name string, productRootID uint64) (bool, error) {
+func (pg *postgres) ProductOptionWithNameExistsForProductRoot(db database.Querier, name string, productRootID uint64) (result bool, err error) {
+	defer pg.observe("ProductOptionWithNameExistsForProductRoot", time.Now(), &err, &result, name, productRootID)
 	var exists string
 
-	err := db.QueryRow(productOptionForNameAndProductIDExistenceQuery, name, productRootID).Scan(&exists)
+	err = db.QueryRow(productOptionForNameAndProductIDExistenceQuery, name, productRootID).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -39,7 +40,8 @@ const productOptionQueryByProductRootID = `
         product_root_id = $1
 `
 
-func (pg *postgres) GetProductOptionsByProductRootID(db database.Querier, productRootID uint64) ([]models.ProductOption, error) {
+func (pg *postgres) GetProductOptionsByProductRootID(db database.Querier, productRootID uint64) (result []models.ProductOption, err error) {
+	defer pg.observe("GetProductOptionsByProductRootID", time.Now(), &err, &result, productRootID)
 	var list []models.ProductOption
 
 	rows, err := db.Query(productOptionQueryByProductRootID, productRootID)
@@ -72,10 +74,11 @@ func (pg *postgres) GetProductOptionsByProductRootID(db database.Querier, produc
 
 const productOptionExistenceQuery = `SELECT EXISTS(SELECT id FROM product_options WHERE id = $1 and archived_on IS NULL);`
 
-func (pg *postgres) ProductOptionExists(db database.Querier, id uint64) (bool, error) {
+func (pg *postgres) ProductOptionExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("ProductOptionExists", time.Now(), &err, &result, id)
 	var exists string
 
-	err := db.QueryRow(productOptionExistenceQuery, id).Scan(&exists)
+	err = db.QueryRow(productOptionExistenceQuery, id).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -101,10 +104,11 @@ const productOptionSelectionQuery = `
         id = $1
 `
 
-func (pg *postgres) GetProductOption(db database.Querier, id uint64) (*models.ProductOption, error) {
+func (pg *postgres) GetProductOption(db database.Querier, id uint64) (result *models.ProductOption, err error) {
+	defer pg.observe("GetProductOption", time.Now(), &err, &result, id)
 	p := &models.ProductOption{}
 
-	err := db.QueryRow(productOptionSelectionQuery, id).Scan(&p.ID, &p.Name, &p.ProductRootID, &p.CreatedOn, &p.UpdatedOn, &p.ArchivedOn)
+	err = db.QueryRow(productOptionSelectionQuery, id).Scan(&p.ID, &p.Name, &p.ProductRootID, &p.CreatedOn, &p.UpdatedOn, &p.ArchivedOn)
 
 	return p, err
 }
@@ -126,7 +130,8 @@ func buildProductOptionListRetrievalQuery(qf *models.QueryFilter) (string, []int
 	return query, args
 }
 
-func (pg *postgres) GetProductOptionList(db database.Querier, qf *models.QueryFilter) ([]models.ProductOption, error) {
+func (pg *postgres) GetProductOptionList(db database.Querier, qf *models.QueryFilter) (result []models.ProductOption, err error) {
+	defer pg.observe("GetProductOptionList", time.Now(), &err, &result, qf)
 	var list []models.ProductOption
 	query, args := buildProductOptionListRetrievalQuery(qf)
 
@@ -167,14 +172,16 @@ func buildProductOptionCountRetrievalQuery(qf *models.QueryFilter) (string, []in
 	return query, args
 }
 
-func (pg *postgres) GetProductOptionCount(db database.Querier, qf *models.QueryFilter) (uint64, error) {
+func (pg *postgres) GetProductOptionCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetProductOptionCount", time.Now(), &err, nil, qf)
 	var count uint64
 	query, args := buildProductOptionCountRetrievalQuery(qf)
-	err := db.QueryRow(query, args...).Scan(&count)
+	err = db.QueryRow(query, args...).Scan(&count)
 	return count, err
 }
 
 const productOptionCreationQuery = `
+    WITH created AS (
     INSERT INTO product_options
         (
             name, product_root_id
@@ -184,38 +191,60 @@ const productOptionCreationQuery = `
             $1, $2
         )
     RETURNING
-        id, created_on;
+        *
+    ), outboxed AS (
+        INSERT INTO outbox_events (event_type, payload)
+        SELECT 'product_option_created', to_jsonb(created) FROM created
+    )
+    SELECT
+        id, created_on
+    FROM created;
 `
 
 func (pg *postgres) CreateProductOption(db database.Querier, nu *models.ProductOption) (createdID uint64, createdOn time.Time, err error) {
+	defer pg.observe("CreateProductOption", time.Now(), &err, &createdID, nu)
 	err = db.QueryRow(productOptionCreationQuery, &nu.Name, &nu.ProductRootID).Scan(&createdID, &createdOn)
 	return createdID, createdOn, err
 }
 
 const productOptionUpdateQuery = `
+    WITH updated AS (
     UPDATE product_options
     SET
         name = $1,
         product_root_id = $2,
         updated_on = NOW()
     WHERE id = $3
-    RETURNING updated_on;
+    RETURNING *
+    ), outboxed AS (
+        INSERT INTO outbox_events (event_type, payload)
+        SELECT 'product_option_updated', to_jsonb(updated) FROM updated
+    )
+    SELECT updated_on FROM updated;
 `
 
-func (pg *postgres) UpdateProductOption(db database.Querier, updated *models.ProductOption) (time.Time, error) {
+func (pg *postgres) UpdateProductOption(db database.Querier, updated *models.ProductOption) (result time.Time, err error) {
+	defer pg.observe("UpdateProductOption", time.Now(), &err, &result, updated)
 	var t time.Time
-	err := db.QueryRow(productOptionUpdateQuery, &updated.Name, &updated.ProductRootID, &updated.ID).Scan(&t)
+	err = db.QueryRow(productOptionUpdateQuery, &updated.Name, &updated.ProductRootID, &updated.ID).Scan(&t)
 	return t, err
 }
 
 const productOptionDeletionQuery = `
+    WITH archived AS (
     UPDATE product_options
     SET archived_on = NOW()
     WHERE id = $1
-    RETURNING archived_on
+    RETURNING *
+    ), outboxed AS (
+        INSERT INTO outbox_events (event_type, payload)
+        SELECT 'product_option_archived', to_jsonb(archived) FROM archived
+    )
+    SELECT archived_on FROM archived
 `
 
 func (pg *postgres) DeleteProductOption(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteProductOption", time.Now(), &err, &t, id)
 	err = db.QueryRow(productOptionDeletionQuery, id).Scan(&t)
 	return t, err
 }
@@ -228,6 +257,7 @@ const productOptionWithProductRootIDDeletionQuery = `
 `
 
 func (pg *postgres) ArchiveProductOptionsWithProductRootID(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("ArchiveProductOptionsWithProductRootID", time.Now(), &err, &t, id)
 	err = db.QueryRow(productOptionWithProductRootIDDeletionQuery, id).Scan(&t)
 	return t, err
 }
@@ -485,6 +485,19 @@ func TestDeleteProductOptionByID(t *testing.T) {
 	})
 }
 
+func TestProductOptionWritesAppendOutboxEvents(t *testing.T) {
+	t.Parallel()
+
+	for query, eventType := range map[string]string{
+		productOptionCreationQuery: "product_option_created",
+		productOptionUpdateQuery:   "product_option_updated",
+		productOptionDeletionQuery: "product_option_archived",
+	} {
+		assert.Contains(t, query, "INSERT INTO outbox_events", "productoption writes should append to the outbox in the same statement")
+		assert.Contains(t, query, "'"+eventType+"'", "productoption write should record a %s event", eventType)
+	}
+}
+
 func setProductOptionWithProductRootIDDeletionQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, err error) {
 	t.Helper()
 	query := formatQueryForSQLMock(productOptionWithProductRootIDDeletionQuery)
@@ -0,0 +1,299 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/dairycart/dairycart/storage/database"
+	"github.com/dairycart/dairymodels/v1"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const setProductPriceQuery = `
+    INSERT INTO product_prices
+        (
+            product_id, currency, price, starts_on, expires_on
+        )
+    VALUES
+        (
+            $1, $2, $3, COALESCE($4, NOW()), $5
+        )
+    ON CONFLICT (product_id, currency, starts_on) WHERE archived_on IS NULL
+    DO UPDATE SET
+        price = EXCLUDED.price,
+        expires_on = EXCLUDED.expires_on,
+        updated_on = NOW()
+    RETURNING
+        id, created_on;
+`
+
+func (pg *postgres) SetProductPrice(db database.Querier, nu *models.ProductPrice) (id uint64, createdOn time.Time, err error) {
+	defer pg.observe("SetProductPrice", time.Now(), &err, &id, nu)
+	err = db.QueryRow(setProductPriceQuery, &nu.ProductID, &nu.Currency, &nu.Price, &nu.StartsOn, &nu.ExpiresOn).Scan(&id, &createdOn)
+	return id, createdOn, err
+}
+
+const productPriceQueryByProductIDAndCurrency = `
+    SELECT
+        id,
+        product_id,
+        currency,
+        price,
+        starts_on,
+        expires_on,
+        created_on,
+        updated_on,
+        archived_on
+    FROM
+        product_prices
+    WHERE
+        archived_on is null
+    AND
+        product_id = $1
+    AND
+        currency = $2
+    AND
+        starts_on <= NOW()
+    AND
+        (expires_on IS NULL OR expires_on > NOW())
+    ORDER BY
+        starts_on DESC
+    LIMIT 1
+`
+
+func (pg *postgres) GetProductPriceForCurrency(db database.Querier, productID uint64, currency string) (result *models.ProductPrice, err error) {
+	defer pg.observe("GetProductPriceForCurrency", time.Now(), &err, &result, productID, currency)
+	p := &models.ProductPrice{}
+	err = db.QueryRow(productPriceQueryByProductIDAndCurrency, productID, currency).Scan(&p.ID, &p.ProductID, &p.Currency, &p.Price, &p.StartsOn, &p.ExpiresOn, &p.CreatedOn, &p.UpdatedOn, &p.ArchivedOn)
+	return p, err
+}
+
+const productPriceQueryByCurrency = `
+    SELECT DISTINCT ON (product_id)
+        id,
+        product_id,
+        currency,
+        price,
+        starts_on,
+        expires_on,
+        created_on,
+        updated_on,
+        archived_on
+    FROM
+        product_prices
+    WHERE
+        archived_on is null
+    AND
+        currency = $1
+    AND
+        starts_on <= NOW()
+    AND
+        (expires_on IS NULL OR expires_on > NOW())
+    ORDER BY
+        product_id, starts_on DESC
+`
+
+func (pg *postgres) GetProductPricesForCurrency(db database.Querier, currency string) (result []models.ProductPrice, err error) {
+	defer pg.observe("GetProductPricesForCurrency", time.Now(), &err, &result, currency)
+	var list []models.ProductPrice
+
+	rows, err := db.Query(productPriceQueryByCurrency, currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p models.ProductPrice
+		err := rows.Scan(
+			&p.ID,
+			&p.ProductID,
+			&p.Currency,
+			&p.Price,
+			&p.StartsOn,
+			&p.ExpiresOn,
+			&p.CreatedOn,
+			&p.UpdatedOn,
+			&p.ArchivedOn,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+const productPriceExistenceQuery = `SELECT EXISTS(SELECT id FROM product_prices WHERE id = $1 and archived_on IS NULL);`
+
+func (pg *postgres) ProductPriceExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("ProductPriceExists", time.Now(), &err, &result, id)
+	var exists string
+
+	err = db.QueryRow(productPriceExistenceQuery, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return exists == "true", err
+}
+
+const productPriceSelectionQuery = `
+    SELECT
+        id,
+        product_id,
+        currency,
+        price,
+        starts_on,
+        expires_on,
+        created_on,
+        updated_on,
+        archived_on
+    FROM
+        product_prices
+    WHERE
+        archived_on is null
+    AND
+        id = $1
+`
+
+func (pg *postgres) GetProductPrice(db database.Querier, id uint64) (result *models.ProductPrice, err error) {
+	defer pg.observe("GetProductPrice", time.Now(), &err, &result, id)
+	p := &models.ProductPrice{}
+
+	err = db.QueryRow(productPriceSelectionQuery, id).Scan(&p.ID, &p.ProductID, &p.Currency, &p.Price, &p.StartsOn, &p.ExpiresOn, &p.CreatedOn, &p.UpdatedOn, &p.ArchivedOn)
+
+	return p, err
+}
+
+func buildProductPriceListRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	sqlBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	queryBuilder := sqlBuilder.
+		Select(
+			"id",
+			"product_id",
+			"currency",
+			"price",
+			"starts_on",
+			"expires_on",
+			"created_on",
+			"updated_on",
+			"archived_on",
+		).
+		From("product_prices")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, true).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetProductPriceList(db database.Querier, qf *models.QueryFilter) (result []models.ProductPrice, err error) {
+	defer pg.observe("GetProductPriceList", time.Now(), &err, &result, qf)
+	var list []models.ProductPrice
+	query, args := buildProductPriceListRetrievalQuery(qf)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p models.ProductPrice
+		err := rows.Scan(
+			&p.ID,
+			&p.ProductID,
+			&p.Currency,
+			&p.Price,
+			&p.StartsOn,
+			&p.ExpiresOn,
+			&p.CreatedOn,
+			&p.UpdatedOn,
+			&p.ArchivedOn,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+func buildProductPriceCountRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("count(id)").
+		From("product_prices")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, false).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetProductPriceCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetProductPriceCount", time.Now(), &err, nil, qf)
+	var count uint64
+	query, args := buildProductPriceCountRetrievalQuery(qf)
+	err = db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+const productPriceCreationQuery = `
+    INSERT INTO product_prices
+        (
+            product_id, currency, price, starts_on, expires_on
+        )
+    VALUES
+        (
+            $1, $2, $3, $4, $5
+        )
+    RETURNING
+        id, created_on;
+`
+
+func (pg *postgres) CreateProductPrice(db database.Querier, nu *models.ProductPrice) (createdID uint64, createdOn time.Time, err error) {
+	defer pg.observe("CreateProductPrice", time.Now(), &err, &createdID, nu)
+	err = db.QueryRow(productPriceCreationQuery, &nu.ProductID, &nu.Currency, &nu.Price, &nu.StartsOn, &nu.ExpiresOn).Scan(&createdID, &createdOn)
+	return createdID, createdOn, err
+}
+
+const productPriceUpdateQuery = `
+    UPDATE product_prices
+    SET
+        product_id = $1,
+        currency = $2,
+        price = $3,
+        starts_on = $4,
+        expires_on = $5,
+        updated_on = NOW()
+    WHERE id = $6
+    RETURNING updated_on;
+`
+
+func (pg *postgres) UpdateProductPrice(db database.Querier, updated *models.ProductPrice) (result time.Time, err error) {
+	defer pg.observe("UpdateProductPrice", time.Now(), &err, &result, updated)
+	var t time.Time
+	err = db.QueryRow(productPriceUpdateQuery, &updated.ProductID, &updated.Currency, &updated.Price, &updated.StartsOn, &updated.ExpiresOn, &updated.ID).Scan(&t)
+	return t, err
+}
+
+const productPriceDeletionQuery = `
+    UPDATE product_prices
+    SET archived_on = NOW()
+    WHERE id = $1
+    RETURNING archived_on
+`
+
+func (pg *postgres) DeleteProductPrice(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteProductPrice", time.Now(), &err, &t, id)
+	err = db.QueryRow(productPriceDeletionQuery, id).Scan(&t)
+	return t, err
+}
@@ -0,0 +1,574 @@
+package postgres
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strconv"
+	"testing"
+
+	// internal dependencies
+	"github.com/dairycart/dairymodels/v1"
+
+	// external dependencies
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func setSetProductPriceQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toSet *models.ProductPrice, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(setProductPriceQuery)
+	tt := buildTestTime(t)
+	exampleRows := sqlmock.NewRows([]string{"id", "created_on"}).AddRow(uint64(1), tt)
+	mock.ExpectQuery(query).
+		WithArgs(
+			toSet.ProductID,
+			toSet.Currency,
+			toSet.Price,
+			toSet.StartsOn,
+			toSet.ExpiresOn,
+		).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestSetProductPrice(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	expectedID := uint64(1)
+	exampleInput := &models.ProductPrice{ProductID: uint64(1), Currency: "EUR", Price: 12.34}
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setSetProductPriceQueryExpectation(t, mock, exampleInput, nil)
+		expectedCreatedOn := buildTestTime(t)
+		actualID, actualCreatedOn, err := client.SetProductPrice(mockDB, exampleInput)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedID, actualID, "expected and actual IDs don't match")
+		assert.Equal(t, expectedCreatedOn, actualCreatedOn, "expected creation time did not match actual creation time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("without start time", func(t *testing.T) {
+		assert.Contains(t, setProductPriceQuery, "COALESCE($4, NOW())", "an unset start time should fall back to the column default")
+	})
+}
+
+func setProductPriceReadQueryExpectationByCurrency(t *testing.T, mock sqlmock.Sqlmock, productID uint64, currency string, toReturn *models.ProductPrice, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(productPriceQueryByProductIDAndCurrency)
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"product_id",
+		"currency",
+		"price",
+		"starts_on",
+		"expires_on",
+		"created_on",
+		"updated_on",
+		"archived_on",
+	}).AddRow(
+		toReturn.ID,
+		toReturn.ProductID,
+		toReturn.Currency,
+		toReturn.Price,
+		toReturn.StartsOn,
+		toReturn.ExpiresOn,
+		toReturn.CreatedOn,
+		toReturn.UpdatedOn,
+		toReturn.ArchivedOn,
+	)
+	mock.ExpectQuery(query).WithArgs(productID, currency).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestGetProductPriceForCurrency(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+
+	exampleProductID := uint64(1)
+	exampleCurrency := "EUR"
+	expected := &models.ProductPrice{ProductID: exampleProductID, Currency: exampleCurrency}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setProductPriceReadQueryExpectationByCurrency(t, mock, exampleProductID, exampleCurrency, expected, nil)
+		actual, err := client.GetProductPriceForCurrency(mockDB, exampleProductID, exampleCurrency)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected productprice did not match actual productprice")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with no price set", func(t *testing.T) {
+		setProductPriceReadQueryExpectationByCurrency(t, mock, exampleProductID, exampleCurrency, expected, sql.ErrNoRows)
+		_, err := client.GetProductPriceForCurrency(mockDB, exampleProductID, exampleCurrency)
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setProductPriceListReadQueryExpectationByCurrency(t *testing.T, mock sqlmock.Sqlmock, currency string, example *models.ProductPrice, rowErr error, err error) {
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"product_id",
+		"currency",
+		"price",
+		"starts_on",
+		"expires_on",
+		"created_on",
+		"updated_on",
+		"archived_on",
+	}).AddRow(
+		example.ID,
+		example.ProductID,
+		example.Currency,
+		example.Price,
+		example.StartsOn,
+		example.ExpiresOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).AddRow(
+		example.ID,
+		example.ProductID,
+		example.Currency,
+		example.Price,
+		example.StartsOn,
+		example.ExpiresOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).AddRow(
+		example.ID,
+		example.ProductID,
+		example.Currency,
+		example.Price,
+		example.StartsOn,
+		example.ExpiresOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).RowError(1, rowErr)
+
+	mock.ExpectQuery(formatQueryForSQLMock(productPriceQueryByCurrency)).
+		WithArgs(currency).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestGetProductPricesForCurrency(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+
+	exampleCurrency := "EUR"
+	example := &models.ProductPrice{Currency: exampleCurrency}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setProductPriceListReadQueryExpectationByCurrency(t, mock, exampleCurrency, example, nil, nil)
+		actual, err := client.GetProductPricesForCurrency(mockDB, exampleCurrency)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, actual, "list retrieval method should not return an empty slice")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error executing query", func(t *testing.T) {
+		setProductPriceListReadQueryExpectationByCurrency(t, mock, exampleCurrency, example, nil, errors.New("pineapple on pizza"))
+		actual, err := client.GetProductPricesForCurrency(mockDB, exampleCurrency)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error scanning values", func(t *testing.T) {
+		exampleRows := sqlmock.NewRows([]string{"things"}).AddRow("stuff")
+		mock.ExpectQuery(formatQueryForSQLMock(productPriceQueryByCurrency)).
+			WillReturnRows(exampleRows)
+
+		actual, err := client.GetProductPricesForCurrency(mockDB, exampleCurrency)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with with row errors", func(t *testing.T) {
+		setProductPriceListReadQueryExpectationByCurrency(t, mock, exampleCurrency, example, errors.New("pineapple on pizza"), nil)
+		actual, err := client.GetProductPricesForCurrency(mockDB, exampleCurrency)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setProductPriceExistenceQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, shouldExist bool, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(productPriceExistenceQuery)
+
+	mock.ExpectQuery(query).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(strconv.FormatBool(shouldExist))).
+		WillReturnError(err)
+}
+
+func TestProductPriceExists(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	client := NewPostgres()
+
+	t.Run("existing", func(t *testing.T) {
+		setProductPriceExistenceQueryExpectation(t, mock, exampleID, true, nil)
+		actual, err := client.ProductPriceExists(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.True(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with no rows found", func(t *testing.T) {
+		setProductPriceExistenceQueryExpectation(t, mock, exampleID, true, sql.ErrNoRows)
+		actual, err := client.ProductPriceExists(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.False(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with a database error", func(t *testing.T) {
+		setProductPriceExistenceQueryExpectation(t, mock, exampleID, true, errors.New("pineapple on pizza"))
+		actual, err := client.ProductPriceExists(mockDB, exampleID)
+
+		assert.NotNil(t, err)
+		assert.False(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setProductPriceReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, toReturn *models.ProductPrice, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(productPriceSelectionQuery)
+
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"product_id",
+		"currency",
+		"price",
+		"starts_on",
+		"expires_on",
+		"created_on",
+		"updated_on",
+		"archived_on",
+	}).AddRow(
+		toReturn.ID,
+		toReturn.ProductID,
+		toReturn.Currency,
+		toReturn.Price,
+		toReturn.StartsOn,
+		toReturn.ExpiresOn,
+		toReturn.CreatedOn,
+		toReturn.UpdatedOn,
+		toReturn.ArchivedOn,
+	)
+	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestGetProductPrice(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	expected := &models.ProductPrice{ID: exampleID}
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setProductPriceReadQueryExpectation(t, mock, exampleID, expected, nil)
+		actual, err := client.GetProductPrice(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected productprice did not match actual productprice")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setProductPriceListReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, qf *models.QueryFilter, example *models.ProductPrice, rowErr error, err error) {
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"product_id",
+		"currency",
+		"price",
+		"starts_on",
+		"expires_on",
+		"created_on",
+		"updated_on",
+		"archived_on",
+	}).AddRow(
+		example.ID,
+		example.ProductID,
+		example.Currency,
+		example.Price,
+		example.StartsOn,
+		example.ExpiresOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).AddRow(
+		example.ID,
+		example.ProductID,
+		example.Currency,
+		example.Price,
+		example.StartsOn,
+		example.ExpiresOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).AddRow(
+		example.ID,
+		example.ProductID,
+		example.Currency,
+		example.Price,
+		example.StartsOn,
+		example.ExpiresOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).RowError(1, rowErr)
+
+	query, _ := buildProductPriceListRetrievalQuery(qf)
+
+	mock.ExpectQuery(formatQueryForSQLMock(query)).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestGetProductPriceList(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	example := &models.ProductPrice{ID: exampleID}
+	client := NewPostgres()
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setProductPriceListReadQueryExpectation(t, mock, exampleQF, example, nil, nil)
+		actual, err := client.GetProductPriceList(mockDB, exampleQF)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, actual, "list retrieval method should not return an empty slice")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error executing query", func(t *testing.T) {
+		setProductPriceListReadQueryExpectation(t, mock, exampleQF, example, nil, errors.New("pineapple on pizza"))
+		actual, err := client.GetProductPriceList(mockDB, exampleQF)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error scanning values", func(t *testing.T) {
+		exampleRows := sqlmock.NewRows([]string{"things"}).AddRow("stuff")
+		query, _ := buildProductPriceListRetrievalQuery(exampleQF)
+		mock.ExpectQuery(formatQueryForSQLMock(query)).
+			WillReturnRows(exampleRows)
+
+		actual, err := client.GetProductPriceList(mockDB, exampleQF)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with with row errors", func(t *testing.T) {
+		setProductPriceListReadQueryExpectation(t, mock, exampleQF, example, errors.New("pineapple on pizza"), nil)
+		actual, err := client.GetProductPriceList(mockDB, exampleQF)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func TestBuildProductPriceCountRetrievalQuery(t *testing.T) {
+	t.Parallel()
+
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+	expected := `SELECT count(id) FROM product_prices WHERE archived_on IS NULL LIMIT 25`
+	actual, _ := buildProductPriceCountRetrievalQuery(exampleQF)
+
+	assert.Equal(t, expected, actual, "expected and actual queries should match")
+}
+
+func setProductPriceCountRetrievalQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, qf *models.QueryFilter, count uint64, err error) {
+	t.Helper()
+	query, args := buildProductPriceCountRetrievalQuery(qf)
+	query = formatQueryForSQLMock(query)
+
+	var argsToExpect []driver.Value
+	for _, x := range args {
+		argsToExpect = append(argsToExpect, x)
+	}
+
+	exampleRow := sqlmock.NewRows([]string{"count"}).AddRow(count)
+	mock.ExpectQuery(query).WithArgs(argsToExpect...).WillReturnRows(exampleRow).WillReturnError(err)
+}
+
+func TestGetProductPriceCount(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+	expected := uint64(123)
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setProductPriceCountRetrievalQueryExpectation(t, mock, exampleQF, expected, nil)
+		actual, err := client.GetProductPriceCount(mockDB, exampleQF)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "count retrieval method should return the expected value")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setProductPriceCreationQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toCreate *models.ProductPrice, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(productPriceCreationQuery)
+	tt := buildTestTime(t)
+	exampleRows := sqlmock.NewRows([]string{"id", "created_on"}).AddRow(uint64(1), tt)
+	mock.ExpectQuery(query).
+		WithArgs(
+			toCreate.ProductID,
+			toCreate.Currency,
+			toCreate.Price,
+			toCreate.StartsOn,
+			toCreate.ExpiresOn,
+		).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestCreateProductPrice(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	expectedID := uint64(1)
+	exampleInput := &models.ProductPrice{ID: expectedID}
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setProductPriceCreationQueryExpectation(t, mock, exampleInput, nil)
+		expectedCreatedOn := buildTestTime(t)
+
+		actualID, actualCreatedOn, err := client.CreateProductPrice(mockDB, exampleInput)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedID, actualID, "expected and actual IDs don't match")
+		assert.Equal(t, expectedCreatedOn, actualCreatedOn, "expected creation time did not match actual creation time")
+
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setProductPriceUpdateQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toUpdate *models.ProductPrice, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(productPriceUpdateQuery)
+	exampleRows := sqlmock.NewRows([]string{"updated_on"}).AddRow(buildTestTime(t))
+	mock.ExpectQuery(query).
+		WithArgs(
+			toUpdate.ProductID,
+			toUpdate.Currency,
+			toUpdate.Price,
+			toUpdate.StartsOn,
+			toUpdate.ExpiresOn,
+			toUpdate.ID,
+		).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestUpdateProductPriceByID(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleInput := &models.ProductPrice{ID: uint64(1)}
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setProductPriceUpdateQueryExpectation(t, mock, exampleInput, nil)
+		expected := buildTestTime(t)
+		actual, err := client.UpdateProductPrice(mockDB, exampleInput)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected deletion time did not match actual deletion time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setProductPriceDeletionQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(productPriceDeletionQuery)
+	exampleRows := sqlmock.NewRows([]string{"archived_on"}).AddRow(buildTestTime(t))
+	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestDeleteProductPriceByID(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setProductPriceDeletionQueryExpectation(t, mock, exampleID, nil)
+		expected := buildTestTime(t)
+		actual, err := client.DeleteProductPrice(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected deletion time did not match actual deletion time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		setProductPriceDeletionQueryExpectation(t, mock, exampleID, nil)
+		expected := buildTestTime(t)
+		tx, err := mockDB.Begin()
+		assert.NoError(t, err, "no error should be returned setting up a transaction in the mock DB")
+		actual, err := client.DeleteProductPrice(tx, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected deletion time did not match actual deletion time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
@@ -12,10 +12,11 @@ import (
 
 const productRootWithSKUPrefixExistenceQuery = `SELECT EXISTS(SELECT id FROM product_roots WHERE sku_prefix = $1 and archived_on IS NULL);`
 
-func (pg *postgres) ProductRootWithSKUPrefixExists(db database.Querier, skuPrefix string) (bool, error) {
+func (pg *postgres) ProductRootWithSKUPrefixExists(db database.Querier, skuPrefix string) (result bool, err error) {
+	defer pg.observe("ProductRootWithSKUPrefixExists", time.Now(), &err, &result, skuPrefix)
 	var exists string
 
-	err := db.QueryRow(productRootWithSKUPrefixExistenceQuery, skuPrefix).Scan(&exists)
+	err = db.QueryRow(productRootWithSKUPrefixExistenceQuery, skuPrefix).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -27,10 +28,11 @@ func (pg *postgres) ProductRootWithSKUPrefixExists(db database.Querier, skuPrefi
 
 const productRootExistenceQuery = `SELECT EXISTS(SELECT id FROM product_roots WHERE id = $1 and archived_on IS NULL);`
 
-func (pg *postgres) ProductRootExists(db database.Querier, id uint64) (bool, error) {
+func (pg *postgres) ProductRootExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("ProductRootExists", time.Now(), &err, &result, id)
 	var exists string
 
-	err := db.QueryRow(productRootExistenceQuery, id).Scan(&exists)
+	err = db.QueryRow(productRootExistenceQuery, id).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -73,10 +75,11 @@ const productRootSelectionQuery = `
         id = $1
 `
 
-func (pg *postgres) GetProductRoot(db database.Querier, id uint64) (*models.ProductRoot, error) {
+func (pg *postgres) GetProductRoot(db database.Querier, id uint64) (result *models.ProductRoot, err error) {
+	defer pg.observe("GetProductRoot", time.Now(), &err, &result, id)
 	p := &models.ProductRoot{}
 
-	err := db.QueryRow(productRootSelectionQuery, id).Scan(&p.ID, &p.Name, &p.PrimaryImageID, &p.Subtitle, &p.Description, &p.SKUPrefix, &p.Manufacturer, &p.Brand, &p.Taxable, &p.Cost, &p.ProductWeight, &p.ProductHeight, &p.ProductWidth, &p.ProductLength, &p.PackageWeight, &p.PackageHeight, &p.PackageWidth, &p.PackageLength, &p.QuantityPerPackage, &p.AvailableOn, &p.CreatedOn, &p.UpdatedOn, &p.ArchivedOn)
+	err = db.QueryRow(productRootSelectionQuery, id).Scan(&p.ID, &p.Name, &p.PrimaryImageID, &p.Subtitle, &p.Description, &p.SKUPrefix, &p.Manufacturer, &p.Brand, &p.Taxable, &p.Cost, &p.ProductWeight, &p.ProductHeight, &p.ProductWidth, &p.ProductLength, &p.PackageWeight, &p.PackageHeight, &p.PackageWidth, &p.PackageLength, &p.QuantityPerPackage, &p.AvailableOn, &p.CreatedOn, &p.UpdatedOn, &p.ArchivedOn)
 
 	return p, err
 }
@@ -115,7 +118,8 @@ func buildProductRootListRetrievalQuery(qf *models.QueryFilter) (string, []inter
 	return query, args
 }
 
-func (pg *postgres) GetProductRootList(db database.Querier, qf *models.QueryFilter) ([]models.ProductRoot, error) {
+func (pg *postgres) GetProductRootList(db database.Querier, qf *models.QueryFilter) (result []models.ProductRoot, err error) {
+	defer pg.observe("GetProductRootList", time.Now(), &err, &result, qf)
 	var list []models.ProductRoot
 	query, args := buildProductRootListRetrievalQuery(qf)
 
@@ -173,14 +177,16 @@ func buildProductRootCountRetrievalQuery(qf *models.QueryFilter) (string, []inte
 	return query, args
 }
 
-func (pg *postgres) GetProductRootCount(db database.Querier, qf *models.QueryFilter) (uint64, error) {
+func (pg *postgres) GetProductRootCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetProductRootCount", time.Now(), &err, nil, qf)
 	var count uint64
 	query, args := buildProductRootCountRetrievalQuery(qf)
-	err := db.QueryRow(query, args...).Scan(&count)
+	err = db.QueryRow(query, args...).Scan(&count)
 	return count, err
 }
 
 const productRootCreationQuery = `
+    WITH created AS (
     INSERT INTO product_roots
         (
             name, primary_image_id, subtitle, description, sku_prefix, manufacturer, brand, taxable, cost, product_weight, product_height, product_width, product_length, package_weight, package_height, package_width, package_length, quantity_per_package, available_on
@@ -190,15 +196,24 @@ const productRootCreationQuery = `
             $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
         )
     RETURNING
-        id, created_on;
+        *
+    ), outboxed AS (
+        INSERT INTO outbox_events (event_type, payload)
+        SELECT 'product_root_created', to_jsonb(created) FROM created
+    )
+    SELECT
+        id, created_on
+    FROM created;
 `
 
 func (pg *postgres) CreateProductRoot(db database.Querier, nu *models.ProductRoot) (createdID uint64, createdOn time.Time, err error) {
+	defer pg.observe("CreateProductRoot", time.Now(), &err, &createdID, nu)
 	err = db.QueryRow(productRootCreationQuery, &nu.Name, &nu.PrimaryImageID, &nu.Subtitle, &nu.Description, &nu.SKUPrefix, &nu.Manufacturer, &nu.Brand, &nu.Taxable, &nu.Cost, &nu.ProductWeight, &nu.ProductHeight, &nu.ProductWidth, &nu.ProductLength, &nu.PackageWeight, &nu.PackageHeight, &nu.PackageWidth, &nu.PackageLength, &nu.QuantityPerPackage, &nu.AvailableOn).Scan(&createdID, &createdOn)
 	return createdID, createdOn, err
 }
 
 const productRootUpdateQuery = `
+    WITH updated AS (
     UPDATE product_roots
     SET
         name = $1,
@@ -222,23 +237,36 @@ const productRootUpdateQuery = `
         available_on = $19,
         updated_on = NOW()
     WHERE id = $20
-    RETURNING updated_on;
+    RETURNING *
+    ), outboxed AS (
+        INSERT INTO outbox_events (event_type, payload)
+        SELECT 'product_root_updated', to_jsonb(updated) FROM updated
+    )
+    SELECT updated_on FROM updated;
 `
 
-func (pg *postgres) UpdateProductRoot(db database.Querier, updated *models.ProductRoot) (time.Time, error) {
+func (pg *postgres) UpdateProductRoot(db database.Querier, updated *models.ProductRoot) (result time.Time, err error) {
+	defer pg.observe("UpdateProductRoot", time.Now(), &err, &result, updated)
 	var t time.Time
-	err := db.QueryRow(productRootUpdateQuery, &updated.Name, &updated.PrimaryImageID, &updated.Subtitle, &updated.Description, &updated.SKUPrefix, &updated.Manufacturer, &updated.Brand, &updated.Taxable, &updated.Cost, &updated.ProductWeight, &updated.ProductHeight, &updated.ProductWidth, &updated.ProductLength, &updated.PackageWeight, &updated.PackageHeight, &updated.PackageWidth, &updated.PackageLength, &updated.QuantityPerPackage, &updated.AvailableOn, &updated.ID).Scan(&t)
+	err = db.QueryRow(productRootUpdateQuery, &updated.Name, &updated.PrimaryImageID, &updated.Subtitle, &updated.Description, &updated.SKUPrefix, &updated.Manufacturer, &updated.Brand, &updated.Taxable, &updated.Cost, &updated.ProductWeight, &updated.ProductHeight, &updated.ProductWidth, &updated.ProductLength, &updated.PackageWeight, &updated.PackageHeight, &updated.PackageWidth, &updated.PackageLength, &updated.QuantityPerPackage, &updated.AvailableOn, &updated.ID).Scan(&t)
 	return t, err
 }
 
 const productRootDeletionQuery = `
+    WITH archived AS (
     UPDATE product_roots
     SET archived_on = NOW()
     WHERE id = $1
-    RETURNING archived_on
+    RETURNING *
+    ), outboxed AS (
+        INSERT INTO outbox_events (event_type, payload)
+        SELECT 'product_root_archived', to_jsonb(archived) FROM archived
+    )
+    SELECT archived_on FROM archived
 `
 
 func (pg *postgres) DeleteProductRoot(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteProductRoot", time.Now(), &err, &t, id)
 	err = db.QueryRow(productRootDeletionQuery, id).Scan(&t)
 	return t, err
 }
@@ -533,3 +533,16 @@ func TestDeleteProductRootByID(t *testing.T) {
 		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
 	})
 }
+
+func TestProductRootWritesAppendOutboxEvents(t *testing.T) {
+	t.Parallel()
+
+	for query, eventType := range map[string]string{
+		productRootCreationQuery: "product_root_created",
+		productRootUpdateQuery:   "product_root_updated",
+		productRootDeletionQuery: "product_root_archived",
+	} {
+		assert.Contains(t, query, "INSERT INTO outbox_events", "productroot writes should append to the outbox in the same statement")
+		assert.Contains(t, query, "'"+eventType+"'", "productroot write should record a %s event", eventType)
+	}
+}
@@ -13,10 +13,11 @@ import (
 
 const productVariantBridgeExistenceQuery = `SELECT EXISTS(SELECT id FROM product_variant_bridge WHERE id = $1 and archived_on IS NULL);`
 
-func (pg *postgres) ProductVariantBridgeExists(db database.Querier, id uint64) (bool, error) {
+func (pg *postgres) ProductVariantBridgeExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("ProductVariantBridgeExists", time.Now(), &err, &result, id)
 	var exists string
 
-	err := db.QueryRow(productVariantBridgeExistenceQuery, id).Scan(&exists)
+	err = db.QueryRow(productVariantBridgeExistenceQuery, id).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -41,10 +42,11 @@ const productVariantBridgeSelectionQuery = `
         id = $1
 `
 
-func (pg *postgres) GetProductVariantBridge(db database.Querier, id uint64) (*models.ProductVariantBridge, error) {
+func (pg *postgres) GetProductVariantBridge(db database.Querier, id uint64) (result *models.ProductVariantBridge, err error) {
+	defer pg.observe("GetProductVariantBridge", time.Now(), &err, &result, id)
 	p := &models.ProductVariantBridge{}
 
-	err := db.QueryRow(productVariantBridgeSelectionQuery, id).Scan(&p.ID, &p.ProductID, &p.ProductOptionValueID, &p.CreatedOn, &p.ArchivedOn)
+	err = db.QueryRow(productVariantBridgeSelectionQuery, id).Scan(&p.ID, &p.ProductID, &p.ProductOptionValueID, &p.CreatedOn, &p.ArchivedOn)
 
 	return p, err
 }
@@ -65,7 +67,8 @@ func buildProductVariantBridgeListRetrievalQuery(qf *models.QueryFilter) (string
 	return query, args
 }
 
-func (pg *postgres) GetProductVariantBridgeList(db database.Querier, qf *models.QueryFilter) ([]models.ProductVariantBridge, error) {
+func (pg *postgres) GetProductVariantBridgeList(db database.Querier, qf *models.QueryFilter) (result []models.ProductVariantBridge, err error) {
+	defer pg.observe("GetProductVariantBridgeList", time.Now(), &err, &result, qf)
 	var list []models.ProductVariantBridge
 	query, args := buildProductVariantBridgeListRetrievalQuery(qf)
 
@@ -105,10 +108,11 @@ func buildProductVariantBridgeCountRetrievalQuery(qf *models.QueryFilter) (strin
 	return query, args
 }
 
-func (pg *postgres) GetProductVariantBridgeCount(db database.Querier, qf *models.QueryFilter) (uint64, error) {
+func (pg *postgres) GetProductVariantBridgeCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetProductVariantBridgeCount", time.Now(), &err, nil, qf)
 	var count uint64
 	query, args := buildProductVariantBridgeCountRetrievalQuery(qf)
-	err := db.QueryRow(query, args...).Scan(&count)
+	err = db.QueryRow(query, args...).Scan(&count)
 	return count, err
 }
 
@@ -126,6 +130,7 @@ const productVariantBridgeCreationQuery = `
 `
 
 func (pg *postgres) CreateProductVariantBridge(db database.Querier, nu *models.ProductVariantBridge) (createdID uint64, createdOn time.Time, err error) {
+	defer pg.observe("CreateProductVariantBridge", time.Now(), &err, &createdID, nu)
 	err = db.QueryRow(productVariantBridgeCreationQuery, &nu.ProductID, &nu.ProductOptionValueID).Scan(&createdID, &createdOn)
 	return createdID, createdOn, err
 }
@@ -168,9 +173,10 @@ func buildMultiProductVariantBridgeCreationQuery(productID uint64, optionValueID
 	return query, values
 }
 
-func (pg *postgres) CreateMultipleProductVariantBridgesForProductID(db database.Querier, productID uint64, optionValueIDs []uint64) error {
+func (pg *postgres) CreateMultipleProductVariantBridgesForProductID(db database.Querier, productID uint64, optionValueIDs []uint64) (err error) {
+	defer pg.observe("CreateMultipleProductVariantBridgesForProductID", time.Now(), &err, nil, productID, optionValueIDs)
 	query, args := buildMultiProductVariantBridgeCreationQuery(productID, optionValueIDs)
-	_, err := db.Exec(query, args...)
+	_, err = db.Exec(query, args...)
 	return err
 }
 
@@ -184,9 +190,10 @@ const productVariantBridgeUpdateQuery = `
     RETURNING updated_on;
 `
 
-func (pg *postgres) UpdateProductVariantBridge(db database.Querier, updated *models.ProductVariantBridge) (time.Time, error) {
+func (pg *postgres) UpdateProductVariantBridge(db database.Querier, updated *models.ProductVariantBridge) (result time.Time, err error) {
+	defer pg.observe("UpdateProductVariantBridge", time.Now(), &err, &result, updated)
 	var t time.Time
-	err := db.QueryRow(productVariantBridgeUpdateQuery, &updated.ProductID, &updated.ProductOptionValueID, &updated.ID).Scan(&t)
+	err = db.QueryRow(productVariantBridgeUpdateQuery, &updated.ProductID, &updated.ProductOptionValueID, &updated.ID).Scan(&t)
 	return t, err
 }
 
@@ -198,6 +205,7 @@ const productVariantBridgeDeletionQuery = `
 `
 
 func (pg *postgres) DeleteProductVariantBridge(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteProductVariantBridge", time.Now(), &err, &t, id)
 	err = db.QueryRow(productVariantBridgeDeletionQuery, id).Scan(&t)
 	return t, err
 }
@@ -209,6 +217,7 @@ const productVariantBridgeWithProductRootIDDeletionQuery = `
 `
 
 func (pg *postgres) ArchiveProductVariantBridgesWithProductRootID(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("ArchiveProductVariantBridgesWithProductRootID", time.Now(), &err, &t, id)
 	err = db.QueryRow(productVariantBridgeWithProductRootIDDeletionQuery, id).Scan(&t)
 	return t, err
 }
@@ -218,6 +227,7 @@ const productVariantBridgeDeletionQueryByProductID = `
 `
 
 func (pg *postgres) DeleteProductVariantBridgeByProductID(db database.Querier, productID uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteProductVariantBridgeByProductID", time.Now(), &err, &t, productID)
 	err = db.QueryRow(productVariantBridgeDeletionQueryByProductID, productID).Scan(&t)
 	return t, err
 }
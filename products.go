@@ -41,7 +41,16 @@ const productQueryBySKU = `
         available_on,
         created_on,
         updated_on,
-        archived_on
+        archived_on,
+        sale_starts_on,
+        sale_ends_on,
+        CASE
+            WHEN on_sale
+            AND (sale_starts_on IS NULL OR sale_starts_on <= NOW())
+            AND (sale_ends_on IS NULL OR sale_ends_on > NOW())
+            THEN sale_price
+            ELSE price
+        END AS effective_price
     FROM
         products
     WHERE
@@ -50,20 +59,22 @@ const productQueryBySKU = `
         sku = $1
 `
 
-func (pg *postgres) GetProductBySKU(db database.Querier, sku string) (*models.Product, error) {
+func (pg *postgres) GetProductBySKU(db database.Querier, sku string) (result *models.Product, err error) {
+	defer pg.observe("GetProductBySKU", time.Now(), &err, &result, sku)
 	p := &models.Product{}
 
-	err := db.QueryRow(productQueryBySKU, sku).Scan(&p.ID, &p.ProductRootID, &p.PrimaryImageID, &p.Name, &p.Subtitle, &p.Description, &p.OptionSummary, &p.SKU, &p.UPC, &p.Manufacturer, &p.Brand, &p.Quantity, &p.Taxable, &p.Price, &p.OnSale, &p.SalePrice, &p.Cost, &p.ProductWeight, &p.ProductHeight, &p.ProductWidth, &p.ProductLength, &p.PackageWeight, &p.PackageHeight, &p.PackageWidth, &p.PackageLength, &p.QuantityPerPackage, &p.AvailableOn, &p.CreatedOn, &p.UpdatedOn, &p.ArchivedOn)
+	err = db.QueryRow(productQueryBySKU, sku).Scan(&p.ID, &p.ProductRootID, &p.PrimaryImageID, &p.Name, &p.Subtitle, &p.Description, &p.OptionSummary, &p.SKU, &p.UPC, &p.Manufacturer, &p.Brand, &p.Quantity, &p.Taxable, &p.Price, &p.OnSale, &p.SalePrice, &p.Cost, &p.ProductWeight, &p.ProductHeight, &p.ProductWidth, &p.ProductLength, &p.PackageWeight, &p.PackageHeight, &p.PackageWidth, &p.PackageLength, &p.QuantityPerPackage, &p.AvailableOn, &p.CreatedOn, &p.UpdatedOn, &p.ArchivedOn, &p.SaleStartsOn, &p.SaleEndsOn, &p.EffectivePrice)
 
 	return p, err
 }
 
 const productWithSKUExistenceQuery = `SELECT EXISTS(SELECT id FROM products WHERE sku = $1 and archived_on IS NULL);`
 
-func (pg *postgres) ProductWithSKUExists(db database.Querier, sku string) (bool, error) {
+func (pg *postgres) ProductWithSKUExists(db database.Querier, sku string) (result bool, err error) {
+	defer pg.observe("ProductWithSKUExists", time.Now(), &err, &result, sku)
 	var exists string
 
-	err := db.QueryRow(productWithSKUExistenceQuery, sku).Scan(&exists)
+	err = db.QueryRow(productWithSKUExistenceQuery, sku).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -73,6 +84,120 @@ func (pg *postgres) ProductWithSKUExists(db database.Querier, sku string) (bool,
 	return exists == "true", err
 }
 
+const productSaleTransitionsQuery = `
+    SELECT
+        id,
+        product_root_id,
+        primary_image_id,
+        name,
+        subtitle,
+        description,
+        option_summary,
+        sku,
+        upc,
+        manufacturer,
+        brand,
+        quantity,
+        taxable,
+        price,
+        on_sale,
+        sale_price,
+        cost,
+        product_weight,
+        product_height,
+        product_width,
+        product_length,
+        package_weight,
+        package_height,
+        package_width,
+        package_length,
+        quantity_per_package,
+        available_on,
+        created_on,
+        updated_on,
+        archived_on,
+        sale_starts_on,
+        sale_ends_on,
+        CASE
+            WHEN on_sale
+            AND (sale_starts_on IS NULL OR sale_starts_on <= NOW())
+            AND (sale_ends_on IS NULL OR sale_ends_on > NOW())
+            THEN sale_price
+            ELSE price
+        END AS effective_price
+    FROM
+        products
+    WHERE
+        archived_on is null
+    AND
+        on_sale IS TRUE
+    AND
+        (
+            (sale_starts_on >= $1 AND sale_starts_on < $2)
+            OR
+            (sale_ends_on >= $1 AND sale_ends_on < $2)
+        )
+`
+
+func (pg *postgres) GetProductsWithSaleTransitions(db database.Querier, from time.Time, to time.Time) (result []models.Product, err error) {
+	defer pg.observe("GetProductsWithSaleTransitions", time.Now(), &err, &result, from, to)
+	var list []models.Product
+
+	rows, err := db.Query(productSaleTransitionsQuery, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p models.Product
+		err := rows.Scan(
+			&p.ID,
+			&p.ProductRootID,
+			&p.PrimaryImageID,
+			&p.Name,
+			&p.Subtitle,
+			&p.Description,
+			&p.OptionSummary,
+			&p.SKU,
+			&p.UPC,
+			&p.Manufacturer,
+			&p.Brand,
+			&p.Quantity,
+			&p.Taxable,
+			&p.Price,
+			&p.OnSale,
+			&p.SalePrice,
+			&p.Cost,
+			&p.ProductWeight,
+			&p.ProductHeight,
+			&p.ProductWidth,
+			&p.ProductLength,
+			&p.PackageWeight,
+			&p.PackageHeight,
+			&p.PackageWidth,
+			&p.PackageLength,
+			&p.QuantityPerPackage,
+			&p.AvailableOn,
+			&p.CreatedOn,
+			&p.UpdatedOn,
+			&p.ArchivedOn,
+			&p.SaleStartsOn,
+			&p.SaleEndsOn,
+			&p.EffectivePrice,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
 const productQueryByProductRootID = `
     SELECT
         id,
@@ -104,14 +229,24 @@ const productQueryByProductRootID = `
         available_on,
         created_on,
         updated_on,
-        archived_on
+        archived_on,
+        sale_starts_on,
+        sale_ends_on,
+        CASE
+            WHEN on_sale
+            AND (sale_starts_on IS NULL OR sale_starts_on <= NOW())
+            AND (sale_ends_on IS NULL OR sale_ends_on > NOW())
+            THEN sale_price
+            ELSE price
+        END AS effective_price
     FROM
         products
     WHERE
         product_root_id = $1
 `
 
-func (pg *postgres) GetProductsByProductRootID(db database.Querier, productRootID uint64) ([]models.Product, error) {
+func (pg *postgres) GetProductsByProductRootID(db database.Querier, productRootID uint64) (result []models.Product, err error) {
+	defer pg.observe("GetProductsByProductRootID", time.Now(), &err, &result, productRootID)
 	var list []models.Product
 
 	rows, err := db.Query(productQueryByProductRootID, productRootID)
@@ -152,6 +287,9 @@ func (pg *postgres) GetProductsByProductRootID(db database.Querier, productRootI
 			&p.CreatedOn,
 			&p.UpdatedOn,
 			&p.ArchivedOn,
+			&p.SaleStartsOn,
+			&p.SaleEndsOn,
+			&p.EffectivePrice,
 		)
 		if err != nil {
 			return nil, err
@@ -168,10 +306,11 @@ func (pg *postgres) GetProductsByProductRootID(db database.Querier, productRootI
 
 const productExistenceQuery = `SELECT EXISTS(SELECT id FROM products WHERE id = $1 and archived_on IS NULL);`
 
-func (pg *postgres) ProductExists(db database.Querier, id uint64) (bool, error) {
+func (pg *postgres) ProductExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("ProductExists", time.Now(), &err, &result, id)
 	var exists string
 
-	err := db.QueryRow(productExistenceQuery, id).Scan(&exists)
+	err = db.QueryRow(productExistenceQuery, id).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -212,7 +351,16 @@ const productSelectionQuery = `
         available_on,
         created_on,
         updated_on,
-        archived_on
+        archived_on,
+        sale_starts_on,
+        sale_ends_on,
+        CASE
+            WHEN on_sale
+            AND (sale_starts_on IS NULL OR sale_starts_on <= NOW())
+            AND (sale_ends_on IS NULL OR sale_ends_on > NOW())
+            THEN sale_price
+            ELSE price
+        END AS effective_price
     FROM
         products
     WHERE
@@ -221,10 +369,11 @@ const productSelectionQuery = `
         id = $1
 `
 
-func (pg *postgres) GetProduct(db database.Querier, id uint64) (*models.Product, error) {
+func (pg *postgres) GetProduct(db database.Querier, id uint64) (result *models.Product, err error) {
+	defer pg.observe("GetProduct", time.Now(), &err, &result, id)
 	p := &models.Product{}
 
-	err := db.QueryRow(productSelectionQuery, id).Scan(&p.ID, &p.ProductRootID, &p.PrimaryImageID, &p.Name, &p.Subtitle, &p.Description, &p.OptionSummary, &p.SKU, &p.UPC, &p.Manufacturer, &p.Brand, &p.Quantity, &p.Taxable, &p.Price, &p.OnSale, &p.SalePrice, &p.Cost, &p.ProductWeight, &p.ProductHeight, &p.ProductWidth, &p.ProductLength, &p.PackageWeight, &p.PackageHeight, &p.PackageWidth, &p.PackageLength, &p.QuantityPerPackage, &p.AvailableOn, &p.CreatedOn, &p.UpdatedOn, &p.ArchivedOn)
+	err = db.QueryRow(productSelectionQuery, id).Scan(&p.ID, &p.ProductRootID, &p.PrimaryImageID, &p.Name, &p.Subtitle, &p.Description, &p.OptionSummary, &p.SKU, &p.UPC, &p.Manufacturer, &p.Brand, &p.Quantity, &p.Taxable, &p.Price, &p.OnSale, &p.SalePrice, &p.Cost, &p.ProductWeight, &p.ProductHeight, &p.ProductWidth, &p.ProductLength, &p.PackageWeight, &p.PackageHeight, &p.PackageWidth, &p.PackageLength, &p.QuantityPerPackage, &p.AvailableOn, &p.CreatedOn, &p.UpdatedOn, &p.ArchivedOn, &p.SaleStartsOn, &p.SaleEndsOn, &p.EffectivePrice)
 
 	return p, err
 }
@@ -263,14 +412,24 @@ func buildProductListRetrievalQuery(qf *models.QueryFilter) (string, []interface
 			"created_on",
 			"updated_on",
 			"archived_on",
+			"sale_starts_on",
+			"sale_ends_on",
 		).
+		Column(`CASE
+            WHEN on_sale
+            AND (sale_starts_on IS NULL OR sale_starts_on <= NOW())
+            AND (sale_ends_on IS NULL OR sale_ends_on > NOW())
+            THEN sale_price
+            ELSE price
+        END AS effective_price`).
 		From("products")
 
 	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, true).ToSql()
 	return query, args
 }
 
-func (pg *postgres) GetProductList(db database.Querier, qf *models.QueryFilter) ([]models.Product, error) {
+func (pg *postgres) GetProductList(db database.Querier, qf *models.QueryFilter) (result []models.Product, err error) {
+	defer pg.observe("GetProductList", time.Now(), &err, &result, qf)
 	var list []models.Product
 	query, args := buildProductListRetrievalQuery(qf)
 
@@ -312,6 +471,163 @@ func (pg *postgres) GetProductList(db database.Querier, qf *models.QueryFilter)
 			&p.CreatedOn,
 			&p.UpdatedOn,
 			&p.ArchivedOn,
+			&p.SaleStartsOn,
+			&p.SaleEndsOn,
+			&p.EffectivePrice,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+func buildProductListRetrievalQueryForCurrency(qf *models.QueryFilter, currency string) (string, []interface{}) {
+	sqlBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	queryBuilder := sqlBuilder.
+		Select(
+			"id",
+			"product_root_id",
+			"primary_image_id",
+			"name",
+			"subtitle",
+			"description",
+			"option_summary",
+			"sku",
+			"upc",
+			"manufacturer",
+			"brand",
+			"quantity",
+			"taxable",
+		).
+		Column(`COALESCE(
+            (
+                SELECT product_prices.price
+                FROM product_prices
+                WHERE product_prices.product_id = products.id
+                AND product_prices.currency = ?
+                AND product_prices.archived_on IS NULL
+                AND product_prices.starts_on <= NOW()
+                AND (product_prices.expires_on IS NULL OR product_prices.expires_on > NOW())
+                ORDER BY product_prices.starts_on DESC
+                LIMIT 1
+            ),
+            products.price
+        ) AS price`, currency).
+		Columns(
+			"on_sale",
+			"sale_price",
+			"cost",
+			"product_weight",
+			"product_height",
+			"product_width",
+			"product_length",
+			"package_weight",
+			"package_height",
+			"package_width",
+			"package_length",
+			"quantity_per_package",
+			"available_on",
+			"created_on",
+			"updated_on",
+			"archived_on",
+			"sale_starts_on",
+			"sale_ends_on",
+		).
+		Column(`CASE
+            WHEN on_sale
+            AND (sale_starts_on IS NULL OR sale_starts_on <= NOW())
+            AND (sale_ends_on IS NULL OR sale_ends_on > NOW())
+            THEN COALESCE(
+                ROUND((
+                SELECT product_prices.price
+                FROM product_prices
+                WHERE product_prices.product_id = products.id
+                AND product_prices.currency = ?
+                AND product_prices.archived_on IS NULL
+                AND product_prices.starts_on <= NOW()
+                AND (product_prices.expires_on IS NULL OR product_prices.expires_on > NOW())
+                ORDER BY product_prices.starts_on DESC
+                LIMIT 1
+            ) * sale_price / NULLIF(products.price, 0), 2),
+                sale_price
+            )
+            ELSE COALESCE(
+                (
+                SELECT product_prices.price
+                FROM product_prices
+                WHERE product_prices.product_id = products.id
+                AND product_prices.currency = ?
+                AND product_prices.archived_on IS NULL
+                AND product_prices.starts_on <= NOW()
+                AND (product_prices.expires_on IS NULL OR product_prices.expires_on > NOW())
+                ORDER BY product_prices.starts_on DESC
+                LIMIT 1
+            ),
+                products.price
+            )
+        END AS effective_price`, currency, currency).
+		From("products")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, true).ToSql()
+	return query, args
+}
+
+// GetProductListForCurrency lists products priced in currency where a price is in
+// effect for it, and in the base currency otherwise. A sale in effect takes
+// the same share off the currency price as it does off the base price.
+func (pg *postgres) GetProductListForCurrency(db database.Querier, qf *models.QueryFilter, currency string) (result []models.Product, err error) {
+	defer pg.observe("GetProductListForCurrency", time.Now(), &err, &result, qf, currency)
+	var list []models.Product
+	query, args := buildProductListRetrievalQueryForCurrency(qf, currency)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p models.Product
+		err := rows.Scan(
+			&p.ID,
+			&p.ProductRootID,
+			&p.PrimaryImageID,
+			&p.Name,
+			&p.Subtitle,
+			&p.Description,
+			&p.OptionSummary,
+			&p.SKU,
+			&p.UPC,
+			&p.Manufacturer,
+			&p.Brand,
+			&p.Quantity,
+			&p.Taxable,
+			&p.Price,
+			&p.OnSale,
+			&p.SalePrice,
+			&p.Cost,
+			&p.ProductWeight,
+			&p.ProductHeight,
+			&p.ProductWidth,
+			&p.ProductLength,
+			&p.PackageWeight,
+			&p.PackageHeight,
+			&p.PackageWidth,
+			&p.PackageLength,
+			&p.QuantityPerPackage,
+			&p.AvailableOn,
+			&p.CreatedOn,
+			&p.UpdatedOn,
+			&p.ArchivedOn,
+			&p.SaleStartsOn,
+			&p.SaleEndsOn,
+			&p.EffectivePrice,
 		)
 		if err != nil {
 			return nil, err
@@ -335,32 +651,45 @@ func buildProductCountRetrievalQuery(qf *models.QueryFilter) (string, []interfac
 	return query, args
 }
 
-func (pg *postgres) GetProductCount(db database.Querier, qf *models.QueryFilter) (uint64, error) {
+func (pg *postgres) GetProductCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetProductCount", time.Now(), &err, nil, qf)
 	var count uint64
 	query, args := buildProductCountRetrievalQuery(qf)
-	err := db.QueryRow(query, args...).Scan(&count)
+	err = db.QueryRow(query, args...).Scan(&count)
 	return count, err
 }
 
 const productCreationQuery = `
+    WITH created AS (
     INSERT INTO products
         (
-            product_root_id, primary_image_id, name, subtitle, description, option_summary, sku, upc, manufacturer, brand, quantity, taxable, price, on_sale, sale_price, cost, product_weight, product_height, product_width, product_length, package_weight, package_height, package_width, package_length, quantity_per_package, available_on
+            product_root_id, primary_image_id, name, subtitle, description, option_summary, sku, upc, manufacturer, brand, quantity, taxable, price, on_sale, sale_price, cost, product_weight, product_height, product_width, product_length, package_weight, package_height, package_width, package_length, quantity_per_package, available_on, sale_starts_on, sale_ends_on
         )
     VALUES
         (
-            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28
         )
     RETURNING
-        id, created_on, available_on;
+        *
+    ), outboxed AS (
+        INSERT INTO outbox_events (event_type, payload)
+        SELECT 'product_created', to_jsonb(created) FROM created
+    )
+    SELECT
+        id, created_on, available_on
+    FROM created;
 `
 
 func (pg *postgres) CreateProduct(db database.Querier, nu *models.Product) (createdID uint64, createdOn time.Time, availableOn time.Time, err error) {
-	err = db.QueryRow(productCreationQuery, &nu.ProductRootID, &nu.PrimaryImageID, &nu.Name, &nu.Subtitle, &nu.Description, &nu.OptionSummary, &nu.SKU, &nu.UPC, &nu.Manufacturer, &nu.Brand, &nu.Quantity, &nu.Taxable, &nu.Price, &nu.OnSale, &nu.SalePrice, &nu.Cost, &nu.ProductWeight, &nu.ProductHeight, &nu.ProductWidth, &nu.ProductLength, &nu.PackageWeight, &nu.PackageHeight, &nu.PackageWidth, &nu.PackageLength, &nu.QuantityPerPackage, &nu.AvailableOn).Scan(&createdID, &createdOn, &availableOn)
+	defer pg.observe("CreateProduct", time.Now(), &err, &createdID, nu)
+	err = db.QueryRow(productCreationQuery, &nu.ProductRootID, &nu.PrimaryImageID, &nu.Name, &nu.Subtitle, &nu.Description, &nu.OptionSummary, &nu.SKU, &nu.UPC, &nu.Manufacturer, &nu.Brand, &nu.Quantity, &nu.Taxable, &nu.Price, &nu.OnSale, &nu.SalePrice, &nu.Cost, &nu.ProductWeight, &nu.ProductHeight, &nu.ProductWidth, &nu.ProductLength, &nu.PackageWeight, &nu.PackageHeight, &nu.PackageWidth, &nu.PackageLength, &nu.QuantityPerPackage, &nu.AvailableOn, &nu.SaleStartsOn, &nu.SaleEndsOn).Scan(&createdID, &createdOn, &availableOn)
 	return createdID, createdOn, availableOn, err
 }
 
 const productUpdateQuery = `
+    WITH previous AS (
+        SELECT id, quantity FROM products WHERE id = $29
+    ), updated AS (
     UPDATE products
     SET
         product_root_id = $1,
@@ -389,25 +718,44 @@ const productUpdateQuery = `
         package_length = $24,
         quantity_per_package = $25,
         available_on = $26,
+        sale_starts_on = $27,
+        sale_ends_on = $28,
         updated_on = NOW()
-    WHERE id = $27
-    RETURNING updated_on;
+    WHERE id = $29
+    RETURNING *
+    ), outboxed AS (
+        INSERT INTO outbox_events (event_type, payload)
+        SELECT 'product_updated', to_jsonb(updated) FROM updated
+        UNION ALL
+        SELECT 'inventory_changed', jsonb_build_object('product_id', updated.id, 'previous_quantity', previous.quantity, 'quantity', updated.quantity)
+        FROM updated JOIN previous ON previous.id = updated.id
+        WHERE previous.quantity IS DISTINCT FROM updated.quantity
+    )
+    SELECT updated_on FROM updated;
 `
 
-func (pg *postgres) UpdateProduct(db database.Querier, updated *models.Product) (time.Time, error) {
+func (pg *postgres) UpdateProduct(db database.Querier, updated *models.Product) (result time.Time, err error) {
+	defer pg.observe("UpdateProduct", time.Now(), &err, &result, updated)
 	var t time.Time
-	err := db.QueryRow(productUpdateQuery, &updated.ProductRootID, &updated.PrimaryImageID, &updated.Name, &updated.Subtitle, &updated.Description, &updated.OptionSummary, &updated.SKU, &updated.UPC, &updated.Manufacturer, &updated.Brand, &updated.Quantity, &updated.Taxable, &updated.Price, &updated.OnSale, &updated.SalePrice, &updated.Cost, &updated.ProductWeight, &updated.ProductHeight, &updated.ProductWidth, &updated.ProductLength, &updated.PackageWeight, &updated.PackageHeight, &updated.PackageWidth, &updated.PackageLength, &updated.QuantityPerPackage, &updated.AvailableOn, &updated.ID).Scan(&t)
+	err = db.QueryRow(productUpdateQuery, &updated.ProductRootID, &updated.PrimaryImageID, &updated.Name, &updated.Subtitle, &updated.Description, &updated.OptionSummary, &updated.SKU, &updated.UPC, &updated.Manufacturer, &updated.Brand, &updated.Quantity, &updated.Taxable, &updated.Price, &updated.OnSale, &updated.SalePrice, &updated.Cost, &updated.ProductWeight, &updated.ProductHeight, &updated.ProductWidth, &updated.ProductLength, &updated.PackageWeight, &updated.PackageHeight, &updated.PackageWidth, &updated.PackageLength, &updated.QuantityPerPackage, &updated.AvailableOn, &updated.SaleStartsOn, &updated.SaleEndsOn, &updated.ID).Scan(&t)
 	return t, err
 }
 
 const productDeletionQuery = `
+    WITH archived AS (
     UPDATE products
     SET archived_on = NOW()
     WHERE id = $1
-    RETURNING archived_on
+    RETURNING *
+    ), outboxed AS (
+        INSERT INTO outbox_events (event_type, payload)
+        SELECT 'product_archived', to_jsonb(archived) FROM archived
+    )
+    SELECT archived_on FROM archived
 `
 
 func (pg *postgres) DeleteProduct(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteProduct", time.Now(), &err, &t, id)
 	err = db.QueryRow(productDeletionQuery, id).Scan(&t)
 	return t, err
 }
@@ -420,6 +768,7 @@ const productWithProductRootIDDeletionQuery = `
 `
 
 func (pg *postgres) ArchiveProductsWithProductRootID(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("ArchiveProductsWithProductRootID", time.Now(), &err, &t, id)
 	err = db.QueryRow(productWithProductRootIDDeletionQuery, id).Scan(&t)
 	return t, err
 }
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"strconv"
 	"testing"
+	"time"
 
 	// internal dependencies
 	"github.com/dairycart/dairymodels/v1"
@@ -49,6 +50,9 @@ func setProductReadQueryExpectationBySKU(t *testing.T, mock sqlmock.Sqlmock, sku
 		"created_on",
 		"updated_on",
 		"archived_on",
+		"sale_starts_on",
+		"sale_ends_on",
+		"effective_price",
 	}).AddRow(
 		toReturn.ID,
 		toReturn.ProductRootID,
@@ -80,6 +84,9 @@ func setProductReadQueryExpectationBySKU(t *testing.T, mock sqlmock.Sqlmock, sku
 		toReturn.CreatedOn,
 		toReturn.UpdatedOn,
 		toReturn.ArchivedOn,
+		toReturn.SaleStartsOn,
+		toReturn.SaleEndsOn,
+		toReturn.EffectivePrice,
 	)
 	mock.ExpectQuery(query).WithArgs(sku).WillReturnRows(exampleRows).WillReturnError(err)
 }
@@ -135,6 +142,9 @@ func setProductReadQueryExpectationByProductRootID(t *testing.T, mock sqlmock.Sq
 		"created_on",
 		"updated_on",
 		"archived_on",
+		"sale_starts_on",
+		"sale_ends_on",
+		"effective_price",
 	}).AddRow(
 		example.ID,
 		example.ProductRootID,
@@ -166,6 +176,9 @@ func setProductReadQueryExpectationByProductRootID(t *testing.T, mock sqlmock.Sq
 		example.CreatedOn,
 		example.UpdatedOn,
 		example.ArchivedOn,
+		example.SaleStartsOn,
+		example.SaleEndsOn,
+		example.EffectivePrice,
 	).AddRow(
 		example.ID,
 		example.ProductRootID,
@@ -197,6 +210,9 @@ func setProductReadQueryExpectationByProductRootID(t *testing.T, mock sqlmock.Sq
 		example.CreatedOn,
 		example.UpdatedOn,
 		example.ArchivedOn,
+		example.SaleStartsOn,
+		example.SaleEndsOn,
+		example.EffectivePrice,
 	).AddRow(
 		example.ID,
 		example.ProductRootID,
@@ -228,6 +244,9 @@ func setProductReadQueryExpectationByProductRootID(t *testing.T, mock sqlmock.Sq
 		example.CreatedOn,
 		example.UpdatedOn,
 		example.ArchivedOn,
+		example.SaleStartsOn,
+		example.SaleEndsOn,
+		example.EffectivePrice,
 	).RowError(1, rowErr)
 
 	mock.ExpectQuery(formatQueryForSQLMock(productQueryByProductRootID)).
@@ -330,6 +349,201 @@ func TestProductWithSKUExists(t *testing.T) {
 		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
 	})
 }
+func setProductSaleTransitionsQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, from time.Time, to time.Time, example *models.Product, rowErr error, err error) {
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"product_root_id",
+		"primary_image_id",
+		"name",
+		"subtitle",
+		"description",
+		"option_summary",
+		"sku",
+		"upc",
+		"manufacturer",
+		"brand",
+		"quantity",
+		"taxable",
+		"price",
+		"on_sale",
+		"sale_price",
+		"cost",
+		"product_weight",
+		"product_height",
+		"product_width",
+		"product_length",
+		"package_weight",
+		"package_height",
+		"package_width",
+		"package_length",
+		"quantity_per_package",
+		"available_on",
+		"created_on",
+		"updated_on",
+		"archived_on",
+		"sale_starts_on",
+		"sale_ends_on",
+		"effective_price",
+	}).AddRow(
+		example.ID,
+		example.ProductRootID,
+		example.PrimaryImageID,
+		example.Name,
+		example.Subtitle,
+		example.Description,
+		example.OptionSummary,
+		example.SKU,
+		example.UPC,
+		example.Manufacturer,
+		example.Brand,
+		example.Quantity,
+		example.Taxable,
+		example.Price,
+		example.OnSale,
+		example.SalePrice,
+		example.Cost,
+		example.ProductWeight,
+		example.ProductHeight,
+		example.ProductWidth,
+		example.ProductLength,
+		example.PackageWeight,
+		example.PackageHeight,
+		example.PackageWidth,
+		example.PackageLength,
+		example.QuantityPerPackage,
+		example.AvailableOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+		example.SaleStartsOn,
+		example.SaleEndsOn,
+		example.EffectivePrice,
+	).AddRow(
+		example.ID,
+		example.ProductRootID,
+		example.PrimaryImageID,
+		example.Name,
+		example.Subtitle,
+		example.Description,
+		example.OptionSummary,
+		example.SKU,
+		example.UPC,
+		example.Manufacturer,
+		example.Brand,
+		example.Quantity,
+		example.Taxable,
+		example.Price,
+		example.OnSale,
+		example.SalePrice,
+		example.Cost,
+		example.ProductWeight,
+		example.ProductHeight,
+		example.ProductWidth,
+		example.ProductLength,
+		example.PackageWeight,
+		example.PackageHeight,
+		example.PackageWidth,
+		example.PackageLength,
+		example.QuantityPerPackage,
+		example.AvailableOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+		example.SaleStartsOn,
+		example.SaleEndsOn,
+		example.EffectivePrice,
+	).AddRow(
+		example.ID,
+		example.ProductRootID,
+		example.PrimaryImageID,
+		example.Name,
+		example.Subtitle,
+		example.Description,
+		example.OptionSummary,
+		example.SKU,
+		example.UPC,
+		example.Manufacturer,
+		example.Brand,
+		example.Quantity,
+		example.Taxable,
+		example.Price,
+		example.OnSale,
+		example.SalePrice,
+		example.Cost,
+		example.ProductWeight,
+		example.ProductHeight,
+		example.ProductWidth,
+		example.ProductLength,
+		example.PackageWeight,
+		example.PackageHeight,
+		example.PackageWidth,
+		example.PackageLength,
+		example.QuantityPerPackage,
+		example.AvailableOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+		example.SaleStartsOn,
+		example.SaleEndsOn,
+		example.EffectivePrice,
+	).RowError(1, rowErr)
+
+	mock.ExpectQuery(formatQueryForSQLMock(productSaleTransitionsQuery)).
+		WithArgs(from, to).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestGetProductsWithSaleTransitions(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+
+	exampleFrom := buildTestTime(t)
+	exampleTo := exampleFrom.Add(time.Hour)
+	example := &models.Product{OnSale: true, SalePrice: 9.99, EffectivePrice: 9.99}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setProductSaleTransitionsQueryExpectation(t, mock, exampleFrom, exampleTo, example, nil, nil)
+		actual, err := client.GetProductsWithSaleTransitions(mockDB, exampleFrom, exampleTo)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, actual, "list retrieval method should not return an empty slice")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error executing query", func(t *testing.T) {
+		setProductSaleTransitionsQueryExpectation(t, mock, exampleFrom, exampleTo, example, nil, errors.New("pineapple on pizza"))
+		actual, err := client.GetProductsWithSaleTransitions(mockDB, exampleFrom, exampleTo)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error scanning values", func(t *testing.T) {
+		exampleRows := sqlmock.NewRows([]string{"things"}).AddRow("stuff")
+		mock.ExpectQuery(formatQueryForSQLMock(productSaleTransitionsQuery)).
+			WillReturnRows(exampleRows)
+
+		actual, err := client.GetProductsWithSaleTransitions(mockDB, exampleFrom, exampleTo)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with with row errors", func(t *testing.T) {
+		setProductSaleTransitionsQueryExpectation(t, mock, exampleFrom, exampleTo, example, errors.New("pineapple on pizza"), nil)
+		actual, err := client.GetProductsWithSaleTransitions(mockDB, exampleFrom, exampleTo)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
 
 func setProductExistenceQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, shouldExist bool, err error) {
 	t.Helper()
@@ -412,6 +626,9 @@ func setProductReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint6
 		"created_on",
 		"updated_on",
 		"archived_on",
+		"sale_starts_on",
+		"sale_ends_on",
+		"effective_price",
 	}).AddRow(
 		toReturn.ID,
 		toReturn.ProductRootID,
@@ -443,6 +660,9 @@ func setProductReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint6
 		toReturn.CreatedOn,
 		toReturn.UpdatedOn,
 		toReturn.ArchivedOn,
+		toReturn.SaleStartsOn,
+		toReturn.SaleEndsOn,
+		toReturn.EffectivePrice,
 	)
 	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
 }
@@ -498,6 +718,9 @@ func setProductListReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, qf *
 		"created_on",
 		"updated_on",
 		"archived_on",
+		"sale_starts_on",
+		"sale_ends_on",
+		"effective_price",
 	}).AddRow(
 		example.ID,
 		example.ProductRootID,
@@ -529,6 +752,9 @@ func setProductListReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, qf *
 		example.CreatedOn,
 		example.UpdatedOn,
 		example.ArchivedOn,
+		example.SaleStartsOn,
+		example.SaleEndsOn,
+		example.EffectivePrice,
 	).AddRow(
 		example.ID,
 		example.ProductRootID,
@@ -560,6 +786,9 @@ func setProductListReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, qf *
 		example.CreatedOn,
 		example.UpdatedOn,
 		example.ArchivedOn,
+		example.SaleStartsOn,
+		example.SaleEndsOn,
+		example.EffectivePrice,
 	).AddRow(
 		example.ID,
 		example.ProductRootID,
@@ -591,6 +820,9 @@ func setProductListReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, qf *
 		example.CreatedOn,
 		example.UpdatedOn,
 		example.ArchivedOn,
+		example.SaleStartsOn,
+		example.SaleEndsOn,
+		example.EffectivePrice,
 	).RowError(1, rowErr)
 
 	query, _ := buildProductListRetrievalQuery(qf)
@@ -654,6 +886,209 @@ func TestGetProductList(t *testing.T) {
 	})
 }
 
+func TestBuildProductListRetrievalQueryForCurrency(t *testing.T) {
+	t.Parallel()
+
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+	query, args := buildProductListRetrievalQueryForCurrency(exampleQF, "EUR")
+
+	assert.Contains(t, query, "product_prices.currency = $1", "currency should be the first query argument")
+	assert.Contains(t, query, "products.price", "base price should be the fallback")
+	assert.Contains(t, query, "* sale_price / NULLIF(products.price, 0)", "a sale should apply to the currency price too")
+	assert.Equal(t, []interface{}{"EUR", "EUR", "EUR"}, args, "expected and actual query arguments should match")
+}
+
+func setProductListReadQueryExpectationForCurrency(t *testing.T, mock sqlmock.Sqlmock, qf *models.QueryFilter, currency string, example *models.Product, rowErr error, err error) {
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"product_root_id",
+		"primary_image_id",
+		"name",
+		"subtitle",
+		"description",
+		"option_summary",
+		"sku",
+		"upc",
+		"manufacturer",
+		"brand",
+		"quantity",
+		"taxable",
+		"price",
+		"on_sale",
+		"sale_price",
+		"cost",
+		"product_weight",
+		"product_height",
+		"product_width",
+		"product_length",
+		"package_weight",
+		"package_height",
+		"package_width",
+		"package_length",
+		"quantity_per_package",
+		"available_on",
+		"created_on",
+		"updated_on",
+		"archived_on",
+		"sale_starts_on",
+		"sale_ends_on",
+		"effective_price",
+	}).AddRow(
+		example.ID,
+		example.ProductRootID,
+		example.PrimaryImageID,
+		example.Name,
+		example.Subtitle,
+		example.Description,
+		example.OptionSummary,
+		example.SKU,
+		example.UPC,
+		example.Manufacturer,
+		example.Brand,
+		example.Quantity,
+		example.Taxable,
+		example.Price,
+		example.OnSale,
+		example.SalePrice,
+		example.Cost,
+		example.ProductWeight,
+		example.ProductHeight,
+		example.ProductWidth,
+		example.ProductLength,
+		example.PackageWeight,
+		example.PackageHeight,
+		example.PackageWidth,
+		example.PackageLength,
+		example.QuantityPerPackage,
+		example.AvailableOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+		example.SaleStartsOn,
+		example.SaleEndsOn,
+		example.EffectivePrice,
+	).AddRow(
+		example.ID,
+		example.ProductRootID,
+		example.PrimaryImageID,
+		example.Name,
+		example.Subtitle,
+		example.Description,
+		example.OptionSummary,
+		example.SKU,
+		example.UPC,
+		example.Manufacturer,
+		example.Brand,
+		example.Quantity,
+		example.Taxable,
+		example.Price,
+		example.OnSale,
+		example.SalePrice,
+		example.Cost,
+		example.ProductWeight,
+		example.ProductHeight,
+		example.ProductWidth,
+		example.ProductLength,
+		example.PackageWeight,
+		example.PackageHeight,
+		example.PackageWidth,
+		example.PackageLength,
+		example.QuantityPerPackage,
+		example.AvailableOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+		example.SaleStartsOn,
+		example.SaleEndsOn,
+		example.EffectivePrice,
+	).AddRow(
+		example.ID,
+		example.ProductRootID,
+		example.PrimaryImageID,
+		example.Name,
+		example.Subtitle,
+		example.Description,
+		example.OptionSummary,
+		example.SKU,
+		example.UPC,
+		example.Manufacturer,
+		example.Brand,
+		example.Quantity,
+		example.Taxable,
+		example.Price,
+		example.OnSale,
+		example.SalePrice,
+		example.Cost,
+		example.ProductWeight,
+		example.ProductHeight,
+		example.ProductWidth,
+		example.ProductLength,
+		example.PackageWeight,
+		example.PackageHeight,
+		example.PackageWidth,
+		example.PackageLength,
+		example.QuantityPerPackage,
+		example.AvailableOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+		example.SaleStartsOn,
+		example.SaleEndsOn,
+		example.EffectivePrice,
+	).RowError(1, rowErr)
+
+	query, _ := buildProductListRetrievalQueryForCurrency(qf, currency)
+
+	mock.ExpectQuery(formatQueryForSQLMock(query)).
+		WithArgs(currency, currency, currency).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestGetProductListForCurrency(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	example := &models.Product{ID: uint64(1)}
+	exampleCurrency := "EUR"
+	client := NewPostgres()
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setProductListReadQueryExpectationForCurrency(t, mock, exampleQF, exampleCurrency, example, nil, nil)
+		actual, err := client.GetProductListForCurrency(mockDB, exampleQF, exampleCurrency)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, actual, "list retrieval method should not return an empty slice")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error executing query", func(t *testing.T) {
+		setProductListReadQueryExpectationForCurrency(t, mock, exampleQF, exampleCurrency, example, nil, errors.New("pineapple on pizza"))
+		actual, err := client.GetProductListForCurrency(mockDB, exampleQF, exampleCurrency)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with with row errors", func(t *testing.T) {
+		setProductListReadQueryExpectationForCurrency(t, mock, exampleQF, exampleCurrency, example, errors.New("pineapple on pizza"), nil)
+		actual, err := client.GetProductListForCurrency(mockDB, exampleQF, exampleCurrency)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
 func TestBuildProductCountRetrievalQuery(t *testing.T) {
 	t.Parallel()
 
@@ -736,6 +1171,8 @@ func setProductCreationQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toCr
 			toCreate.PackageLength,
 			toCreate.QuantityPerPackage,
 			toCreate.AvailableOn,
+			toCreate.SaleStartsOn,
+			toCreate.SaleEndsOn,
 		).
 		WillReturnRows(exampleRows).
 		WillReturnError(err)
@@ -796,6 +1233,8 @@ func setProductUpdateQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toUpda
 			toUpdate.PackageLength,
 			toUpdate.QuantityPerPackage,
 			toUpdate.AvailableOn,
+			toUpdate.SaleStartsOn,
+			toUpdate.SaleEndsOn,
 			toUpdate.ID,
 		).
 		WillReturnRows(exampleRows).
@@ -860,6 +1299,20 @@ func TestDeleteProductByID(t *testing.T) {
 	})
 }
 
+func TestProductWritesAppendOutboxEvents(t *testing.T) {
+	t.Parallel()
+
+	for query, eventType := range map[string]string{
+		productCreationQuery: "product_created",
+		productUpdateQuery:   "product_updated",
+		productDeletionQuery: "product_archived",
+	} {
+		assert.Contains(t, query, "INSERT INTO outbox_events", "product writes should append to the outbox in the same statement")
+		assert.Contains(t, query, "'"+eventType+"'", "product write should record a %s event", eventType)
+	}
+	assert.Contains(t, productUpdateQuery, "'inventory_changed'", "product updates should record quantity changes")
+}
+
 func setProductWithProductRootIDDeletionQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, err error) {
 	t.Helper()
 	query := formatQueryForSQLMock(productWithProductRootIDDeletionQuery)
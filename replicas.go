@@ -0,0 +1,328 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dairycart/dairycart/storage/database"
+)
+
+var (
+	_ database.Querier = (*ReplicaRouter)(nil)
+	_ database.Querier = (*ReplicaSession)(nil)
+	_ ContextQuerier   = (*ReplicaRouter)(nil)
+	_ ContextQuerier   = (*ReplicaSession)(nil)
+)
+
+// ReplicaRouterConfig controls how long reads stick to the primary after a
+// write and how replicas that stop answering are handled.
+type ReplicaRouterConfig struct {
+	// ReadYourWritesWindow is how long after a write through a ReplicaSession
+	// that session's reads are sent to the primary, so the caller sees its
+	// own changes despite replication lag.
+	ReadYourWritesWindow time.Duration
+	// HealthCheckInterval is how old a replica's last successful contact may
+	// be before it is pinged again ahead of a QueryRow, whose errors surface
+	// too late to fall back.
+	HealthCheckInterval time.Duration
+	// RetryDownReplicaAfter is how long a replica that failed is skipped.
+	RetryDownReplicaAfter time.Duration
+}
+
+var DefaultReplicaRouterConfig = ReplicaRouterConfig{
+	ReadYourWritesWindow:  5 * time.Second,
+	HealthCheckInterval:   10 * time.Second,
+	RetryDownReplicaAfter: 30 * time.Second,
+}
+
+type replica struct {
+	db *sql.DB
+
+	mu        sync.Mutex
+	checkedOn time.Time
+	downUntil time.Time
+}
+
+// ReplicaRouter is a Querier that sends read-only statements to a pool of
+// replicas and everything else to the primary. Pass it wherever a Querier is
+// expected; list, count and lookup queries then run on the replicas while
+// creates, updates and archives keep going to the primary. Transactions are
+// started on the primary with Begin, and the *sql.Tx is used as the Querier
+// for everything inside them.
+//
+// The router itself does not track writes, so reads made right after a write
+// may not see it yet. Callers that need to read their own writes, such as a
+// single request, use a ReplicaSession from Session instead.
+type ReplicaRouter struct {
+	primary  *sql.DB
+	replicas []*replica
+	cfg      ReplicaRouterConfig
+	logger   Logger
+	now      func() time.Time
+
+	next uint32
+}
+
+// ReplicaSession is a Querier that routes like its ReplicaRouter, except that
+// after it writes, its own reads go to the primary for the read-your-writes
+// window. Writes made by other sessions or by the router directly do not
+// affect it. Sessions are cheap; take one per request or unit of work.
+type ReplicaSession struct {
+	router    *ReplicaRouter
+	lastWrite int64
+}
+
+// NewReplicaRouter returns a router over primary and replicas. Zero fields of
+// cfg keep their default value. With no replicas every statement goes to the
+// primary.
+func (pg *postgres) NewReplicaRouter(primary *sql.DB, replicas []*sql.DB, cfg ReplicaRouterConfig) *ReplicaRouter {
+	if cfg.ReadYourWritesWindow == 0 {
+		cfg.ReadYourWritesWindow = DefaultReplicaRouterConfig.ReadYourWritesWindow
+	}
+	if cfg.HealthCheckInterval == 0 {
+		cfg.HealthCheckInterval = DefaultReplicaRouterConfig.HealthCheckInterval
+	}
+	if cfg.RetryDownReplicaAfter == 0 {
+		cfg.RetryDownReplicaAfter = DefaultReplicaRouterConfig.RetryDownReplicaAfter
+	}
+
+	r := &ReplicaRouter{
+		primary: primary,
+		cfg:     cfg,
+		logger:  pg.logger,
+		now:     time.Now,
+	}
+	for _, db := range replicas {
+		r.replicas = append(r.replicas, &replica{db: db})
+	}
+	return r
+}
+
+// writeStatementPattern matches anything that modifies data or takes locks,
+// including writes hidden in a WITH clause and SELECT ... FOR UPDATE.
+var writeStatementPattern = regexp.MustCompile(`(?i)\b(insert|update|delete|merge|truncate|nextval|setval|pg_advisory_\w+|pg_try_advisory_\w+)\b|\bfor\s+(no\s+key\s+)?(update|share|key\s+share)\b`)
+
+// isReadOnlyStatement reports whether query can safely run on a replica.
+func isReadOnlyStatement(query string) bool {
+	q := strings.ToLower(strings.TrimSpace(query))
+	if !strings.HasPrefix(q, "select") && !strings.HasPrefix(q, "with") {
+		return false
+	}
+	return !writeStatementPattern.MatchString(q)
+}
+
+// isConnectionError reports whether err means the server could not be
+// reached, as opposed to the statement itself failing.
+func isConnectionError(err error) bool {
+	if err == driver.ErrBadConn {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// Primary returns the primary database, for reads that must see the latest
+// data regardless of where they would be routed.
+func (r *ReplicaRouter) Primary() *sql.DB {
+	return r.primary
+}
+
+// Session returns a new ReplicaSession over the router with its own
+// read-your-writes window.
+func (r *ReplicaRouter) Session() *ReplicaSession {
+	return &ReplicaSession{router: r}
+}
+
+// Begin starts a transaction on the primary.
+func (r *ReplicaRouter) Begin() (*sql.Tx, error) {
+	return r.primary.Begin()
+}
+
+func (r *ReplicaRouter) markDown(rep *replica, err error) {
+	rep.mu.Lock()
+	rep.downUntil = r.now().Add(r.cfg.RetryDownReplicaAfter)
+	rep.mu.Unlock()
+	r.logger.Printf("replica unavailable, sending reads elsewhere for %s: %v", r.cfg.RetryDownReplicaAfter, err)
+}
+
+// healthyReplicas returns the replicas that are not marked down, starting
+// from the next one in round robin order.
+func (r *ReplicaRouter) healthyReplicas() []*replica {
+	if len(r.replicas) == 0 {
+		return nil
+	}
+	now := r.now()
+	start := int(atomic.AddUint32(&r.next, 1)) % len(r.replicas)
+
+	var healthy []*replica
+	for i := range r.replicas {
+		rep := r.replicas[(start+i)%len(r.replicas)]
+		rep.mu.Lock()
+		down := now.Before(rep.downUntil)
+		rep.mu.Unlock()
+		if !down {
+			healthy = append(healthy, rep)
+		}
+	}
+	return healthy
+}
+
+// checkReplica pings rep unless it was reached recently.
+func (r *ReplicaRouter) checkReplica(ctx context.Context, rep *replica) bool {
+	rep.mu.Lock()
+	fresh := r.now().Sub(rep.checkedOn) < r.cfg.HealthCheckInterval
+	rep.mu.Unlock()
+	if fresh {
+		return true
+	}
+
+	if err := rep.db.PingContext(ctx); err != nil {
+		r.markDown(rep, err)
+		return false
+	}
+	r.touch(rep)
+	return true
+}
+
+func (r *ReplicaRouter) touch(rep *replica) {
+	rep.mu.Lock()
+	rep.checkedOn = r.now()
+	rep.mu.Unlock()
+}
+
+func (r *ReplicaRouter) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return r.ExecContext(context.Background(), query, args...)
+}
+
+func (r *ReplicaRouter) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return r.QueryContext(context.Background(), query, args...)
+}
+
+func (r *ReplicaRouter) QueryRow(query string, args ...interface{}) *sql.Row {
+	return r.QueryRowContext(context.Background(), query, args...)
+}
+
+func (r *ReplicaRouter) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.primary.ExecContext(ctx, query, args...)
+}
+
+func (r *ReplicaRouter) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return r.query(ctx, isReadOnlyStatement(query), query, args...)
+}
+
+func (r *ReplicaRouter) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return r.queryRow(ctx, isReadOnlyStatement(query), query, args...)
+}
+
+// statementConn returns a connection of its own for query, from the database
+// Query would send it to.
+func (r *ReplicaRouter) statementConn(ctx context.Context, query string) (*sql.Conn, error) {
+	return r.conn(ctx, isReadOnlyStatement(query))
+}
+
+// conn checks replicas the way QueryRow does, since errors on the connection
+// only surface once the statement runs on it.
+func (r *ReplicaRouter) conn(ctx context.Context, fromReplica bool) (*sql.Conn, error) {
+	if fromReplica {
+		for _, rep := range r.healthyReplicas() {
+			if r.checkReplica(ctx, rep) {
+				return rep.db.Conn(ctx)
+			}
+		}
+	}
+	return r.primary.Conn(ctx)
+}
+
+func (r *ReplicaRouter) query(ctx context.Context, fromReplica bool, query string, args ...interface{}) (*sql.Rows, error) {
+	if fromReplica {
+		for _, rep := range r.healthyReplicas() {
+			rows, err := rep.db.QueryContext(ctx, query, args...)
+			if err == nil || !isConnectionError(err) {
+				if err == nil {
+					r.touch(rep)
+				}
+				return rows, err
+			}
+			r.markDown(rep, err)
+		}
+	}
+	return r.primary.QueryContext(ctx, query, args...)
+}
+
+func (r *ReplicaRouter) queryRow(ctx context.Context, fromReplica bool, query string, args ...interface{}) *sql.Row {
+	if fromReplica {
+		for _, rep := range r.healthyReplicas() {
+			if r.checkReplica(ctx, rep) {
+				return rep.db.QueryRowContext(ctx, query, args...)
+			}
+		}
+	}
+	return r.primary.QueryRowContext(ctx, query, args...)
+}
+
+// Primary returns the primary database of the session's router.
+func (s *ReplicaSession) Primary() *sql.DB {
+	return s.router.primary
+}
+
+// MarkWritten sends the session's reads to the primary for the
+// read-your-writes window. Exec and writes made through the session call it
+// themselves; call it after committing a transaction.
+func (s *ReplicaSession) MarkWritten() {
+	atomic.StoreInt64(&s.lastWrite, s.router.now().UnixNano())
+}
+
+func (s *ReplicaSession) recentlyWritten() bool {
+	last := atomic.LoadInt64(&s.lastWrite)
+	return last != 0 && s.router.now().Sub(time.Unix(0, last)) < s.router.cfg.ReadYourWritesWindow
+}
+
+func (s *ReplicaSession) readsFromReplica(query string) bool {
+	if isReadOnlyStatement(query) {
+		return !s.recentlyWritten()
+	}
+	s.MarkWritten()
+	return false
+}
+
+// Begin starts a transaction on the primary.
+func (s *ReplicaSession) Begin() (*sql.Tx, error) {
+	return s.router.Begin()
+}
+
+func (s *ReplicaSession) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return s.ExecContext(context.Background(), query, args...)
+}
+
+func (s *ReplicaSession) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return s.QueryContext(context.Background(), query, args...)
+}
+
+func (s *ReplicaSession) QueryRow(query string, args ...interface{}) *sql.Row {
+	return s.QueryRowContext(context.Background(), query, args...)
+}
+
+func (s *ReplicaSession) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	s.MarkWritten()
+	return s.router.ExecContext(ctx, query, args...)
+}
+
+func (s *ReplicaSession) statementConn(ctx context.Context, query string) (*sql.Conn, error) {
+	return s.router.conn(ctx, s.readsFromReplica(query))
+}
+
+func (s *ReplicaSession) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return s.router.query(ctx, s.readsFromReplica(query), query, args...)
+}
+
+func (s *ReplicaSession) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return s.router.queryRow(ctx, s.readsFromReplica(query), query, args...)
+}
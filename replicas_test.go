@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestIsReadOnlyStatement(t *testing.T) {
+	t.Parallel()
+
+	examples := map[string]bool{
+		`SELECT id, name FROM products WHERE archived_on IS NULL`:                    true,
+		`  select count(id) from product_images`:                                     true,
+		`SELECT id, updated_on FROM products`:                                        true,
+		`WITH recent AS (SELECT id FROM products) SELECT id FROM recent`:             true,
+		`INSERT INTO products (name) VALUES ($1) RETURNING id`:                       false,
+		`UPDATE products SET name = $1 WHERE id = $2 RETURNING updated_on`:           false,
+		`WITH gone AS (DELETE FROM products RETURNING id) SELECT count(*) FROM gone`: false,
+		`SELECT id FROM outbox_events FOR UPDATE SKIP LOCKED`:                        false,
+		`SELECT id FROM products FOR NO KEY UPDATE`:                                  false,
+		`SELECT pg_try_advisory_lock($1)`:                                            false,
+		`SELECT nextval('products_id_seq')`:                                          false,
+	}
+	for query, expected := range examples {
+		assert.Equal(t, expected, isReadOnlyStatement(query), query)
+	}
+}
+
+type replicaRouterTest struct {
+	router   *ReplicaRouter
+	primary  sqlmock.Sqlmock
+	replicas []sqlmock.Sqlmock
+	clock    time.Time
+}
+
+func setupReplicaRouterTest(t *testing.T, replicaCount int) *replicaRouterTest {
+	t.Helper()
+	primaryDB, primaryMock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	rt := &replicaRouterTest{primary: primaryMock, clock: buildTestTime(t)}
+	var replicaDBs []*sql.DB
+	for i := 0; i < replicaCount; i++ {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		replicaDBs = append(replicaDBs, db)
+		rt.replicas = append(rt.replicas, mock)
+	}
+
+	client := NewPostgres()
+	client.SetLogger(&recordingLogger{})
+	rt.router = client.NewReplicaRouter(primaryDB, replicaDBs, ReplicaRouterConfig{})
+	rt.router.now = func() time.Time { return rt.clock }
+	return rt
+}
+
+func (rt *replicaRouterTest) expectationsWereMet(t *testing.T) {
+	assert.Nil(t, rt.primary.ExpectationsWereMet(), "not all primary expectations were met")
+	for _, mock := range rt.replicas {
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all replica expectations were met")
+	}
+}
+
+func TestReplicaRouter(t *testing.T) {
+	t.Parallel()
+	readQuery := `SELECT id FROM products WHERE archived_on IS NULL`
+	writeQuery := `UPDATE products SET archived_on = NOW() WHERE id = $1 RETURNING archived_on`
+
+	t.Run("sends reads to replicas in turn", func(*testing.T) {
+		rt := setupReplicaRouterTest(t, 2)
+		rt.replicas[0].ExpectQuery(formatQueryForSQLMock(readQuery)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		rt.replicas[1].ExpectQuery(formatQueryForSQLMock(readQuery)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		for i := 0; i < 2; i++ {
+			rows, err := rt.router.Query(readQuery)
+			require.NoError(t, err)
+			rows.Close()
+		}
+		rt.expectationsWereMet(t)
+	})
+
+	t.Run("sends writes to the primary without pinning later reads", func(*testing.T) {
+		rt := setupReplicaRouterTest(t, 1)
+		rt.primary.ExpectQuery(formatQueryForSQLMock(writeQuery)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"archived_on"}).AddRow(rt.clock))
+		rt.primary.ExpectExec(formatQueryForSQLMock(`DELETE FROM products`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		rt.replicas[0].ExpectQuery(formatQueryForSQLMock(readQuery)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		var archivedOn time.Time
+		require.NoError(t, rt.router.QueryRow(writeQuery, 1).Scan(&archivedOn))
+		_, err := rt.router.Exec(`DELETE FROM products`)
+		require.NoError(t, err)
+
+		var id uint64
+		require.NoError(t, rt.router.QueryRow(readQuery).Scan(&id))
+		rt.expectationsWereMet(t)
+	})
+
+	t.Run("sends a session's reads to the primary after it writes", func(*testing.T) {
+		rt := setupReplicaRouterTest(t, 1)
+		session := rt.router.Session()
+		rt.primary.ExpectQuery(formatQueryForSQLMock(writeQuery)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"archived_on"}).AddRow(rt.clock))
+		rt.primary.ExpectQuery(formatQueryForSQLMock(readQuery)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		rt.replicas[0].ExpectQuery(formatQueryForSQLMock(readQuery)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		var archivedOn time.Time
+		require.NoError(t, session.QueryRow(writeQuery, 1).Scan(&archivedOn))
+
+		var id uint64
+		require.NoError(t, session.QueryRow(readQuery).Scan(&id))
+
+		rt.clock = rt.clock.Add(DefaultReplicaRouterConfig.ReadYourWritesWindow)
+		require.NoError(t, session.QueryRow(readQuery).Scan(&id))
+		rt.expectationsWereMet(t)
+	})
+
+	t.Run("with session exec", func(*testing.T) {
+		rt := setupReplicaRouterTest(t, 1)
+		session := rt.router.Session()
+		rt.primary.ExpectExec(formatQueryForSQLMock(`DELETE FROM products`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		rt.primary.ExpectQuery(formatQueryForSQLMock(readQuery)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		_, err := session.Exec(`DELETE FROM products`)
+		require.NoError(t, err)
+		rows, err := session.Query(readQuery)
+		require.NoError(t, err)
+		rows.Close()
+		rt.expectationsWereMet(t)
+	})
+
+	t.Run("keeps each session's window to itself", func(*testing.T) {
+		rt := setupReplicaRouterTest(t, 1)
+		writer, reader := rt.router.Session(), rt.router.Session()
+		rt.primary.ExpectExec(formatQueryForSQLMock(`DELETE FROM products`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		rt.replicas[0].ExpectQuery(formatQueryForSQLMock(readQuery)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		rt.primary.ExpectQuery(formatQueryForSQLMock(readQuery)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		_, err := writer.Exec(`DELETE FROM products`)
+		require.NoError(t, err)
+
+		var id uint64
+		require.NoError(t, reader.QueryRow(readQuery).Scan(&id), "another session's write should not pin this one to the primary")
+		require.NoError(t, writer.QueryRow(readQuery).Scan(&id))
+		rt.expectationsWereMet(t)
+	})
+
+	t.Run("falls back when a replica is down", func(*testing.T) {
+		rt := setupReplicaRouterTest(t, 1)
+		refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+		rt.replicas[0].ExpectQuery(formatQueryForSQLMock(readQuery)).WillReturnError(refused)
+		rt.primary.ExpectQuery(formatQueryForSQLMock(readQuery)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		rt.primary.ExpectQuery(formatQueryForSQLMock(readQuery)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		rt.replicas[0].ExpectQuery(formatQueryForSQLMock(readQuery)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		for i := 0; i < 2; i++ {
+			rows, err := rt.router.Query(readQuery)
+			require.NoError(t, err)
+			rows.Close()
+		}
+
+		rt.clock = rt.clock.Add(DefaultReplicaRouterConfig.RetryDownReplicaAfter)
+		rows, err := rt.router.Query(readQuery)
+		require.NoError(t, err)
+		rows.Close()
+		rt.expectationsWereMet(t)
+	})
+
+	t.Run("with query error", func(*testing.T) {
+		rt := setupReplicaRouterTest(t, 1)
+		rt.replicas[0].ExpectQuery(formatQueryForSQLMock(readQuery)).WillReturnError(errors.New("pineapple on pizza"))
+
+		_, err := rt.router.Query(readQuery)
+		assert.NotNil(t, err)
+		rt.expectationsWereMet(t)
+	})
+
+	t.Run("without replicas", func(*testing.T) {
+		rt := setupReplicaRouterTest(t, 0)
+		rt.primary.ExpectQuery(formatQueryForSQLMock(readQuery)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		var id uint64
+		require.NoError(t, rt.router.QueryRow(readQuery).Scan(&id))
+		rt.expectationsWereMet(t)
+	})
+}
@@ -0,0 +1,254 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/dairycart/dairycart/storage/database"
+
+	"github.com/lib/pq"
+)
+
+// RetryConfig controls statement timeouts and how transient failures are
+// retried.
+type RetryConfig struct {
+	// StatementTimeout cancels a statement that runs longer than this. See
+	// RetryingQuerier for which statements that return rows it covers.
+	StatementTimeout time.Duration
+	// MaxAttempts caps how many times a statement is tried, including the
+	// first attempt.
+	MaxAttempts uint
+	// BaseDelay is the wait before the first retry. It doubles for every
+	// further retry, up to MaxDelay, and each wait is jittered.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+var DefaultRetryConfig = RetryConfig{
+	StatementTimeout: 30 * time.Second,
+	MaxAttempts:      3,
+	BaseDelay:        50 * time.Millisecond,
+	MaxDelay:         time.Second,
+}
+
+// ContextQuerier is what RetryingQuerier runs statements on. *sql.DB,
+// *sql.Conn and *sql.Tx all satisfy it, as do ReplicaRouter, ReplicaSession
+// and PreparedStatementCache, so retries can be layered over either.
+type ContextQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// statementConnSource is implemented by queriers that can give a statement a
+// connection of its own to run on. It returns a nil *sql.Conn for statements
+// it has to run itself.
+type statementConnSource interface {
+	statementConn(ctx context.Context, query string) (*sql.Conn, error)
+}
+
+var _ database.Querier = (*RetryingQuerier)(nil)
+
+// RetryingQuerier is a Querier that gives every statement a timeout and
+// retries read-only statements that fail with serialization failures,
+// deadlocks, lock timeouts or lost connections. Writes are only retried when
+// marked idempotent with RetryingWrites. Statements run on a *sql.Tx are never
+// retried, since postgres aborts the whole transaction on the first error.
+//
+// QueryRow can only retry errors from running the query. Errors that surface
+// later, in Scan, such as a connection lost while the row is read, reach the
+// caller as is, since the row has been handed over by then.
+//
+// The statement timeout of Query and QueryRow lasts until the rows are closed
+// or the row is scanned, which is only known when the statement runs on a
+// connection of its own. That is the case over a *sql.DB, a ReplicaRouter or
+// ReplicaSession, and for the queries a PreparedStatementCache does not
+// prepare. Elsewhere, such as inside a *sql.Tx, those statements run without
+// a client-side timeout and rely on the server's statement_timeout, which
+// ConnectionConfig.StatementTimeout sets.
+type RetryingQuerier struct {
+	db          ContextQuerier
+	cfg         RetryConfig
+	retryWrites bool
+	logger      Logger
+	sleep       func(time.Duration)
+	jitter      func(time.Duration) time.Duration
+	withTimeout func(time.Duration) (context.Context, context.CancelFunc)
+}
+
+// NewRetryingQuerier wraps db. Zero fields of cfg keep their default value.
+func (pg *postgres) NewRetryingQuerier(db ContextQuerier, cfg RetryConfig) *RetryingQuerier {
+	if cfg.StatementTimeout == 0 {
+		cfg.StatementTimeout = DefaultRetryConfig.StatementTimeout
+	}
+	if cfg.MaxAttempts == 0 {
+		cfg.MaxAttempts = DefaultRetryConfig.MaxAttempts
+	}
+	if cfg.BaseDelay == 0 {
+		cfg.BaseDelay = DefaultRetryConfig.BaseDelay
+	}
+	if cfg.MaxDelay == 0 {
+		cfg.MaxDelay = DefaultRetryConfig.MaxDelay
+	}
+	if _, ok := db.(*sql.Tx); ok {
+		cfg.MaxAttempts = 1
+	}
+
+	return &RetryingQuerier{
+		db:     db,
+		cfg:    cfg,
+		logger: pg.logger,
+		sleep:  pg.sleep,
+		jitter: equalJitter,
+		withTimeout: func(d time.Duration) (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.Background(), d)
+		},
+	}
+}
+
+// equalJitter picks a wait between half of d and d, so instances that failed
+// together do not all retry at the same moment.
+func equalJitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)))
+}
+
+// WithTimeout returns a copy of q whose statements time out after d instead.
+func (q *RetryingQuerier) WithTimeout(d time.Duration) *RetryingQuerier {
+	c := *q
+	c.cfg.StatementTimeout = d
+	return &c
+}
+
+// RetryingWrites returns a copy of q that retries writes too. Only use it for
+// writes that are safe to apply twice, since a lost connection can hide a
+// write that did go through.
+func (q *RetryingQuerier) RetryingWrites() *RetryingQuerier {
+	c := *q
+	c.retryWrites = true
+	return &c
+}
+
+// WithoutRetries returns a copy of q that tries every statement once, keeping
+// the timeout.
+func (q *RetryingQuerier) WithoutRetries() *RetryingQuerier {
+	c := *q
+	c.cfg.MaxAttempts = 1
+	return &c
+}
+
+// isRetryableError reports whether err is a transient failure worth trying
+// again, judged by its SQLSTATE where there is one.
+func isRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if isConnectionError(err) {
+		return true
+	}
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	switch pqErr.Code.Name() {
+	case "serialization_failure", "deadlock_detected", "lock_not_available",
+		"admin_shutdown", "crash_shutdown", "cannot_connect_now":
+		return true
+	}
+	return pqErr.Code.Class().Name() == "connection_exception"
+}
+
+// statementConn returns a connection of its own to run query on, or nil if
+// the wrapped querier cannot give it one.
+func (q *RetryingQuerier) statementConn(ctx context.Context, query string) (*sql.Conn, error) {
+	switch db := q.db.(type) {
+	case *sql.DB:
+		return db.Conn(ctx)
+	case statementConnSource:
+		return db.statementConn(ctx, query)
+	}
+	return nil, nil
+}
+
+// queryWithTimeout runs a query that returns rows, and returns the error it
+// failed with, if any.
+//
+// The rows outlive the call, so the statement runs on a connection of its
+// own, and the timeout is released once closing that connection goes
+// through, which waits for the rows to be closed or the row to be scanned.
+// Without a connection of its own nothing reports when that happens, so the
+// statement runs without a timeout rather than leave one behind.
+func (q *RetryingQuerier) queryWithTimeout(query string, run func(ctx context.Context, db ContextQuerier) error) error {
+	ctx, cancel := q.withTimeout(q.cfg.StatementTimeout)
+	conn, err := q.statementConn(ctx, query)
+	if err != nil || conn == nil {
+		cancel()
+		if err != nil {
+			return err
+		}
+		return run(context.Background(), q.db)
+	}
+
+	err = run(ctx, conn)
+	go func() {
+		conn.Close()
+		cancel()
+	}()
+	return err
+}
+
+// attempt runs try until it succeeds, fails with an error that is not worth
+// retrying, or runs out of attempts.
+func (q *RetryingQuerier) attempt(query string, try func() error) {
+	attempts := uint(1)
+	if q.retryWrites || isReadOnlyStatement(query) {
+		attempts = q.cfg.MaxAttempts
+	}
+
+	for n := uint(1); ; n++ {
+		err := try()
+		if n >= attempts || !isRetryableError(err) {
+			return
+		}
+		delay := q.jitter(backoffDelay(n, q.cfg.BaseDelay, q.cfg.MaxDelay))
+		q.logger.Printf("transient database error (attempt %d of %d), retrying in %s: %v", n, attempts, delay, err)
+		q.sleep(delay)
+	}
+}
+
+func (q *RetryingQuerier) Exec(query string, args ...interface{}) (res sql.Result, err error) {
+	q.attempt(query, func() error {
+		ctx, cancel := q.withTimeout(q.cfg.StatementTimeout)
+		defer cancel()
+		res, err = q.db.ExecContext(ctx, query, args...)
+		return err
+	})
+	return res, err
+}
+
+func (q *RetryingQuerier) Query(query string, args ...interface{}) (rows *sql.Rows, err error) {
+	q.attempt(query, func() error {
+		err = q.queryWithTimeout(query, func(ctx context.Context, db ContextQuerier) error {
+			rows, err = db.QueryContext(ctx, query, args...)
+			return err
+		})
+		return err
+	})
+	return rows, err
+}
+
+func (q *RetryingQuerier) QueryRow(query string, args ...interface{}) (row *sql.Row) {
+	q.attempt(query, func() error {
+		return q.queryWithTimeout(query, func(ctx context.Context, db ContextQuerier) error {
+			row = db.QueryRowContext(ctx, query, args...)
+			return row.Err()
+		})
+	})
+	return row
+}
@@ -0,0 +1,293 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	t.Parallel()
+
+	examples := map[error]bool{
+		&pq.Error{Code: "40001"}: true,
+		&pq.Error{Code: "40P01"}: true,
+		&pq.Error{Code: "55P03"}: true,
+		&pq.Error{Code: "57P01"}: true,
+		&pq.Error{Code: "08006"}: true,
+		&net.OpError{Op: "read", Err: errors.New("connection reset by peer")}: true,
+		&pq.Error{Code: "23505"}:         false,
+		&pq.Error{Code: "57014"}:         false,
+		errors.New("pineapple on pizza"): false,
+		nil:                              false,
+	}
+	for err, expected := range examples {
+		assert.Equal(t, expected, isRetryableError(err), "unexpected result for %v", err)
+	}
+}
+
+func buildTestRetryingQuerier(t *testing.T, db ContextQuerier) (*RetryingQuerier, *[]time.Duration) {
+	t.Helper()
+	client := NewPostgres()
+	client.SetLogger(&recordingLogger{})
+	q := client.NewRetryingQuerier(db, RetryConfig{BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond})
+
+	var slept []time.Duration
+	q.sleep = func(d time.Duration) { slept = append(slept, d) }
+	q.jitter = func(d time.Duration) time.Duration { return d }
+	return q, &slept
+}
+
+// countCancelledTimeouts makes q count how many of its statement timeouts
+// have been released.
+func countCancelledTimeouts(q *RetryingQuerier) *int32 {
+	var cancelled int32
+	q.withTimeout = func(d time.Duration) (context.Context, context.CancelFunc) {
+		ctx, cancel := context.WithTimeout(context.Background(), d)
+		return ctx, func() {
+			atomic.AddInt32(&cancelled, 1)
+			cancel()
+		}
+	}
+	return &cancelled
+}
+
+func TestRetryingQuerier(t *testing.T) {
+	t.Parallel()
+	readQuery := `SELECT id FROM products WHERE id = $1`
+	writeQuery := `UPDATE products SET archived_on = NOW() WHERE id = $1`
+	serializationFailure := &pq.Error{Code: "40001"}
+
+	t.Run("retries reads", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+		q, slept := buildTestRetryingQuerier(t, mockDB)
+
+		mock.ExpectQuery(formatQueryForSQLMock(readQuery)).WithArgs(1).WillReturnError(serializationFailure)
+		mock.ExpectQuery(formatQueryForSQLMock(readQuery)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		var id uint64
+		assert.NoError(t, q.QueryRow(readQuery, 1).Scan(&id))
+		assert.Equal(t, []time.Duration{time.Millisecond}, *slept)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("gives up after max attempts", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+		q, slept := buildTestRetryingQuerier(t, mockDB)
+
+		for i := uint(0); i < DefaultRetryConfig.MaxAttempts; i++ {
+			mock.ExpectQuery(formatQueryForSQLMock(readQuery)).WithArgs(1).WillReturnError(serializationFailure)
+		}
+
+		_, err = q.Query(readQuery, 1)
+		assert.Equal(t, serializationFailure, err)
+		assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond}, *slept)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("does not retry other errors", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+		q, _ := buildTestRetryingQuerier(t, mockDB)
+
+		mock.ExpectQuery(formatQueryForSQLMock(readQuery)).WithArgs(1).WillReturnError(errors.New("pineapple on pizza"))
+
+		_, err = q.Query(readQuery, 1)
+		assert.NotNil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("does not retry unmarked writes", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+		q, _ := buildTestRetryingQuerier(t, mockDB)
+
+		mock.ExpectExec(formatQueryForSQLMock(writeQuery)).WithArgs(1).WillReturnError(serializationFailure)
+
+		_, err = q.Exec(writeQuery, 1)
+		assert.Equal(t, serializationFailure, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("retries marked writes", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+		q, _ := buildTestRetryingQuerier(t, mockDB)
+
+		mock.ExpectExec(formatQueryForSQLMock(writeQuery)).WithArgs(1).WillReturnError(&pq.Error{Code: "40P01"})
+		mock.ExpectExec(formatQueryForSQLMock(writeQuery)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err = q.RetryingWrites().Exec(writeQuery, 1)
+		assert.NoError(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("without retries", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+		q, _ := buildTestRetryingQuerier(t, mockDB)
+
+		mock.ExpectQuery(formatQueryForSQLMock(readQuery)).WithArgs(1).WillReturnError(serializationFailure)
+
+		_, err = q.WithoutRetries().Query(readQuery, 1)
+		assert.Equal(t, serializationFailure, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("inside a transaction", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(formatQueryForSQLMock(readQuery)).WithArgs(1).WillReturnError(serializationFailure)
+		tx, err := mockDB.Begin()
+		require.NoError(t, err)
+		q, _ := buildTestRetryingQuerier(t, tx)
+
+		_, err = q.Query(readQuery, 1)
+		assert.Equal(t, serializationFailure, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with statement timeout", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+		q, _ := buildTestRetryingQuerier(t, mockDB)
+
+		mock.ExpectExec(formatQueryForSQLMock(writeQuery)).WithArgs(1).WillDelayFor(time.Second).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		start := time.Now()
+		_, err = q.WithTimeout(10*time.Millisecond).Exec(writeQuery, 1)
+		assert.NotNil(t, err)
+		assert.True(t, time.Since(start) < time.Second, "statement should have been cancelled")
+	})
+
+	t.Run("with statement timeout on a query", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+		q, _ := buildTestRetryingQuerier(t, mockDB)
+
+		mock.ExpectQuery(formatQueryForSQLMock(readQuery)).WithArgs(1).WillDelayFor(time.Second).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		start := time.Now()
+		_, err = q.WithTimeout(10*time.Millisecond).WithoutRetries().Query(readQuery, 1)
+		assert.NotNil(t, err)
+		assert.True(t, time.Since(start) < time.Second, "statement should have been cancelled")
+	})
+
+	t.Run("releases the timeout once rows are closed", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+		q, _ := buildTestRetryingQuerier(t, mockDB)
+		cancelled := countCancelledTimeouts(q)
+		released := func() bool { return atomic.LoadInt32(cancelled) == 1 }
+
+		mock.ExpectQuery(formatQueryForSQLMock(readQuery)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		rows, err := q.Query(readQuery, 1)
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+		assert.False(t, released(), "the timeout should be held while the rows are open")
+		assert.Equal(t, 1, mockDB.Stats().InUse, "the connection should be held while the rows are open")
+
+		rows.Close()
+		assert.Eventually(t, released, time.Second, time.Millisecond)
+		assert.Equal(t, 0, mockDB.Stats().InUse)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("releases the timeout once the row is scanned", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+		q, _ := buildTestRetryingQuerier(t, mockDB)
+		cancelled := countCancelledTimeouts(q)
+
+		mock.ExpectQuery(formatQueryForSQLMock(readQuery)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		var id uint64
+		require.NoError(t, q.QueryRow(readQuery, 1).Scan(&id))
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(cancelled) == 1 }, time.Second, time.Millisecond)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("does not leave a timeout behind without a connection of its own", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(formatQueryForSQLMock(readQuery)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		tx, err := mockDB.Begin()
+		require.NoError(t, err)
+		q, _ := buildTestRetryingQuerier(t, tx)
+		cancelled := countCancelledTimeouts(q)
+
+		rows, err := q.Query(readQuery, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(cancelled), "the timeout should be released before the rows are handed over")
+		rows.Close()
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("over a replica router", func(*testing.T) {
+		rt := setupReplicaRouterTest(t, 1)
+		q, slept := buildTestRetryingQuerier(t, rt.router)
+		cancelled := countCancelledTimeouts(q)
+
+		rt.replicas[0].ExpectQuery(formatQueryForSQLMock(readQuery)).WithArgs(1).WillReturnError(serializationFailure)
+		rt.replicas[0].ExpectQuery(formatQueryForSQLMock(readQuery)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		var id uint64
+		assert.NoError(t, q.QueryRow(readQuery, 1).Scan(&id))
+		assert.Equal(t, []time.Duration{time.Millisecond}, *slept)
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(cancelled) == 2 }, time.Second, time.Millisecond, "both attempts should release their timeout")
+		rt.expectationsWereMet(t)
+	})
+
+	t.Run("over a prepared statement cache", func(*testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+		client := NewPostgres()
+		q, slept := buildTestRetryingQuerier(t, client.PrepareStatements(mockDB))
+
+		mock.ExpectPrepare(formatQueryForSQLMock(productSelectionQuery))
+		mock.ExpectQuery(formatQueryForSQLMock(productSelectionQuery)).WithArgs(1).WillReturnError(serializationFailure)
+		mock.ExpectQuery(formatQueryForSQLMock(productSelectionQuery)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		var id uint64
+		assert.NoError(t, q.QueryRow(productSelectionQuery, 1).Scan(&id))
+		assert.Equal(t, []time.Duration{time.Millisecond}, *slept)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func TestEqualJitter(t *testing.T) {
+	t.Parallel()
+
+	for i := 0; i < 100; i++ {
+		d := equalJitter(100 * time.Millisecond)
+		assert.True(t, d >= 50*time.Millisecond && d <= 100*time.Millisecond, "unexpected jitter %s", d)
+	}
+}
@@ -0,0 +1,204 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/dairycart/dairycart/storage/database"
+	"github.com/dairycart/dairymodels/v1"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const rolePermissionGrantQuery = `
+    INSERT INTO role_permissions
+        (
+            role_id, permission_id
+        )
+    VALUES
+        (
+            $1, $2
+        )
+    ON CONFLICT (role_id, permission_id) WHERE archived_on IS NULL
+    DO UPDATE SET permission_id = EXCLUDED.permission_id
+    RETURNING
+        created_on;
+`
+
+func (pg *postgres) GrantRolePermission(db database.Querier, roleID uint64, permissionID uint64) (grantedOn time.Time, err error) {
+	defer pg.observe("GrantRolePermission", time.Now(), &err, &grantedOn, roleID, permissionID)
+	err = db.QueryRow(rolePermissionGrantQuery, roleID, permissionID).Scan(&grantedOn)
+	return grantedOn, err
+}
+
+const rolePermissionRevocationQuery = `
+    UPDATE role_permissions
+    SET archived_on = NOW()
+    WHERE role_id = $1
+    AND permission_id = $2
+    AND archived_on IS NULL
+    RETURNING archived_on
+`
+
+func (pg *postgres) RevokeRolePermission(db database.Querier, roleID uint64, permissionID uint64) (t time.Time, err error) {
+	defer pg.observe("RevokeRolePermission", time.Now(), &err, &t, roleID, permissionID)
+	err = db.QueryRow(rolePermissionRevocationQuery, roleID, permissionID).Scan(&t)
+	return t, err
+}
+
+const rolePermissionExistenceQuery = `SELECT EXISTS(SELECT id FROM role_permissions WHERE id = $1 and archived_on IS NULL);`
+
+func (pg *postgres) RolePermissionExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("RolePermissionExists", time.Now(), &err, &result, id)
+	var exists string
+
+	err = db.QueryRow(rolePermissionExistenceQuery, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return exists == "true", err
+}
+
+const rolePermissionSelectionQuery = `
+    SELECT
+        id,
+        role_id,
+        permission_id,
+        created_on,
+        updated_on,
+        archived_on
+    FROM
+        role_permissions
+    WHERE
+        archived_on is null
+    AND
+        id = $1
+`
+
+func (pg *postgres) GetRolePermission(db database.Querier, id uint64) (result *models.RolePermission, err error) {
+	defer pg.observe("GetRolePermission", time.Now(), &err, &result, id)
+	r := &models.RolePermission{}
+
+	err = db.QueryRow(rolePermissionSelectionQuery, id).Scan(&r.ID, &r.RoleID, &r.PermissionID, &r.CreatedOn, &r.UpdatedOn, &r.ArchivedOn)
+
+	return r, err
+}
+
+func buildRolePermissionListRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	sqlBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	queryBuilder := sqlBuilder.
+		Select(
+			"id",
+			"role_id",
+			"permission_id",
+			"created_on",
+			"updated_on",
+			"archived_on",
+		).
+		From("role_permissions")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, true).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetRolePermissionList(db database.Querier, qf *models.QueryFilter) (result []models.RolePermission, err error) {
+	defer pg.observe("GetRolePermissionList", time.Now(), &err, &result, qf)
+	var list []models.RolePermission
+	query, args := buildRolePermissionListRetrievalQuery(qf)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var r models.RolePermission
+		err := rows.Scan(
+			&r.ID,
+			&r.RoleID,
+			&r.PermissionID,
+			&r.CreatedOn,
+			&r.UpdatedOn,
+			&r.ArchivedOn,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, r)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+func buildRolePermissionCountRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("count(id)").
+		From("role_permissions")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, false).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetRolePermissionCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetRolePermissionCount", time.Now(), &err, nil, qf)
+	var count uint64
+	query, args := buildRolePermissionCountRetrievalQuery(qf)
+	err = db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+const rolePermissionCreationQuery = `
+    INSERT INTO role_permissions
+        (
+            role_id, permission_id
+        )
+    VALUES
+        (
+            $1, $2
+        )
+    RETURNING
+        id, created_on;
+`
+
+func (pg *postgres) CreateRolePermission(db database.Querier, nu *models.RolePermission) (createdID uint64, createdOn time.Time, err error) {
+	defer pg.observe("CreateRolePermission", time.Now(), &err, &createdID, nu)
+	err = db.QueryRow(rolePermissionCreationQuery, &nu.RoleID, &nu.PermissionID).Scan(&createdID, &createdOn)
+	return createdID, createdOn, err
+}
+
+const rolePermissionUpdateQuery = `
+    UPDATE role_permissions
+    SET
+        role_id = $1,
+        permission_id = $2,
+        updated_on = NOW()
+    WHERE id = $3
+    RETURNING updated_on;
+`
+
+func (pg *postgres) UpdateRolePermission(db database.Querier, updated *models.RolePermission) (result time.Time, err error) {
+	defer pg.observe("UpdateRolePermission", time.Now(), &err, &result, updated)
+	var t time.Time
+	err = db.QueryRow(rolePermissionUpdateQuery, &updated.RoleID, &updated.PermissionID, &updated.ID).Scan(&t)
+	return t, err
+}
+
+const rolePermissionDeletionQuery = `
+    UPDATE role_permissions
+    SET archived_on = NOW()
+    WHERE id = $1
+    RETURNING archived_on
+`
+
+func (pg *postgres) DeleteRolePermission(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteRolePermission", time.Now(), &err, &t, id)
+	err = db.QueryRow(rolePermissionDeletionQuery, id).Scan(&t)
+	return t, err
+}
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/dairycart/dairycart/storage/database"
+	"github.com/dairycart/dairymodels/v1"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const roleExistenceQuery = `SELECT EXISTS(SELECT id FROM roles WHERE id = $1 and archived_on IS NULL);`
+
+func (pg *postgres) RoleExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("RoleExists", time.Now(), &err, &result, id)
+	var exists string
+
+	err = db.QueryRow(roleExistenceQuery, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return exists == "true", err
+}
+
+const roleSelectionQuery = `
+    SELECT
+        id,
+        name,
+        description,
+        created_on,
+        updated_on,
+        archived_on
+    FROM
+        roles
+    WHERE
+        archived_on is null
+    AND
+        id = $1
+`
+
+func (pg *postgres) GetRole(db database.Querier, id uint64) (result *models.Role, err error) {
+	defer pg.observe("GetRole", time.Now(), &err, &result, id)
+	r := &models.Role{}
+
+	err = db.QueryRow(roleSelectionQuery, id).Scan(&r.ID, &r.Name, &r.Description, &r.CreatedOn, &r.UpdatedOn, &r.ArchivedOn)
+
+	return r, err
+}
+
+func buildRoleListRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	sqlBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	queryBuilder := sqlBuilder.
+		Select(
+			"id",
+			"name",
+			"description",
+			"created_on",
+			"updated_on",
+			"archived_on",
+		).
+		From("roles")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, true).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetRoleList(db database.Querier, qf *models.QueryFilter) (result []models.Role, err error) {
+	defer pg.observe("GetRoleList", time.Now(), &err, &result, qf)
+	var list []models.Role
+	query, args := buildRoleListRetrievalQuery(qf)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var r models.Role
+		err := rows.Scan(
+			&r.ID,
+			&r.Name,
+			&r.Description,
+			&r.CreatedOn,
+			&r.UpdatedOn,
+			&r.ArchivedOn,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, r)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+func buildRoleCountRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("count(id)").
+		From("roles")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, false).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetRoleCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetRoleCount", time.Now(), &err, nil, qf)
+	var count uint64
+	query, args := buildRoleCountRetrievalQuery(qf)
+	err = db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+const roleCreationQuery = `
+    INSERT INTO roles
+        (
+            name, description
+        )
+    VALUES
+        (
+            $1, $2
+        )
+    RETURNING
+        id, created_on;
+`
+
+func (pg *postgres) CreateRole(db database.Querier, nu *models.Role) (createdID uint64, createdOn time.Time, err error) {
+	defer pg.observe("CreateRole", time.Now(), &err, &createdID, nu)
+	err = db.QueryRow(roleCreationQuery, &nu.Name, &nu.Description).Scan(&createdID, &createdOn)
+	return createdID, createdOn, err
+}
+
+const roleUpdateQuery = `
+    UPDATE roles
+    SET
+        name = $1,
+        description = $2,
+        updated_on = NOW()
+    WHERE id = $3
+    RETURNING updated_on;
+`
+
+func (pg *postgres) UpdateRole(db database.Querier, updated *models.Role) (result time.Time, err error) {
+	defer pg.observe("UpdateRole", time.Now(), &err, &result, updated)
+	var t time.Time
+	err = db.QueryRow(roleUpdateQuery, &updated.Name, &updated.Description, &updated.ID).Scan(&t)
+	return t, err
+}
+
+const roleDeletionQuery = `
+    UPDATE roles
+    SET archived_on = NOW()
+    WHERE id = $1
+    RETURNING archived_on
+`
+
+func (pg *postgres) DeleteRole(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteRole", time.Now(), &err, &t, id)
+	err = db.QueryRow(roleDeletionQuery, id).Scan(&t)
+	return t, err
+}
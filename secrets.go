@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"io"
+)
+
+var errSealedSecretTooShort = errors.New("sealed secret is too short")
+
+// SealWebhookSecret encrypts a webhook signing secret with AES-GCM so it can
+// be stored at rest. The key must be 16, 24 or 32 bytes long. Signing secrets
+// are encrypted rather than hashed because they are needed in plaintext to
+// sign outgoing requests.
+func SealWebhookSecret(key []byte, secret []byte) ([]byte, error) {
+	gcm, err := newSecretCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, secret, nil), nil
+}
+
+// OpenWebhookSecret decrypts a secret produced by SealWebhookSecret.
+func OpenWebhookSecret(key []byte, sealed []byte) ([]byte, error) {
+	gcm, err := newSecretCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errSealedSecretTooShort
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newSecretCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
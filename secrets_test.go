@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSealWebhookSecret(t *testing.T) {
+	t.Parallel()
+	exampleKey := []byte("0123456789abcdef0123456789abcdef")
+	exampleSecret := []byte("super secret signing key")
+
+	t.Run("round trip", func(*testing.T) {
+		sealed, err := SealWebhookSecret(exampleKey, exampleSecret)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(sealed), string(exampleSecret), "sealed secret should not contain the plaintext")
+
+		opened, err := OpenWebhookSecret(exampleKey, sealed)
+		assert.NoError(t, err)
+		assert.Equal(t, exampleSecret, opened, "opened secret should match the original")
+	})
+
+	t.Run("uses a fresh nonce every time", func(*testing.T) {
+		a, err := SealWebhookSecret(exampleKey, exampleSecret)
+		assert.NoError(t, err)
+		b, err := SealWebhookSecret(exampleKey, exampleSecret)
+		assert.NoError(t, err)
+		assert.NotEqual(t, a, b, "sealing the same secret twice should not produce the same output")
+	})
+
+	t.Run("with the wrong key", func(*testing.T) {
+		sealed, err := SealWebhookSecret(exampleKey, exampleSecret)
+		assert.NoError(t, err)
+
+		_, err = OpenWebhookSecret([]byte("fedcba9876543210fedcba9876543210"), sealed)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("with an invalid key length", func(*testing.T) {
+		_, err := SealWebhookSecret([]byte("short"), exampleSecret)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("with truncated input", func(*testing.T) {
+		_, err := OpenWebhookSecret(exampleKey, []byte("tiny"))
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+func keyedHash(key []byte, value string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// HashPasswordResetToken returns the keyed hash that password reset tokens are
+// stored and looked up by, so the plaintext token never reaches the database.
+func HashPasswordResetToken(key []byte, token string) string {
+	return keyedHash(key, token)
+}
+
+// HashEmailVerificationToken returns the keyed hash that email verification
+// tokens are stored and looked up by.
+func HashEmailVerificationToken(key []byte, token string) string {
+	return keyedHash(key, token)
+}
+
+// HashSessionID returns the keyed hash that user sessions are stored and
+// looked up by.
+func HashSessionID(key []byte, sessionID string) string {
+	return keyedHash(key, sessionID)
+}
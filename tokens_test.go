@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashPasswordResetToken(t *testing.T) {
+	t.Parallel()
+
+	t.Run("matches HMAC-SHA256", func(*testing.T) {
+		expected := "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+		actual := HashPasswordResetToken([]byte("Jefe"), "what do ya want for nothing?")
+		assert.Equal(t, expected, actual, "expected and actual hashes don't match")
+	})
+
+	t.Run("depends on the key", func(*testing.T) {
+		a := HashPasswordResetToken([]byte("one"), "token")
+		b := HashPasswordResetToken([]byte("two"), "token")
+		assert.NotEqual(t, a, b, "hashes with different keys should differ")
+	})
+}
+
+func TestHashSessionID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("matches HMAC-SHA256", func(*testing.T) {
+		expected := "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+		actual := HashSessionID([]byte("Jefe"), "what do ya want for nothing?")
+		assert.Equal(t, expected, actual, "expected and actual hashes don't match")
+	})
+}
+
+func TestHashEmailVerificationToken(t *testing.T) {
+	t.Parallel()
+
+	t.Run("matches HMAC-SHA256", func(*testing.T) {
+		expected := "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+		actual := HashEmailVerificationToken([]byte("Jefe"), "what do ya want for nothing?")
+		assert.Equal(t, expected, actual, "expected and actual hashes don't match")
+	})
+}
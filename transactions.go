@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/dairycart/dairycart/storage/database"
+
+	"github.com/lib/pq"
+)
+
+// TxOptions controls how WithTransaction starts and retries a transaction.
+type TxOptions struct {
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+	// MaxAttempts caps how many times the whole transaction is run when it
+	// fails with a serialization failure or deadlock.
+	MaxAttempts uint
+}
+
+var DefaultTxOptions = TxOptions{
+	Isolation:   sql.LevelDefault,
+	MaxAttempts: 3,
+}
+
+// txBeginner is satisfied by *sql.DB and *sql.Conn.
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// savepointCounter keeps savepoint names unique, so nested calls never
+// release or roll back a savepoint that belongs to another level.
+var savepointCounter uint64
+
+// WithTransaction runs fn inside a transaction on db, committing if fn
+// returns nil and rolling back if it returns an error or panics. Pass the tx
+// fn receives to storer methods to make them part of the transaction. A
+// RetryingQuerier or PreparedStatementCache starts it on the querier it wraps.
+//
+// Called with a *sql.Tx, including the one handed to an outer fn, it runs fn
+// inside a savepoint instead, so a failing inner call only undoes its own
+// work and the outer call decides what happens to the rest. opts is ignored
+// for nested calls.
+//
+// Transactions that fail with a serialization failure or deadlock are run
+// again from the start, fn included, up to opts.MaxAttempts times. A nil opts
+// uses DefaultTxOptions, and zero fields keep their default value.
+func (pg *postgres) WithTransaction(db database.Querier, opts *TxOptions, fn func(tx database.Querier) error) error {
+	target := unwrapQuerier(db)
+	if tx, ok := target.(*sql.Tx); ok {
+		return withSavepoint(tx, fn)
+	}
+
+	o := DefaultTxOptions
+	if opts != nil {
+		o = *opts
+		if o.MaxAttempts == 0 {
+			o.MaxAttempts = DefaultTxOptions.MaxAttempts
+		}
+	}
+
+	var beginner txBeginner
+	switch d := target.(type) {
+	case txBeginner:
+		beginner = d
+	case *ReplicaRouter:
+		beginner = d.Primary()
+	case *ReplicaSession:
+		beginner = d.Primary()
+		defer d.MarkWritten()
+	default:
+		return fmt.Errorf("cannot start a transaction on %T", db)
+	}
+
+	for n := uint(1); ; n++ {
+		err := runTransaction(beginner, o, fn)
+		if n >= o.MaxAttempts || !isTransactionConflict(err) {
+			return err
+		}
+		delay := equalJitter(backoffDelay(n, DefaultRetryConfig.BaseDelay, DefaultRetryConfig.MaxDelay))
+		pg.logger.Printf("transaction conflict (attempt %d of %d), retrying in %s: %v", n, o.MaxAttempts, delay, err)
+		pg.sleep(delay)
+	}
+}
+
+// unwrapQuerier returns what a RetryingQuerier or PreparedStatementCache runs
+// its statements on, since transactions are started there, or db itself for
+// any other querier.
+func unwrapQuerier(db interface{}) interface{} {
+	for {
+		switch d := db.(type) {
+		case *RetryingQuerier:
+			db = d.db
+		case *PreparedStatementCache:
+			db = d.db
+		default:
+			return db
+		}
+	}
+}
+
+// isTransactionConflict reports whether err means the transaction lost a
+// race with another one and can succeed if it is run again.
+func isTransactionConflict(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	name := pqErr.Code.Name()
+	return name == "serialization_failure" || name == "deadlock_detected"
+}
+
+// rollbackError keeps err as the cause, since it is what the caller acts on,
+// while still reporting that rolling back failed.
+func rollbackError(err error, rollbackErr error) error {
+	if rollbackErr == nil {
+		return err
+	}
+	return fmt.Errorf("%w (rolling back also failed: %v)", err, rollbackErr)
+}
+
+func runTransaction(db txBeginner, opts TxOptions, fn func(tx database.Querier) error) (err error) {
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly})
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		return rollbackError(err, tx.Rollback())
+	}
+	return tx.Commit()
+}
+
+func withSavepoint(tx *sql.Tx, fn func(tx database.Querier) error) (err error) {
+	name := fmt.Sprintf("dairycart_savepoint_%d", atomic.AddUint64(&savepointCounter, 1))
+	if _, err = tx.Exec("SAVEPOINT " + name); err != nil {
+		return err
+	}
+
+	rollback := func() error {
+		_, rollbackErr := tx.Exec("ROLLBACK TO SAVEPOINT " + name)
+		return rollbackErr
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		return rollbackError(err, rollback())
+	}
+	_, err = tx.Exec("RELEASE SAVEPOINT " + name)
+	return err
+}
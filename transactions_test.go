@@ -0,0 +1,301 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dairycart/dairycart/storage/database"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+const (
+	savepointPattern         = `^SAVEPOINT dairycart_savepoint_\d+$`
+	releaseSavepointPattern  = `^RELEASE SAVEPOINT dairycart_savepoint_\d+$`
+	rollbackSavepointPattern = `^ROLLBACK TO SAVEPOINT dairycart_savepoint_\d+$`
+)
+
+type recordingBeginner struct {
+	db   *sql.DB
+	opts []*sql.TxOptions
+}
+
+func (b *recordingBeginner) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	b.opts = append(b.opts, opts)
+	return b.db.BeginTx(ctx, opts)
+}
+
+func (b *recordingBeginner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return b.db.Exec(query, args...)
+}
+
+func (b *recordingBeginner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return b.db.Query(query, args...)
+}
+
+func (b *recordingBeginner) QueryRow(query string, args ...interface{}) *sql.Row {
+	return b.db.QueryRow(query, args...)
+}
+
+func TestWithTransaction(t *testing.T) {
+	t.Parallel()
+	writeQuery := `UPDATE products SET archived_on = NOW() WHERE id = $1`
+
+	setup := func(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *postgres, *[]time.Duration) {
+		t.Helper()
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		client := NewPostgres()
+		client.SetLogger(&recordingLogger{})
+		var slept []time.Duration
+		client.sleep = func(d time.Duration) { slept = append(slept, d) }
+		return mockDB, mock, client, &slept
+	}
+
+	archive := func(tx database.Querier) error {
+		_, err := tx.Exec(writeQuery, 1)
+		return err
+	}
+
+	t.Run("commits", func(*testing.T) {
+		mockDB, mock, client, _ := setup(t)
+		defer mockDB.Close()
+		mock.ExpectBegin()
+		mock.ExpectExec(formatQueryForSQLMock(writeQuery)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := client.WithTransaction(mockDB, nil, archive)
+		assert.NoError(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("rolls back on error", func(*testing.T) {
+		mockDB, mock, client, _ := setup(t)
+		defer mockDB.Close()
+		expected := errors.New("pineapple on pizza")
+		mock.ExpectBegin()
+		mock.ExpectExec(formatQueryForSQLMock(writeQuery)).WithArgs(1).WillReturnError(expected)
+		mock.ExpectRollback()
+
+		err := client.WithTransaction(mockDB, nil, archive)
+		assert.Equal(t, expected, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("rolls back on panic", func(*testing.T) {
+		mockDB, mock, client, _ := setup(t)
+		defer mockDB.Close()
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		assert.Panics(t, func() {
+			client.WithTransaction(mockDB, nil, func(database.Querier) error {
+				panic("pineapple on pizza")
+			})
+		})
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error beginning", func(*testing.T) {
+		mockDB, mock, client, _ := setup(t)
+		defer mockDB.Close()
+		mock.ExpectBegin().WillReturnError(errors.New("pineapple on pizza"))
+
+		err := client.WithTransaction(mockDB, nil, archive)
+		assert.NotNil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("releases nested savepoints", func(*testing.T) {
+		mockDB, mock, client, _ := setup(t)
+		defer mockDB.Close()
+		mock.ExpectBegin()
+		mock.ExpectExec(savepointPattern).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(formatQueryForSQLMock(writeQuery)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(releaseSavepointPattern).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := client.WithTransaction(mockDB, nil, func(tx database.Querier) error {
+			return client.WithTransaction(tx, nil, archive)
+		})
+		assert.NoError(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("rolls back nested savepoints only", func(*testing.T) {
+		mockDB, mock, client, _ := setup(t)
+		defer mockDB.Close()
+		innerErr := errors.New("pineapple on pizza")
+		mock.ExpectBegin()
+		mock.ExpectExec(formatQueryForSQLMock(writeQuery)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(savepointPattern).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(formatQueryForSQLMock(writeQuery)).WithArgs(2).WillReturnError(innerErr)
+		mock.ExpectExec(rollbackSavepointPattern).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		var nestedErr error
+		err := client.WithTransaction(mockDB, nil, func(tx database.Querier) error {
+			if err := archive(tx); err != nil {
+				return err
+			}
+			nestedErr = client.WithTransaction(tx, nil, func(tx database.Querier) error {
+				_, err := tx.Exec(writeQuery, 2)
+				return err
+			})
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, innerErr, nestedErr)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("rolls back nested savepoints on panic", func(*testing.T) {
+		mockDB, mock, client, _ := setup(t)
+		defer mockDB.Close()
+		mock.ExpectBegin()
+		mock.ExpectExec(savepointPattern).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(rollbackSavepointPattern).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		assert.Panics(t, func() {
+			client.WithTransaction(mockDB, nil, func(tx database.Querier) error {
+				return client.WithTransaction(tx, nil, func(database.Querier) error {
+					panic("pineapple on pizza")
+				})
+			})
+		})
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("retries serialization failures", func(*testing.T) {
+		mockDB, mock, client, slept := setup(t)
+		defer mockDB.Close()
+		mock.ExpectBegin()
+		mock.ExpectExec(formatQueryForSQLMock(writeQuery)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit().WillReturnError(&pq.Error{Code: "40001"})
+		mock.ExpectBegin()
+		mock.ExpectExec(formatQueryForSQLMock(writeQuery)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		runs := 0
+		err := client.WithTransaction(mockDB, nil, func(tx database.Querier) error {
+			runs++
+			return archive(tx)
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, runs)
+		assert.Len(t, *slept, 1)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("gives up after max attempts", func(*testing.T) {
+		mockDB, mock, client, _ := setup(t)
+		defer mockDB.Close()
+		deadlock := &pq.Error{Code: "40P01"}
+		for i := 0; i < 2; i++ {
+			mock.ExpectBegin()
+			mock.ExpectExec(formatQueryForSQLMock(writeQuery)).WithArgs(1).WillReturnError(deadlock)
+			mock.ExpectRollback()
+		}
+
+		err := client.WithTransaction(mockDB, &TxOptions{MaxAttempts: 2}, archive)
+		assert.Equal(t, deadlock, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with isolation level", func(*testing.T) {
+		mockDB, mock, client, _ := setup(t)
+		defer mockDB.Close()
+		beginner := &recordingBeginner{db: mockDB}
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		err := client.WithTransaction(beginner, &TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}, func(database.Querier) error {
+			return nil
+		})
+		assert.NoError(t, err)
+		require.Len(t, beginner.opts, 1)
+		assert.Equal(t, &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}, beginner.opts[0])
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with replica router", func(*testing.T) {
+		mockDB, mock, client, _ := setup(t)
+		defer mockDB.Close()
+		router := client.NewReplicaRouter(mockDB, nil, ReplicaRouterConfig{})
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		err := client.WithTransaction(router, nil, func(database.Querier) error { return nil })
+		assert.NoError(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with replica session", func(*testing.T) {
+		mockDB, mock, client, _ := setup(t)
+		defer mockDB.Close()
+		router := client.NewReplicaRouter(mockDB, nil, ReplicaRouterConfig{})
+		session, other := router.Session(), router.Session()
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		err := client.WithTransaction(session, nil, func(database.Querier) error { return nil })
+		assert.NoError(t, err)
+		assert.True(t, session.recentlyWritten(), "the session's reads should stick to the primary after a transaction")
+		assert.False(t, other.recentlyWritten(), "other sessions should keep reading from replicas")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with retrying querier", func(*testing.T) {
+		mockDB, mock, client, _ := setup(t)
+		defer mockDB.Close()
+		q := client.NewRetryingQuerier(mockDB, RetryConfig{})
+		mock.ExpectBegin()
+		mock.ExpectExec(formatQueryForSQLMock(writeQuery)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := client.WithTransaction(q, nil, archive)
+		assert.NoError(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with retrying querier inside a transaction", func(*testing.T) {
+		mockDB, mock, client, _ := setup(t)
+		defer mockDB.Close()
+		mock.ExpectBegin()
+		mock.ExpectExec(savepointPattern).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(formatQueryForSQLMock(writeQuery)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(releaseSavepointPattern).WillReturnResult(sqlmock.NewResult(0, 0))
+		tx, err := mockDB.Begin()
+		require.NoError(t, err)
+
+		err = client.WithTransaction(client.NewRetryingQuerier(tx, RetryConfig{}), nil, archive)
+		assert.NoError(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with prepared statement cache", func(*testing.T) {
+		mockDB, mock, client, _ := setup(t)
+		defer mockDB.Close()
+		cache := client.PrepareStatements(mockDB)
+		mock.ExpectBegin()
+		mock.ExpectExec(formatQueryForSQLMock(writeQuery)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := client.WithTransaction(cache, nil, archive)
+		assert.NoError(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with unsupported querier", func(*testing.T) {
+		_, _, client, _ := setup(t)
+
+		err := client.WithTransaction(struct{ database.Querier }{}, nil, archive)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,204 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/dairycart/dairycart/storage/database"
+	"github.com/dairycart/dairymodels/v1"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const userRoleGrantQuery = `
+    INSERT INTO user_roles
+        (
+            user_id, role_id
+        )
+    VALUES
+        (
+            $1, $2
+        )
+    ON CONFLICT (user_id, role_id) WHERE archived_on IS NULL
+    DO UPDATE SET role_id = EXCLUDED.role_id
+    RETURNING
+        created_on;
+`
+
+func (pg *postgres) GrantUserRole(db database.Querier, userID uint64, roleID uint64) (grantedOn time.Time, err error) {
+	defer pg.observe("GrantUserRole", time.Now(), &err, &grantedOn, userID, roleID)
+	err = db.QueryRow(userRoleGrantQuery, userID, roleID).Scan(&grantedOn)
+	return grantedOn, err
+}
+
+const userRoleRevocationQuery = `
+    UPDATE user_roles
+    SET archived_on = NOW()
+    WHERE user_id = $1
+    AND role_id = $2
+    AND archived_on IS NULL
+    RETURNING archived_on
+`
+
+func (pg *postgres) RevokeUserRole(db database.Querier, userID uint64, roleID uint64) (t time.Time, err error) {
+	defer pg.observe("RevokeUserRole", time.Now(), &err, &t, userID, roleID)
+	err = db.QueryRow(userRoleRevocationQuery, userID, roleID).Scan(&t)
+	return t, err
+}
+
+const userRoleExistenceQuery = `SELECT EXISTS(SELECT id FROM user_roles WHERE id = $1 and archived_on IS NULL);`
+
+func (pg *postgres) UserRoleExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("UserRoleExists", time.Now(), &err, &result, id)
+	var exists string
+
+	err = db.QueryRow(userRoleExistenceQuery, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return exists == "true", err
+}
+
+const userRoleSelectionQuery = `
+    SELECT
+        id,
+        user_id,
+        role_id,
+        created_on,
+        updated_on,
+        archived_on
+    FROM
+        user_roles
+    WHERE
+        archived_on is null
+    AND
+        id = $1
+`
+
+func (pg *postgres) GetUserRole(db database.Querier, id uint64) (result *models.UserRole, err error) {
+	defer pg.observe("GetUserRole", time.Now(), &err, &result, id)
+	u := &models.UserRole{}
+
+	err = db.QueryRow(userRoleSelectionQuery, id).Scan(&u.ID, &u.UserID, &u.RoleID, &u.CreatedOn, &u.UpdatedOn, &u.ArchivedOn)
+
+	return u, err
+}
+
+func buildUserRoleListRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	sqlBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	queryBuilder := sqlBuilder.
+		Select(
+			"id",
+			"user_id",
+			"role_id",
+			"created_on",
+			"updated_on",
+			"archived_on",
+		).
+		From("user_roles")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, true).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetUserRoleList(db database.Querier, qf *models.QueryFilter) (result []models.UserRole, err error) {
+	defer pg.observe("GetUserRoleList", time.Now(), &err, &result, qf)
+	var list []models.UserRole
+	query, args := buildUserRoleListRetrievalQuery(qf)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var u models.UserRole
+		err := rows.Scan(
+			&u.ID,
+			&u.UserID,
+			&u.RoleID,
+			&u.CreatedOn,
+			&u.UpdatedOn,
+			&u.ArchivedOn,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, u)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+func buildUserRoleCountRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("count(id)").
+		From("user_roles")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, false).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetUserRoleCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetUserRoleCount", time.Now(), &err, nil, qf)
+	var count uint64
+	query, args := buildUserRoleCountRetrievalQuery(qf)
+	err = db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+const userRoleCreationQuery = `
+    INSERT INTO user_roles
+        (
+            user_id, role_id
+        )
+    VALUES
+        (
+            $1, $2
+        )
+    RETURNING
+        id, created_on;
+`
+
+func (pg *postgres) CreateUserRole(db database.Querier, nu *models.UserRole) (createdID uint64, createdOn time.Time, err error) {
+	defer pg.observe("CreateUserRole", time.Now(), &err, &createdID, nu)
+	err = db.QueryRow(userRoleCreationQuery, &nu.UserID, &nu.RoleID).Scan(&createdID, &createdOn)
+	return createdID, createdOn, err
+}
+
+const userRoleUpdateQuery = `
+    UPDATE user_roles
+    SET
+        user_id = $1,
+        role_id = $2,
+        updated_on = NOW()
+    WHERE id = $3
+    RETURNING updated_on;
+`
+
+func (pg *postgres) UpdateUserRole(db database.Querier, updated *models.UserRole) (result time.Time, err error) {
+	defer pg.observe("UpdateUserRole", time.Now(), &err, &result, updated)
+	var t time.Time
+	err = db.QueryRow(userRoleUpdateQuery, &updated.UserID, &updated.RoleID, &updated.ID).Scan(&t)
+	return t, err
+}
+
+const userRoleDeletionQuery = `
+    UPDATE user_roles
+    SET archived_on = NOW()
+    WHERE id = $1
+    RETURNING archived_on
+`
+
+func (pg *postgres) DeleteUserRole(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteUserRole", time.Now(), &err, &t, id)
+	err = db.QueryRow(userRoleDeletionQuery, id).Scan(&t)
+	return t, err
+}
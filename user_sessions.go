@@ -0,0 +1,302 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/dairycart/dairycart/storage/database"
+	"github.com/dairycart/dairymodels/v1"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const userSessionTouchQuery = `
+    UPDATE user_sessions
+    SET last_seen_on = NOW()
+    WHERE session_id_hash = $1
+    AND archived_on IS NULL
+    AND NOW() < expires_on
+    RETURNING
+        id,
+        user_id,
+        session_id_hash,
+        user_agent,
+        ip_address,
+        last_seen_on,
+        expires_on,
+        created_on,
+        updated_on,
+        archived_on
+`
+
+func (pg *postgres) TouchUserSession(db database.Querier, sessionIDHash string) (result *models.UserSession, err error) {
+	defer pg.observe("TouchUserSession", time.Now(), &err, &result, sessionIDHash)
+	u := &models.UserSession{}
+
+	err = db.QueryRow(userSessionTouchQuery, sessionIDHash).Scan(&u.ID, &u.UserID, &u.SessionIDHash, &u.UserAgent, &u.IPAddress, &u.LastSeenOn, &u.ExpiresOn, &u.CreatedOn, &u.UpdatedOn, &u.ArchivedOn)
+
+	return u, err
+}
+
+const userSessionRevocationQuery = `
+    UPDATE user_sessions
+    SET archived_on = NOW()
+    WHERE session_id_hash = $1
+    AND archived_on IS NULL
+    RETURNING archived_on
+`
+
+func (pg *postgres) RevokeUserSession(db database.Querier, sessionIDHash string) (t time.Time, err error) {
+	defer pg.observe("RevokeUserSession", time.Now(), &err, &t, sessionIDHash)
+	err = db.QueryRow(userSessionRevocationQuery, sessionIDHash).Scan(&t)
+	return t, err
+}
+
+const userSessionRevocationQueryByUserID = `
+    UPDATE user_sessions
+    SET archived_on = NOW()
+    WHERE user_id = $1
+    AND archived_on IS NULL
+`
+
+func (pg *postgres) RevokeUserSessionsForUser(db database.Querier, userID uint64) (result int64, err error) {
+	defer pg.observe("RevokeUserSessionsForUser", time.Now(), &err, &result, userID)
+	res, err := db.Exec(userSessionRevocationQueryByUserID, userID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+const userSessionActiveQueryByUserID = `
+    SELECT
+        id,
+        user_id,
+        session_id_hash,
+        user_agent,
+        ip_address,
+        last_seen_on,
+        expires_on,
+        created_on,
+        updated_on,
+        archived_on
+    FROM
+        user_sessions
+    WHERE
+        archived_on is null
+    AND
+        user_id = $1
+    AND
+        NOW() < expires_on
+    ORDER BY
+        last_seen_on DESC
+`
+
+func (pg *postgres) GetActiveUserSessionsForUser(db database.Querier, userID uint64) (result []models.UserSession, err error) {
+	defer pg.observe("GetActiveUserSessionsForUser", time.Now(), &err, &result, userID)
+	var list []models.UserSession
+
+	rows, err := db.Query(userSessionActiveQueryByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var u models.UserSession
+		err := rows.Scan(
+			&u.ID,
+			&u.UserID,
+			&u.SessionIDHash,
+			&u.UserAgent,
+			&u.IPAddress,
+			&u.LastSeenOn,
+			&u.ExpiresOn,
+			&u.CreatedOn,
+			&u.UpdatedOn,
+			&u.ArchivedOn,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, u)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+const userSessionExistenceQuery = `SELECT EXISTS(SELECT id FROM user_sessions WHERE id = $1 and archived_on IS NULL);`
+
+func (pg *postgres) UserSessionExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("UserSessionExists", time.Now(), &err, &result, id)
+	var exists string
+
+	err = db.QueryRow(userSessionExistenceQuery, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return exists == "true", err
+}
+
+const userSessionSelectionQuery = `
+    SELECT
+        id,
+        user_id,
+        session_id_hash,
+        user_agent,
+        ip_address,
+        last_seen_on,
+        expires_on,
+        created_on,
+        updated_on,
+        archived_on
+    FROM
+        user_sessions
+    WHERE
+        archived_on is null
+    AND
+        id = $1
+`
+
+func (pg *postgres) GetUserSession(db database.Querier, id uint64) (result *models.UserSession, err error) {
+	defer pg.observe("GetUserSession", time.Now(), &err, &result, id)
+	u := &models.UserSession{}
+
+	err = db.QueryRow(userSessionSelectionQuery, id).Scan(&u.ID, &u.UserID, &u.SessionIDHash, &u.UserAgent, &u.IPAddress, &u.LastSeenOn, &u.ExpiresOn, &u.CreatedOn, &u.UpdatedOn, &u.ArchivedOn)
+
+	return u, err
+}
+
+func buildUserSessionListRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	sqlBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	queryBuilder := sqlBuilder.
+		Select(
+			"id",
+			"user_id",
+			"session_id_hash",
+			"user_agent",
+			"ip_address",
+			"last_seen_on",
+			"expires_on",
+			"created_on",
+			"updated_on",
+			"archived_on",
+		).
+		From("user_sessions")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, true).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetUserSessionList(db database.Querier, qf *models.QueryFilter) (result []models.UserSession, err error) {
+	defer pg.observe("GetUserSessionList", time.Now(), &err, &result, qf)
+	var list []models.UserSession
+	query, args := buildUserSessionListRetrievalQuery(qf)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var u models.UserSession
+		err := rows.Scan(
+			&u.ID,
+			&u.UserID,
+			&u.SessionIDHash,
+			&u.UserAgent,
+			&u.IPAddress,
+			&u.LastSeenOn,
+			&u.ExpiresOn,
+			&u.CreatedOn,
+			&u.UpdatedOn,
+			&u.ArchivedOn,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, u)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+func buildUserSessionCountRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("count(id)").
+		From("user_sessions")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, false).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetUserSessionCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetUserSessionCount", time.Now(), &err, nil, qf)
+	var count uint64
+	query, args := buildUserSessionCountRetrievalQuery(qf)
+	err = db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+const userSessionCreationQuery = `
+    INSERT INTO user_sessions
+        (
+            user_id, session_id_hash, user_agent, ip_address, last_seen_on, expires_on
+        )
+    VALUES
+        (
+            $1, $2, $3, CAST(NULLIF($4, '') AS inet), $5, $6
+        )
+    RETURNING
+        id, created_on;
+`
+
+func (pg *postgres) CreateUserSession(db database.Querier, nu *models.UserSession) (createdID uint64, createdOn time.Time, err error) {
+	defer pg.observe("CreateUserSession", time.Now(), &err, &createdID, nu)
+	err = db.QueryRow(userSessionCreationQuery, &nu.UserID, &nu.SessionIDHash, &nu.UserAgent, &nu.IPAddress, &nu.LastSeenOn, &nu.ExpiresOn).Scan(&createdID, &createdOn)
+	return createdID, createdOn, err
+}
+
+const userSessionUpdateQuery = `
+    UPDATE user_sessions
+    SET
+        user_id = $1,
+        session_id_hash = $2,
+        user_agent = $3,
+        ip_address = CAST(NULLIF($4, '') AS inet),
+        last_seen_on = $5,
+        expires_on = $6,
+        updated_on = NOW()
+    WHERE id = $7
+    RETURNING updated_on;
+`
+
+func (pg *postgres) UpdateUserSession(db database.Querier, updated *models.UserSession) (result time.Time, err error) {
+	defer pg.observe("UpdateUserSession", time.Now(), &err, &result, updated)
+	var t time.Time
+	err = db.QueryRow(userSessionUpdateQuery, &updated.UserID, &updated.SessionIDHash, &updated.UserAgent, &updated.IPAddress, &updated.LastSeenOn, &updated.ExpiresOn, &updated.ID).Scan(&t)
+	return t, err
+}
+
+const userSessionDeletionQuery = `
+    UPDATE user_sessions
+    SET archived_on = NOW()
+    WHERE id = $1
+    RETURNING archived_on
+`
+
+func (pg *postgres) DeleteUserSession(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteUserSession", time.Now(), &err, &t, id)
+	err = db.QueryRow(userSessionDeletionQuery, id).Scan(&t)
+	return t, err
+}
@@ -0,0 +1,599 @@
+package postgres
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strconv"
+	"testing"
+
+	// internal dependencies
+	"github.com/dairycart/dairymodels/v1"
+
+	// external dependencies
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func setUserSessionTouchQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, sessionIDHash string, toReturn *models.UserSession, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(userSessionTouchQuery)
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"user_id",
+		"session_id_hash",
+		"user_agent",
+		"ip_address",
+		"last_seen_on",
+		"expires_on",
+		"created_on",
+		"updated_on",
+		"archived_on",
+	}).AddRow(
+		toReturn.ID,
+		toReturn.UserID,
+		toReturn.SessionIDHash,
+		toReturn.UserAgent,
+		toReturn.IPAddress,
+		toReturn.LastSeenOn,
+		toReturn.ExpiresOn,
+		toReturn.CreatedOn,
+		toReturn.UpdatedOn,
+		toReturn.ArchivedOn,
+	)
+	mock.ExpectQuery(query).WithArgs(sessionIDHash).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestTouchUserSession(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+
+	exampleSessionIDHash := "deadbeef"
+	expected := &models.UserSession{ID: 1, UserID: 1, SessionIDHash: exampleSessionIDHash}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setUserSessionTouchQueryExpectation(t, mock, exampleSessionIDHash, expected, nil)
+		actual, err := client.TouchUserSession(mockDB, exampleSessionIDHash)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected usersession did not match actual usersession")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with an expired or revoked session", func(t *testing.T) {
+		setUserSessionTouchQueryExpectation(t, mock, exampleSessionIDHash, expected, sql.ErrNoRows)
+		_, err := client.TouchUserSession(mockDB, exampleSessionIDHash)
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setUserSessionRevocationQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, sessionIDHash string, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(userSessionRevocationQuery)
+	exampleRows := sqlmock.NewRows([]string{"archived_on"}).AddRow(buildTestTime(t))
+	mock.ExpectQuery(query).WithArgs(sessionIDHash).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestRevokeUserSession(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleSessionIDHash := "deadbeef"
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setUserSessionRevocationQueryExpectation(t, mock, exampleSessionIDHash, nil)
+		expected := buildTestTime(t)
+		actual, err := client.RevokeUserSession(mockDB, exampleSessionIDHash)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected revocation time did not match actual revocation time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func TestRevokeUserSessionsForUser(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleUserID := uint64(1)
+	client := NewPostgres()
+	query := formatQueryForSQLMock(userSessionRevocationQueryByUserID)
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(exampleUserID).WillReturnResult(sqlmock.NewResult(0, 2))
+		actual, err := client.RevokeUserSessionsForUser(mockDB, exampleUserID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), actual, "expected revoked session count did not match actual count")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with a database error", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(exampleUserID).WillReturnError(errors.New("pineapple on pizza"))
+		actual, err := client.RevokeUserSessionsForUser(mockDB, exampleUserID)
+
+		assert.NotNil(t, err)
+		assert.Zero(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setActiveUserSessionsForUserQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, userID uint64, example *models.UserSession, rowErr error, err error) {
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"user_id",
+		"session_id_hash",
+		"user_agent",
+		"ip_address",
+		"last_seen_on",
+		"expires_on",
+		"created_on",
+		"updated_on",
+		"archived_on",
+	}).AddRow(
+		example.ID,
+		example.UserID,
+		example.SessionIDHash,
+		example.UserAgent,
+		example.IPAddress,
+		example.LastSeenOn,
+		example.ExpiresOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).AddRow(
+		example.ID,
+		example.UserID,
+		example.SessionIDHash,
+		example.UserAgent,
+		example.IPAddress,
+		example.LastSeenOn,
+		example.ExpiresOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).AddRow(
+		example.ID,
+		example.UserID,
+		example.SessionIDHash,
+		example.UserAgent,
+		example.IPAddress,
+		example.LastSeenOn,
+		example.ExpiresOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).RowError(1, rowErr)
+
+	mock.ExpectQuery(formatQueryForSQLMock(userSessionActiveQueryByUserID)).
+		WithArgs(userID).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestGetActiveUserSessionsForUser(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+
+	exampleUserID := uint64(1)
+	example := &models.UserSession{UserID: exampleUserID}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setActiveUserSessionsForUserQueryExpectation(t, mock, exampleUserID, example, nil, nil)
+		actual, err := client.GetActiveUserSessionsForUser(mockDB, exampleUserID)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, actual, "list retrieval method should not return an empty slice")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error executing query", func(t *testing.T) {
+		setActiveUserSessionsForUserQueryExpectation(t, mock, exampleUserID, example, nil, errors.New("pineapple on pizza"))
+		actual, err := client.GetActiveUserSessionsForUser(mockDB, exampleUserID)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error scanning values", func(t *testing.T) {
+		exampleRows := sqlmock.NewRows([]string{"things"}).AddRow("stuff")
+		mock.ExpectQuery(formatQueryForSQLMock(userSessionActiveQueryByUserID)).
+			WillReturnRows(exampleRows)
+
+		actual, err := client.GetActiveUserSessionsForUser(mockDB, exampleUserID)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with with row errors", func(t *testing.T) {
+		setActiveUserSessionsForUserQueryExpectation(t, mock, exampleUserID, example, errors.New("pineapple on pizza"), nil)
+		actual, err := client.GetActiveUserSessionsForUser(mockDB, exampleUserID)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setUserSessionExistenceQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, shouldExist bool, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(userSessionExistenceQuery)
+
+	mock.ExpectQuery(query).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(strconv.FormatBool(shouldExist))).
+		WillReturnError(err)
+}
+
+func TestUserSessionExists(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	client := NewPostgres()
+
+	t.Run("existing", func(t *testing.T) {
+		setUserSessionExistenceQueryExpectation(t, mock, exampleID, true, nil)
+		actual, err := client.UserSessionExists(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.True(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with no rows found", func(t *testing.T) {
+		setUserSessionExistenceQueryExpectation(t, mock, exampleID, true, sql.ErrNoRows)
+		actual, err := client.UserSessionExists(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.False(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with a database error", func(t *testing.T) {
+		setUserSessionExistenceQueryExpectation(t, mock, exampleID, true, errors.New("pineapple on pizza"))
+		actual, err := client.UserSessionExists(mockDB, exampleID)
+
+		assert.NotNil(t, err)
+		assert.False(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setUserSessionReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, toReturn *models.UserSession, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(userSessionSelectionQuery)
+
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"user_id",
+		"session_id_hash",
+		"user_agent",
+		"ip_address",
+		"last_seen_on",
+		"expires_on",
+		"created_on",
+		"updated_on",
+		"archived_on",
+	}).AddRow(
+		toReturn.ID,
+		toReturn.UserID,
+		toReturn.SessionIDHash,
+		toReturn.UserAgent,
+		toReturn.IPAddress,
+		toReturn.LastSeenOn,
+		toReturn.ExpiresOn,
+		toReturn.CreatedOn,
+		toReturn.UpdatedOn,
+		toReturn.ArchivedOn,
+	)
+	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestGetUserSession(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	expected := &models.UserSession{ID: exampleID}
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setUserSessionReadQueryExpectation(t, mock, exampleID, expected, nil)
+		actual, err := client.GetUserSession(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected usersession did not match actual usersession")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setUserSessionListReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, qf *models.QueryFilter, example *models.UserSession, rowErr error, err error) {
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"user_id",
+		"session_id_hash",
+		"user_agent",
+		"ip_address",
+		"last_seen_on",
+		"expires_on",
+		"created_on",
+		"updated_on",
+		"archived_on",
+	}).AddRow(
+		example.ID,
+		example.UserID,
+		example.SessionIDHash,
+		example.UserAgent,
+		example.IPAddress,
+		example.LastSeenOn,
+		example.ExpiresOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).AddRow(
+		example.ID,
+		example.UserID,
+		example.SessionIDHash,
+		example.UserAgent,
+		example.IPAddress,
+		example.LastSeenOn,
+		example.ExpiresOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).AddRow(
+		example.ID,
+		example.UserID,
+		example.SessionIDHash,
+		example.UserAgent,
+		example.IPAddress,
+		example.LastSeenOn,
+		example.ExpiresOn,
+		example.CreatedOn,
+		example.UpdatedOn,
+		example.ArchivedOn,
+	).RowError(1, rowErr)
+
+	query, _ := buildUserSessionListRetrievalQuery(qf)
+
+	mock.ExpectQuery(formatQueryForSQLMock(query)).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestGetUserSessionList(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	example := &models.UserSession{ID: exampleID}
+	client := NewPostgres()
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setUserSessionListReadQueryExpectation(t, mock, exampleQF, example, nil, nil)
+		actual, err := client.GetUserSessionList(mockDB, exampleQF)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, actual, "list retrieval method should not return an empty slice")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error executing query", func(t *testing.T) {
+		setUserSessionListReadQueryExpectation(t, mock, exampleQF, example, nil, errors.New("pineapple on pizza"))
+		actual, err := client.GetUserSessionList(mockDB, exampleQF)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error scanning values", func(t *testing.T) {
+		exampleRows := sqlmock.NewRows([]string{"things"}).AddRow("stuff")
+		query, _ := buildUserSessionListRetrievalQuery(exampleQF)
+		mock.ExpectQuery(formatQueryForSQLMock(query)).
+			WillReturnRows(exampleRows)
+
+		actual, err := client.GetUserSessionList(mockDB, exampleQF)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with with row errors", func(t *testing.T) {
+		setUserSessionListReadQueryExpectation(t, mock, exampleQF, example, errors.New("pineapple on pizza"), nil)
+		actual, err := client.GetUserSessionList(mockDB, exampleQF)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func TestBuildUserSessionCountRetrievalQuery(t *testing.T) {
+	t.Parallel()
+
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+	expected := `SELECT count(id) FROM user_sessions WHERE archived_on IS NULL LIMIT 25`
+	actual, _ := buildUserSessionCountRetrievalQuery(exampleQF)
+
+	assert.Equal(t, expected, actual, "expected and actual queries should match")
+}
+
+func setUserSessionCountRetrievalQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, qf *models.QueryFilter, count uint64, err error) {
+	t.Helper()
+	query, args := buildUserSessionCountRetrievalQuery(qf)
+	query = formatQueryForSQLMock(query)
+
+	var argsToExpect []driver.Value
+	for _, x := range args {
+		argsToExpect = append(argsToExpect, x)
+	}
+
+	exampleRow := sqlmock.NewRows([]string{"count"}).AddRow(count)
+	mock.ExpectQuery(query).WithArgs(argsToExpect...).WillReturnRows(exampleRow).WillReturnError(err)
+}
+
+func TestGetUserSessionCount(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+	expected := uint64(123)
+	exampleQF := &models.QueryFilter{
+		Limit: 25,
+		Page:  1,
+	}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setUserSessionCountRetrievalQueryExpectation(t, mock, exampleQF, expected, nil)
+		actual, err := client.GetUserSessionCount(mockDB, exampleQF)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "count retrieval method should return the expected value")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setUserSessionCreationQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toCreate *models.UserSession, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(userSessionCreationQuery)
+	tt := buildTestTime(t)
+	exampleRows := sqlmock.NewRows([]string{"id", "created_on"}).AddRow(uint64(1), tt)
+	mock.ExpectQuery(query).
+		WithArgs(
+			toCreate.UserID,
+			toCreate.SessionIDHash,
+			toCreate.UserAgent,
+			toCreate.IPAddress,
+			toCreate.LastSeenOn,
+			toCreate.ExpiresOn,
+		).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestCreateUserSession(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	expectedID := uint64(1)
+	exampleInput := &models.UserSession{ID: expectedID}
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setUserSessionCreationQueryExpectation(t, mock, exampleInput, nil)
+		expectedCreatedOn := buildTestTime(t)
+
+		actualID, actualCreatedOn, err := client.CreateUserSession(mockDB, exampleInput)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedID, actualID, "expected and actual IDs don't match")
+		assert.Equal(t, expectedCreatedOn, actualCreatedOn, "expected creation time did not match actual creation time")
+
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setUserSessionUpdateQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toUpdate *models.UserSession, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(userSessionUpdateQuery)
+	exampleRows := sqlmock.NewRows([]string{"updated_on"}).AddRow(buildTestTime(t))
+	mock.ExpectQuery(query).
+		WithArgs(
+			toUpdate.UserID,
+			toUpdate.SessionIDHash,
+			toUpdate.UserAgent,
+			toUpdate.IPAddress,
+			toUpdate.LastSeenOn,
+			toUpdate.ExpiresOn,
+			toUpdate.ID,
+		).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestUpdateUserSessionByID(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleInput := &models.UserSession{ID: uint64(1)}
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setUserSessionUpdateQueryExpectation(t, mock, exampleInput, nil)
+		expected := buildTestTime(t)
+		actual, err := client.UpdateUserSession(mockDB, exampleInput)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected deletion time did not match actual deletion time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setUserSessionDeletionQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(userSessionDeletionQuery)
+	exampleRows := sqlmock.NewRows([]string{"archived_on"}).AddRow(buildTestTime(t))
+	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestDeleteUserSessionByID(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setUserSessionDeletionQueryExpectation(t, mock, exampleID, nil)
+		expected := buildTestTime(t)
+		actual, err := client.DeleteUserSession(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected deletion time did not match actual deletion time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		setUserSessionDeletionQueryExpectation(t, mock, exampleID, nil)
+		expected := buildTestTime(t)
+		tx, err := mockDB.Begin()
+		assert.NoError(t, err, "no error should be returned setting up a transaction in the mock DB")
+		actual, err := client.DeleteUserSession(tx, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected deletion time did not match actual deletion time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
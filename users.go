@@ -2,12 +2,14 @@ package postgres
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/dairycart/dairycart/storage/database"
 	"github.com/dairycart/dairymodels/v1"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
 )
 
 const userQueryByUsername = `
@@ -17,13 +19,12 @@ const userQueryByUsername = `
         last_name,
         username,
         email,
-        password,
-        salt,
         is_admin,
         password_last_changed_on,
         created_on,
         updated_on,
-        archived_on
+        archived_on,
+        verified_on
     FROM
         users
     WHERE
@@ -32,18 +33,68 @@ const userQueryByUsername = `
         username = $1
 `
 
-func (pg *postgres) GetUserByUsername(db database.Querier, username string) (*models.User, error) {
-	u := &models.User{}
-	err := db.QueryRow(userQueryByUsername, username).Scan(&u.ID, &u.FirstName, &u.LastName, &u.Username, &u.Email, &u.Password, &u.Salt, &u.IsAdmin, &u.PasswordLastChangedOn, &u.CreatedOn, &u.UpdatedOn, &u.ArchivedOn)
+func (pg *postgres) GetUserByUsername(db database.Querier, username string) (result *models.UserProfile, err error) {
+	defer pg.observe("GetUserByUsername", time.Now(), &err, &result, username)
+	u := &models.UserProfile{}
+	err = db.QueryRow(userQueryByUsername, username).Scan(&u.ID, &u.FirstName, &u.LastName, &u.Username, &u.Email, &u.IsAdmin, &u.PasswordLastChangedOn, &u.CreatedOn, &u.UpdatedOn, &u.ArchivedOn, &u.VerifiedOn)
 	return u, err
 }
 
+const userCredentialsQueryByUsername = `
+    SELECT
+        id,
+        username,
+        password,
+        salt,
+        password_last_changed_on
+    FROM
+        users
+    WHERE
+        archived_on is null
+    AND
+        username = $1
+`
+
+func (pg *postgres) GetUserCredentials(db database.Querier, username string) (result *models.UserCredentials, err error) {
+	defer pg.observe("GetUserCredentials", time.Now(), &err, &result, username)
+	c := &models.UserCredentials{}
+	err = db.QueryRow(userCredentialsQueryByUsername, username).Scan(&c.ID, &c.Username, &c.Password, &c.Salt, &c.PasswordLastChangedOn)
+	return c, err
+}
+
+const userCredentialsUpdateQuery = `
+    WITH revoked_sessions AS (
+        UPDATE user_sessions
+        SET archived_on = NOW()
+        WHERE user_id = $3
+        AND archived_on IS NULL
+        AND EXISTS(SELECT id FROM users WHERE id = $3 AND password <> $1)
+    )
+    UPDATE users
+    SET
+        password = $1,
+        salt = $2,
+        password_last_changed_on = NOW(),
+        updated_on = NOW()
+    WHERE id = $3
+    AND archived_on IS NULL
+    RETURNING password_last_changed_on;
+`
+
+func (pg *postgres) UpdateUserCredentials(db database.Querier, id uint64, password string, salt []byte) (result time.Time, err error) {
+	defer pg.observe("UpdateUserCredentials", time.Now(), &err, &result, id)
+	var t time.Time
+	err = db.QueryRow(userCredentialsUpdateQuery, password, salt, id).Scan(&t)
+	return t, err
+}
+
 const userWithUsernameExistenceQuery = `SELECT EXISTS(SELECT id FROM users WHERE username = $1 and archived_on IS NULL);`
 
-func (pg *postgres) UserWithUsernameExists(db database.Querier, sku string) (bool, error) {
+func (pg *postgres) UserWithUsernameExists(db database.Querier, sku string) (result bool, err error) {
+	defer pg.observe("UserWithUsernameExists", time.Now(), &err, &result, sku)
 	var exists string
 
-	err := db.QueryRow(userWithUsernameExistenceQuery, sku).Scan(&exists)
+	err = db.QueryRow(userWithUsernameExistenceQuery, sku).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -53,12 +104,242 @@ func (pg *postgres) UserWithUsernameExists(db database.Querier, sku string) (boo
 	return exists == "true", err
 }
 
+const userQueryByEmail = `
+    SELECT
+        id,
+        first_name,
+        last_name,
+        username,
+        email,
+        is_admin,
+        password_last_changed_on,
+        created_on,
+        updated_on,
+        archived_on,
+        verified_on
+    FROM
+        users
+    WHERE
+        archived_on is null
+    AND
+        lower(email) = lower($1)
+`
+
+func (pg *postgres) GetUserByEmail(db database.Querier, email string) (result *models.UserProfile, err error) {
+	defer pg.observe("GetUserByEmail", time.Now(), &err, &result, email)
+	u := &models.UserProfile{}
+	err = db.QueryRow(userQueryByEmail, email).Scan(&u.ID, &u.FirstName, &u.LastName, &u.Username, &u.Email, &u.IsAdmin, &u.PasswordLastChangedOn, &u.CreatedOn, &u.UpdatedOn, &u.ArchivedOn, &u.VerifiedOn)
+	return u, err
+}
+
+const userWithEmailExistenceQuery = `SELECT EXISTS(SELECT id FROM users WHERE lower(email) = lower($1) and archived_on IS NULL);`
+
+func (pg *postgres) UserWithEmailExists(db database.Querier, email string) (result bool, err error) {
+	defer pg.observe("UserWithEmailExists", time.Now(), &err, &result, email)
+	var exists string
+
+	err = db.QueryRow(userWithEmailExistenceQuery, email).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return exists == "true", err
+}
+
+const userPermissionCheckQuery = `
+    SELECT EXISTS(
+        SELECT users.id FROM users
+        WHERE users.id = $1
+        AND users.archived_on IS NULL
+        AND (
+            users.is_admin IS TRUE
+            OR EXISTS(
+                SELECT user_roles.id FROM user_roles
+                JOIN roles ON roles.id = user_roles.role_id AND roles.archived_on IS NULL
+                JOIN role_permissions ON role_permissions.role_id = roles.id AND role_permissions.archived_on IS NULL
+                JOIN permissions ON permissions.id = role_permissions.permission_id AND permissions.archived_on IS NULL
+                WHERE user_roles.user_id = users.id
+                AND user_roles.archived_on IS NULL
+                AND permissions.name = $2
+            )
+        )
+    );
+`
+
+func (pg *postgres) UserHasPermission(db database.Querier, userID uint64, permission string) (result bool, err error) {
+	defer pg.observe("UserHasPermission", time.Now(), &err, &result, userID, permission)
+	var allowed string
+
+	err = db.QueryRow(userPermissionCheckQuery, userID, permission).Scan(&allowed)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return allowed == "true", err
+}
+
+const userWithPermissionsQuery = `
+    SELECT
+        users.id,
+        users.first_name,
+        users.last_name,
+        users.username,
+        users.email,
+        users.is_admin,
+        users.password_last_changed_on,
+        users.created_on,
+        users.updated_on,
+        users.archived_on,
+        users.verified_on,
+        CASE
+            WHEN users.is_admin THEN ARRAY(SELECT name FROM permissions WHERE archived_on IS NULL ORDER BY name)
+            ELSE COALESCE(array_agg(DISTINCT permissions.name ORDER BY permissions.name) FILTER (WHERE permissions.name IS NOT NULL), '{}')
+        END AS permissions
+    FROM
+        users
+    LEFT JOIN user_roles ON user_roles.user_id = users.id AND user_roles.archived_on IS NULL
+    LEFT JOIN roles ON roles.id = user_roles.role_id AND roles.archived_on IS NULL
+    LEFT JOIN role_permissions ON role_permissions.role_id = roles.id AND role_permissions.archived_on IS NULL
+    LEFT JOIN permissions ON permissions.id = role_permissions.permission_id AND permissions.archived_on IS NULL
+    WHERE
+        users.archived_on is null
+    AND
+        users.id = $1
+    GROUP BY
+        users.id
+`
+
+func (pg *postgres) GetUserWithPermissions(db database.Querier, id uint64) (result *models.UserWithPermissions, err error) {
+	defer pg.observe("GetUserWithPermissions", time.Now(), &err, &result, id)
+	u := &models.UserWithPermissions{}
+
+	err = db.QueryRow(userWithPermissionsQuery, id).Scan(&u.ID, &u.FirstName, &u.LastName, &u.Username, &u.Email, &u.IsAdmin, &u.PasswordLastChangedOn, &u.CreatedOn, &u.UpdatedOn, &u.ArchivedOn, &u.VerifiedOn, pq.Array(&u.Permissions))
+
+	return u, err
+}
+
+const userAnonymizationQuery = `
+    WITH target AS (
+        SELECT id, username FROM users WHERE id = $1
+    ), scrubbed_login_attempts AS (
+        UPDATE login_attempts
+        SET username = 'anonymized-' || $1, ip_address = NULL
+        WHERE username IN (SELECT username FROM target)
+    ), deleted_login_lockouts AS (
+        DELETE FROM login_lockouts
+        WHERE username IN (SELECT username FROM target)
+    ), deleted_password_reset_tokens AS (
+        DELETE FROM password_reset_tokens
+        WHERE user_id IN (SELECT id FROM target)
+    ), deleted_email_verification_tokens AS (
+        DELETE FROM email_verification_tokens
+        WHERE user_id IN (SELECT id FROM target)
+    ), deleted_sessions AS (
+        DELETE FROM user_sessions
+        WHERE user_id IN (SELECT id FROM target)
+    ), redacted_outbox_events AS (
+        UPDATE outbox_events
+        SET payload = jsonb_build_object('id', payload->'id'), updated_on = NOW()
+        WHERE event_type IN ('user_created', 'user_updated', 'user_archived')
+        AND payload->>'id' IN (SELECT id::text FROM target)
+    )
+    UPDATE users
+    SET
+        first_name = '',
+        last_name = '',
+        username = 'anonymized-' || $1,
+        email = 'anonymized-' || $1 || '@invalid',
+        password = '',
+        salt = '',
+        is_admin = false,
+        verified_on = NULL,
+        updated_on = NOW(),
+        archived_on = COALESCE(archived_on, NOW())
+    WHERE id = $1
+    RETURNING updated_on;
+`
+
+func (pg *postgres) AnonymizeUser(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("AnonymizeUser", time.Now(), &err, &t, id)
+	err = db.QueryRow(userAnonymizationQuery, id).Scan(&t)
+	return t, err
+}
+
+const userDataExportQuery = `
+    SELECT json_build_object(
+        'user', (
+            SELECT row_to_json(u) FROM (
+                SELECT
+                    id,
+                    first_name,
+                    last_name,
+                    username,
+                    email,
+                    is_admin,
+                    password_last_changed_on,
+                    created_on,
+                    updated_on,
+                    archived_on,
+                    verified_on
+                FROM users WHERE id = $1
+            ) u
+        ),
+        'roles', (
+            SELECT COALESCE(json_agg(r ORDER BY r.granted_on), '[]') FROM (
+                SELECT roles.name, user_roles.created_on AS granted_on, user_roles.archived_on AS revoked_on
+                FROM user_roles JOIN roles ON roles.id = user_roles.role_id
+                WHERE user_roles.user_id = $1
+            ) r
+        ),
+        'sessions', (
+            SELECT COALESCE(json_agg(s ORDER BY s.created_on), '[]') FROM (
+                SELECT user_agent, ip_address, last_seen_on, expires_on, created_on, archived_on
+                FROM user_sessions WHERE user_id = $1
+            ) s
+        ),
+        'login_attempts', (
+            SELECT COALESCE(json_agg(l ORDER BY l.created_on), '[]') FROM (
+                SELECT successful, ip_address, created_on
+                FROM login_attempts WHERE username = (SELECT username FROM users WHERE id = $1)
+            ) l
+        ),
+        'password_reset_tokens', (
+            SELECT COALESCE(json_agg(p ORDER BY p.created_on), '[]') FROM (
+                SELECT created_on, expires_on, password_reset_on, invalidated_on
+                FROM password_reset_tokens WHERE user_id = $1
+            ) p
+        ),
+        'email_verification_tokens', (
+            SELECT COALESCE(json_agg(e ORDER BY e.created_on), '[]') FROM (
+                SELECT email, created_on, expires_on, verified_on, invalidated_on
+                FROM email_verification_tokens WHERE user_id = $1
+            ) e
+        )
+    )
+    WHERE EXISTS(SELECT id FROM users WHERE id = $1)
+`
+
+func (pg *postgres) ExportUserData(db database.Querier, id uint64) (result json.RawMessage, err error) {
+	defer pg.observe("ExportUserData", time.Now(), &err, &result, id)
+	var doc []byte
+	err = db.QueryRow(userDataExportQuery, id).Scan(&doc)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(doc), nil
+}
+
 const userExistenceQuery = `SELECT EXISTS(SELECT id FROM users WHERE id = $1 and archived_on IS NULL);`
 
-func (pg *postgres) UserExists(db database.Querier, id uint64) (bool, error) {
+func (pg *postgres) UserExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("UserExists", time.Now(), &err, &result, id)
 	var exists string
 
-	err := db.QueryRow(userExistenceQuery, id).Scan(&exists)
+	err = db.QueryRow(userExistenceQuery, id).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -75,13 +356,12 @@ const userSelectionQuery = `
         last_name,
         username,
         email,
-        password,
-        salt,
         is_admin,
         password_last_changed_on,
         created_on,
         updated_on,
-        archived_on
+        archived_on,
+        verified_on
     FROM
         users
     WHERE
@@ -90,10 +370,11 @@ const userSelectionQuery = `
         id = $1
 `
 
-func (pg *postgres) GetUser(db database.Querier, id uint64) (*models.User, error) {
-	u := &models.User{}
+func (pg *postgres) GetUser(db database.Querier, id uint64) (result *models.UserProfile, err error) {
+	defer pg.observe("GetUser", time.Now(), &err, &result, id)
+	u := &models.UserProfile{}
 
-	err := db.QueryRow(userSelectionQuery, id).Scan(&u.ID, &u.FirstName, &u.LastName, &u.Username, &u.Email, &u.Password, &u.Salt, &u.IsAdmin, &u.PasswordLastChangedOn, &u.CreatedOn, &u.UpdatedOn, &u.ArchivedOn)
+	err = db.QueryRow(userSelectionQuery, id).Scan(&u.ID, &u.FirstName, &u.LastName, &u.Username, &u.Email, &u.IsAdmin, &u.PasswordLastChangedOn, &u.CreatedOn, &u.UpdatedOn, &u.ArchivedOn, &u.VerifiedOn)
 
 	return u, err
 }
@@ -107,13 +388,12 @@ func buildUserListRetrievalQuery(qf *models.QueryFilter) (string, []interface{})
 			"last_name",
 			"username",
 			"email",
-			"password",
-			"salt",
 			"is_admin",
 			"password_last_changed_on",
 			"created_on",
 			"updated_on",
 			"archived_on",
+			"verified_on",
 		).
 		From("users")
 
@@ -121,8 +401,9 @@ func buildUserListRetrievalQuery(qf *models.QueryFilter) (string, []interface{})
 	return query, args
 }
 
-func (pg *postgres) GetUserList(db database.Querier, qf *models.QueryFilter) ([]models.User, error) {
-	var list []models.User
+func (pg *postgres) GetUserList(db database.Querier, qf *models.QueryFilter) (result []models.UserProfile, err error) {
+	defer pg.observe("GetUserList", time.Now(), &err, &result, qf)
+	var list []models.UserProfile
 	query, args := buildUserListRetrievalQuery(qf)
 
 	rows, err := db.Query(query, args...)
@@ -131,20 +412,19 @@ func (pg *postgres) GetUserList(db database.Querier, qf *models.QueryFilter) ([]
 	}
 	defer rows.Close()
 	for rows.Next() {
-		var u models.User
+		var u models.UserProfile
 		err := rows.Scan(
 			&u.ID,
 			&u.FirstName,
 			&u.LastName,
 			&u.Username,
 			&u.Email,
-			&u.Password,
-			&u.Salt,
 			&u.IsAdmin,
 			&u.PasswordLastChangedOn,
 			&u.CreatedOn,
 			&u.UpdatedOn,
 			&u.ArchivedOn,
+			&u.VerifiedOn,
 		)
 		if err != nil {
 			return nil, err
@@ -168,61 +448,83 @@ func buildUserCountRetrievalQuery(qf *models.QueryFilter) (string, []interface{}
 	return query, args
 }
 
-func (pg *postgres) GetUserCount(db database.Querier, qf *models.QueryFilter) (uint64, error) {
+func (pg *postgres) GetUserCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetUserCount", time.Now(), &err, nil, qf)
 	var count uint64
 	query, args := buildUserCountRetrievalQuery(qf)
-	err := db.QueryRow(query, args...).Scan(&count)
+	err = db.QueryRow(query, args...).Scan(&count)
 	return count, err
 }
 
 const userCreationQuery = `
+    WITH created AS (
     INSERT INTO users
         (
-            first_name, last_name, username, email, password, salt, is_admin, password_last_changed_on
+            first_name, last_name, username, email, password, salt, is_admin, password_last_changed_on, verified_on
         )
     VALUES
         (
-            $1, $2, $3, $4, $5, $6, $7, $8
+            $1, $2, $3, $4, $5, $6, $7, $8, $9
         )
     RETURNING
-        id, created_on;
+        *
+    ), outboxed AS (
+        INSERT INTO outbox_events (event_type, payload)
+        SELECT 'user_created', to_jsonb(created) - 'password' - 'salt' FROM created
+    )
+    SELECT
+        id, created_on
+    FROM created;
 `
 
 func (pg *postgres) CreateUser(db database.Querier, nu *models.User) (createdID uint64, createdOn time.Time, err error) {
-	err = db.QueryRow(userCreationQuery, &nu.FirstName, &nu.LastName, &nu.Username, &nu.Email, &nu.Password, &nu.Salt, &nu.IsAdmin, &nu.PasswordLastChangedOn).Scan(&createdID, &createdOn)
+	defer pg.observe("CreateUser", time.Now(), &err, &createdID, nu)
+	err = db.QueryRow(userCreationQuery, &nu.FirstName, &nu.LastName, &nu.Username, &nu.Email, &nu.Password, &nu.Salt, &nu.IsAdmin, &nu.PasswordLastChangedOn, &nu.VerifiedOn).Scan(&createdID, &createdOn)
 	return createdID, createdOn, err
 }
 
 const userUpdateQuery = `
+    WITH updated AS (
     UPDATE users
     SET
         first_name = $1,
         last_name = $2,
         username = $3,
         email = $4,
-        password = $5,
-        salt = $6,
-        is_admin = $7,
-        password_last_changed_on = $8,
+        is_admin = $5,
+        verified_on = $6,
         updated_on = NOW()
-    WHERE id = $9
-    RETURNING updated_on;
+    WHERE id = $7
+    RETURNING *
+    ), outboxed AS (
+        INSERT INTO outbox_events (event_type, payload)
+        SELECT 'user_updated', to_jsonb(updated) - 'password' - 'salt' FROM updated
+    )
+    SELECT updated_on FROM updated;
 `
 
-func (pg *postgres) UpdateUser(db database.Querier, updated *models.User) (time.Time, error) {
+func (pg *postgres) UpdateUser(db database.Querier, updated *models.UserProfile) (result time.Time, err error) {
+	defer pg.observe("UpdateUser", time.Now(), &err, &result, updated)
 	var t time.Time
-	err := db.QueryRow(userUpdateQuery, &updated.FirstName, &updated.LastName, &updated.Username, &updated.Email, &updated.Password, &updated.Salt, &updated.IsAdmin, &updated.PasswordLastChangedOn, &updated.ID).Scan(&t)
+	err = db.QueryRow(userUpdateQuery, &updated.FirstName, &updated.LastName, &updated.Username, &updated.Email, &updated.IsAdmin, &updated.VerifiedOn, &updated.ID).Scan(&t)
 	return t, err
 }
 
 const userDeletionQuery = `
+    WITH archived AS (
     UPDATE users
     SET archived_on = NOW()
     WHERE id = $1
-    RETURNING archived_on
+    RETURNING *
+    ), outboxed AS (
+        INSERT INTO outbox_events (event_type, payload)
+        SELECT 'user_archived', to_jsonb(archived) - 'password' - 'salt' FROM archived
+    )
+    SELECT archived_on FROM archived
 `
 
 func (pg *postgres) DeleteUser(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteUser", time.Now(), &err, &t, id)
 	err = db.QueryRow(userDeletionQuery, id).Scan(&t)
 	return t, err
 }
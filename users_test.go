@@ -15,7 +15,7 @@ import (
 	"gopkg.in/DATA-DOG/go-sqlmock.v1"
 )
 
-func setUserReadQueryExpectationByUsername(t *testing.T, mock sqlmock.Sqlmock, username string, toReturn *models.User, err error) {
+func setUserReadQueryExpectationByUsername(t *testing.T, mock sqlmock.Sqlmock, username string, toReturn *models.UserProfile, err error) {
 	t.Helper()
 	query := formatQueryForSQLMock(userQueryByUsername)
 	exampleRows := sqlmock.NewRows([]string{
@@ -24,26 +24,24 @@ func setUserReadQueryExpectationByUsername(t *testing.T, mock sqlmock.Sqlmock, u
 		"last_name",
 		"username",
 		"email",
-		"password",
-		"salt",
 		"is_admin",
 		"password_last_changed_on",
 		"created_on",
 		"updated_on",
 		"archived_on",
+		"verified_on",
 	}).AddRow(
 		toReturn.ID,
 		toReturn.FirstName,
 		toReturn.LastName,
 		toReturn.Username,
 		toReturn.Email,
-		toReturn.Password,
-		toReturn.Salt,
 		toReturn.IsAdmin,
 		toReturn.PasswordLastChangedOn,
 		toReturn.CreatedOn,
 		toReturn.UpdatedOn,
 		toReturn.ArchivedOn,
+		toReturn.VerifiedOn,
 	)
 	mock.ExpectQuery(query).WithArgs(username).WillReturnRows(exampleRows).WillReturnError(err)
 }
@@ -56,7 +54,7 @@ func TestGetUserByUsername(t *testing.T) {
 	client := NewPostgres()
 
 	exampleUsername := "username"
-	expected := &models.User{Username: exampleUsername}
+	expected := &models.UserProfile{Username: exampleUsername}
 
 	t.Run("optimal behavior", func(t *testing.T) {
 		setUserReadQueryExpectationByUsername(t, mock, exampleUsername, expected, nil)
@@ -68,6 +66,102 @@ func TestGetUserByUsername(t *testing.T) {
 	})
 }
 
+func setUserCredentialsQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, username string, toReturn *models.UserCredentials, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(userCredentialsQueryByUsername)
+	exampleRows := sqlmock.NewRows([]string{"id", "username", "password", "salt", "password_last_changed_on"}).
+		AddRow(toReturn.ID, toReturn.Username, toReturn.Password, toReturn.Salt, toReturn.PasswordLastChangedOn)
+	mock.ExpectQuery(query).WithArgs(username).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestGetUserCredentials(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+
+	exampleUsername := "username"
+	expected := &models.UserCredentials{
+		ID:       1,
+		Username: exampleUsername,
+		Password: "hashed password",
+		Salt:     []byte("salt"),
+	}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setUserCredentialsQueryExpectation(t, mock, exampleUsername, expected, nil)
+		actual, err := client.GetUserCredentials(mockDB, exampleUsername)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected credentials did not match actual credentials")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with nonexistent user", func(t *testing.T) {
+		setUserCredentialsQueryExpectation(t, mock, exampleUsername, expected, sql.ErrNoRows)
+		_, err := client.GetUserCredentials(mockDB, exampleUsername)
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setUserCredentialsUpdateQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, password string, salt []byte, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(userCredentialsUpdateQuery)
+	exampleRows := sqlmock.NewRows([]string{"password_last_changed_on"}).AddRow(buildTestTime(t))
+	mock.ExpectQuery(query).WithArgs(password, salt, id).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestUpdateUserCredentials(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+
+	exampleID := uint64(1)
+	examplePassword := "new hashed password"
+	exampleSalt := []byte("new salt")
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setUserCredentialsUpdateQueryExpectation(t, mock, exampleID, examplePassword, exampleSalt, nil)
+		expected := buildTestTime(t)
+		actual, err := client.UpdateUserCredentials(mockDB, exampleID, examplePassword, exampleSalt)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected password change time did not match actual password change time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with nonexistent user", func(t *testing.T) {
+		setUserCredentialsUpdateQueryExpectation(t, mock, exampleID, examplePassword, exampleSalt, sql.ErrNoRows)
+		_, err := client.UpdateUserCredentials(mockDB, exampleID, examplePassword, exampleSalt)
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func TestUserUpdateQueryDoesNotWriteCredentials(t *testing.T) {
+	t.Parallel()
+
+	assert.NotRegexp(t, `\bpassword =`, userUpdateQuery, "general user updates should not write the password hash")
+	assert.NotRegexp(t, `\bsalt =`, userUpdateQuery, "general user updates should not write the salt")
+	assert.NotContains(t, userUpdateQuery, "user_sessions", "general user updates should not revoke sessions")
+}
+
+func TestUserReadQueriesDoNotSelectSecrets(t *testing.T) {
+	t.Parallel()
+
+	listQuery, _ := buildUserListRetrievalQuery(&models.QueryFilter{Limit: 25, Page: 1})
+	for _, query := range []string{userSelectionQuery, userQueryByUsername, userQueryByEmail, listQuery} {
+		assert.NotRegexp(t, `\bpassword\b`, query, "general user reads should not select the password hash")
+		assert.NotRegexp(t, `\bsalt\b`, query, "general user reads should not select the salt")
+	}
+}
+
 func setUserWithUsernameExistenceQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, username string, shouldExist bool, err error) {
 	t.Helper()
 	query := formatQueryForSQLMock(userWithUsernameExistenceQuery)
@@ -114,6 +208,289 @@ func TestUserWithUsernameExists(t *testing.T) {
 	})
 }
 
+func setUserReadQueryExpectationByEmail(t *testing.T, mock sqlmock.Sqlmock, email string, toReturn *models.UserProfile, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(userQueryByEmail)
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"first_name",
+		"last_name",
+		"username",
+		"email",
+		"is_admin",
+		"password_last_changed_on",
+		"created_on",
+		"updated_on",
+		"archived_on",
+		"verified_on",
+	}).AddRow(
+		toReturn.ID,
+		toReturn.FirstName,
+		toReturn.LastName,
+		toReturn.Username,
+		toReturn.Email,
+		toReturn.IsAdmin,
+		toReturn.PasswordLastChangedOn,
+		toReturn.CreatedOn,
+		toReturn.UpdatedOn,
+		toReturn.ArchivedOn,
+		toReturn.VerifiedOn,
+	)
+	mock.ExpectQuery(query).WithArgs(email).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestGetUserByEmail(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+
+	exampleEmail := "frank@example.com"
+	expected := &models.UserProfile{Email: exampleEmail}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setUserReadQueryExpectationByEmail(t, mock, exampleEmail, expected, nil)
+		actual, err := client.GetUserByEmail(mockDB, exampleEmail)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected user did not match actual user")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setUserWithEmailExistenceQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, email string, shouldExist bool, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(userWithEmailExistenceQuery)
+
+	mock.ExpectQuery(query).
+		WithArgs(email).
+		WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(strconv.FormatBool(shouldExist))).
+		WillReturnError(err)
+}
+
+func TestUserWithEmailExists(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleEmail := "frank@example.com"
+	client := NewPostgres()
+
+	t.Run("existing", func(t *testing.T) {
+		setUserWithEmailExistenceQueryExpectation(t, mock, exampleEmail, true, nil)
+		actual, err := client.UserWithEmailExists(mockDB, exampleEmail)
+
+		assert.NoError(t, err)
+		assert.True(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with no rows found", func(t *testing.T) {
+		setUserWithEmailExistenceQueryExpectation(t, mock, exampleEmail, true, sql.ErrNoRows)
+		actual, err := client.UserWithEmailExists(mockDB, exampleEmail)
+
+		assert.NoError(t, err)
+		assert.False(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with a database error", func(t *testing.T) {
+		setUserWithEmailExistenceQueryExpectation(t, mock, exampleEmail, true, errors.New("pineapple on pizza"))
+		actual, err := client.UserWithEmailExists(mockDB, exampleEmail)
+
+		assert.NotNil(t, err)
+		assert.False(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setUserPermissionCheckQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, userID uint64, permission string, allowed bool, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(userPermissionCheckQuery)
+
+	mock.ExpectQuery(query).
+		WithArgs(userID, permission).
+		WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(strconv.FormatBool(allowed))).
+		WillReturnError(err)
+}
+
+func TestUserHasPermission(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleUserID := uint64(1)
+	client := NewPostgres()
+
+	t.Run("allowed", func(t *testing.T) {
+		setUserPermissionCheckQueryExpectation(t, mock, exampleUserID, PermissionWriteInventory, true, nil)
+		actual, err := client.UserHasPermission(mockDB, exampleUserID, PermissionWriteInventory)
+
+		assert.NoError(t, err)
+		assert.True(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("not allowed", func(t *testing.T) {
+		setUserPermissionCheckQueryExpectation(t, mock, exampleUserID, PermissionManageWebhooks, false, nil)
+		actual, err := client.UserHasPermission(mockDB, exampleUserID, PermissionManageWebhooks)
+
+		assert.NoError(t, err)
+		assert.False(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with a database error", func(t *testing.T) {
+		setUserPermissionCheckQueryExpectation(t, mock, exampleUserID, PermissionWriteInventory, true, errors.New("pineapple on pizza"))
+		actual, err := client.UserHasPermission(mockDB, exampleUserID, PermissionWriteInventory)
+
+		assert.NotNil(t, err)
+		assert.False(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setUserWithPermissionsQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, toReturn *models.UserWithPermissions, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(userWithPermissionsQuery)
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"first_name",
+		"last_name",
+		"username",
+		"email",
+		"is_admin",
+		"password_last_changed_on",
+		"created_on",
+		"updated_on",
+		"archived_on",
+		"verified_on",
+		"permissions",
+	}).AddRow(
+		toReturn.ID,
+		toReturn.FirstName,
+		toReturn.LastName,
+		toReturn.Username,
+		toReturn.Email,
+		toReturn.IsAdmin,
+		toReturn.PasswordLastChangedOn,
+		toReturn.CreatedOn,
+		toReturn.UpdatedOn,
+		toReturn.ArchivedOn,
+		toReturn.VerifiedOn,
+		"{inventory:write,products:write}",
+	)
+	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestGetUserWithPermissions(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+
+	exampleID := uint64(1)
+	expected := &models.UserWithPermissions{}
+	expected.ID = exampleID
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setUserWithPermissionsQueryExpectation(t, mock, exampleID, expected, nil)
+		actual, err := client.GetUserWithPermissions(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, exampleID, actual.ID, "expected user did not match actual user")
+		assert.Equal(t, []string{PermissionWriteInventory, PermissionWriteProducts}, actual.Permissions, "expected permissions did not match actual permissions")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setUserAnonymizationQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(userAnonymizationQuery)
+	exampleRows := sqlmock.NewRows([]string{"updated_on"}).AddRow(buildTestTime(t))
+	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestAnonymizeUser(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setUserAnonymizationQueryExpectation(t, mock, exampleID, nil)
+		expected := buildTestTime(t)
+		actual, err := client.AnonymizeUser(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected anonymization time did not match actual anonymization time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		setUserAnonymizationQueryExpectation(t, mock, exampleID, nil)
+		expected := buildTestTime(t)
+		tx, err := mockDB.Begin()
+		assert.NoError(t, err, "no error should be returned setting up a transaction in the mock DB")
+		actual, err := client.AnonymizeUser(tx, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected anonymization time did not match actual anonymization time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func TestUserAnonymizationRedactsOutboxEvents(t *testing.T) {
+	t.Parallel()
+
+	query := userAnonymizationQuery
+	assert.Contains(t, query, "UPDATE outbox_events", "anonymizing a user should redact their queued events")
+	assert.Contains(t, query, "payload = jsonb_build_object('id', payload->'id')", "redacted events should only keep the user ID")
+	for _, eventType := range []string{"user_created", "user_updated", "user_archived"} {
+		assert.Contains(t, query, "'"+eventType+"'", "%s events should be redacted", eventType)
+	}
+}
+
+func setUserDataExportQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, doc string, err error) {
+	t.Helper()
+	query := formatQueryForSQLMock(userDataExportQuery)
+	exampleRows := sqlmock.NewRows([]string{"json_build_object"}).AddRow([]byte(doc))
+	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
+}
+
+func TestExportUserData(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	exampleID := uint64(1)
+	client := NewPostgres()
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		expected := `{"user": {"id": 1}, "roles": [], "sessions": [], "login_attempts": [], "password_reset_tokens": [], "email_verification_tokens": []}`
+		setUserDataExportQueryExpectation(t, mock, exampleID, expected, nil)
+		actual, err := client.ExportUserData(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, expected, string(actual), "expected export did not match actual export")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with nonexistent user", func(t *testing.T) {
+		setUserDataExportQueryExpectation(t, mock, exampleID, "", sql.ErrNoRows)
+		actual, err := client.ExportUserData(mockDB, exampleID)
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
 func setUserExistenceQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, shouldExist bool, err error) {
 	t.Helper()
 	query := formatQueryForSQLMock(userExistenceQuery)
@@ -160,7 +537,7 @@ func TestUserExists(t *testing.T) {
 	})
 }
 
-func setUserReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, toReturn *models.User, err error) {
+func setUserReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, toReturn *models.UserProfile, err error) {
 	t.Helper()
 	query := formatQueryForSQLMock(userSelectionQuery)
 
@@ -170,26 +547,24 @@ func setUserReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64,
 		"last_name",
 		"username",
 		"email",
-		"password",
-		"salt",
 		"is_admin",
 		"password_last_changed_on",
 		"created_on",
 		"updated_on",
 		"archived_on",
+		"verified_on",
 	}).AddRow(
 		toReturn.ID,
 		toReturn.FirstName,
 		toReturn.LastName,
 		toReturn.Username,
 		toReturn.Email,
-		toReturn.Password,
-		toReturn.Salt,
 		toReturn.IsAdmin,
 		toReturn.PasswordLastChangedOn,
 		toReturn.CreatedOn,
 		toReturn.UpdatedOn,
 		toReturn.ArchivedOn,
+		toReturn.VerifiedOn,
 	)
 	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
 }
@@ -200,7 +575,7 @@ func TestGetUser(t *testing.T) {
 	assert.NoError(t, err)
 	defer mockDB.Close()
 	exampleID := uint64(1)
-	expected := &models.User{ID: exampleID}
+	expected := &models.UserProfile{ID: exampleID}
 	client := NewPostgres()
 
 	t.Run("optimal behavior", func(t *testing.T) {
@@ -213,59 +588,55 @@ func TestGetUser(t *testing.T) {
 	})
 }
 
-func setUserListReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, qf *models.QueryFilter, example *models.User, rowErr error, err error) {
+func setUserListReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, qf *models.QueryFilter, example *models.UserProfile, rowErr error, err error) {
 	exampleRows := sqlmock.NewRows([]string{
 		"id",
 		"first_name",
 		"last_name",
 		"username",
 		"email",
-		"password",
-		"salt",
 		"is_admin",
 		"password_last_changed_on",
 		"created_on",
 		"updated_on",
 		"archived_on",
+		"verified_on",
 	}).AddRow(
 		example.ID,
 		example.FirstName,
 		example.LastName,
 		example.Username,
 		example.Email,
-		example.Password,
-		example.Salt,
 		example.IsAdmin,
 		example.PasswordLastChangedOn,
 		example.CreatedOn,
 		example.UpdatedOn,
 		example.ArchivedOn,
+		example.VerifiedOn,
 	).AddRow(
 		example.ID,
 		example.FirstName,
 		example.LastName,
 		example.Username,
 		example.Email,
-		example.Password,
-		example.Salt,
 		example.IsAdmin,
 		example.PasswordLastChangedOn,
 		example.CreatedOn,
 		example.UpdatedOn,
 		example.ArchivedOn,
+		example.VerifiedOn,
 	).AddRow(
 		example.ID,
 		example.FirstName,
 		example.LastName,
 		example.Username,
 		example.Email,
-		example.Password,
-		example.Salt,
 		example.IsAdmin,
 		example.PasswordLastChangedOn,
 		example.CreatedOn,
 		example.UpdatedOn,
 		example.ArchivedOn,
+		example.VerifiedOn,
 	).RowError(1, rowErr)
 
 	query, _ := buildUserListRetrievalQuery(qf)
@@ -281,7 +652,7 @@ func TestGetUserList(t *testing.T) {
 	assert.NoError(t, err)
 	defer mockDB.Close()
 	exampleID := uint64(1)
-	example := &models.User{ID: exampleID}
+	example := &models.UserProfile{ID: exampleID}
 	client := NewPostgres()
 	exampleQF := &models.QueryFilter{
 		Limit: 25,
@@ -393,6 +764,7 @@ func setUserCreationQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toCreat
 			toCreate.Salt,
 			toCreate.IsAdmin,
 			toCreate.PasswordLastChangedOn,
+			toCreate.VerifiedOn,
 		).
 		WillReturnRows(exampleRows).
 		WillReturnError(err)
@@ -421,7 +793,7 @@ func TestCreateUser(t *testing.T) {
 	})
 }
 
-func setUserUpdateQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toUpdate *models.User, err error) {
+func setUserUpdateQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toUpdate *models.UserProfile, err error) {
 	t.Helper()
 	query := formatQueryForSQLMock(userUpdateQuery)
 	exampleRows := sqlmock.NewRows([]string{"updated_on"}).AddRow(buildTestTime(t))
@@ -431,10 +803,8 @@ func setUserUpdateQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toUpdate
 			toUpdate.LastName,
 			toUpdate.Username,
 			toUpdate.Email,
-			toUpdate.Password,
-			toUpdate.Salt,
 			toUpdate.IsAdmin,
-			toUpdate.PasswordLastChangedOn,
+			toUpdate.VerifiedOn,
 			toUpdate.ID,
 		).
 		WillReturnRows(exampleRows).
@@ -446,7 +816,7 @@ func TestUpdateUserByID(t *testing.T) {
 	mockDB, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer mockDB.Close()
-	exampleInput := &models.User{ID: uint64(1)}
+	exampleInput := &models.UserProfile{ID: uint64(1)}
 	client := NewPostgres()
 
 	t.Run("optimal behavior", func(t *testing.T) {
@@ -498,3 +868,19 @@ func TestDeleteUserByID(t *testing.T) {
 		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
 	})
 }
+
+func TestUserWritesAppendOutboxEvents(t *testing.T) {
+	t.Parallel()
+
+	for query, eventType := range map[string]string{
+		userCreationQuery: "user_created",
+		userUpdateQuery:   "user_updated",
+		userDeletionQuery: "user_archived",
+	} {
+		assert.Contains(t, query, "INSERT INTO outbox_events", "user writes should append to the outbox in the same statement")
+		assert.Contains(t, query, "'"+eventType+"'", "user write should record a %s event", eventType)
+	}
+	for _, query := range []string{userCreationQuery, userUpdateQuery, userDeletionQuery} {
+		assert.Contains(t, query, "- 'password' - 'salt'", "user event payloads should not include credentials")
+	}
+}
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/dairycart/dairycart/storage/database"
+	"github.com/dairycart/dairymodels/v1"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const webhookEventTypeExistenceQuery = `SELECT EXISTS(SELECT id FROM webhook_event_types WHERE id = $1 and archived_on IS NULL);`
+
+func (pg *postgres) WebhookEventTypeExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("WebhookEventTypeExists", time.Now(), &err, &result, id)
+	var exists string
+
+	err = db.QueryRow(webhookEventTypeExistenceQuery, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return exists == "true", err
+}
+
+const webhookEventTypeSelectionQuery = `
+    SELECT
+        id,
+        name,
+        description,
+        created_on,
+        updated_on,
+        archived_on
+    FROM
+        webhook_event_types
+    WHERE
+        archived_on is null
+    AND
+        id = $1
+`
+
+func (pg *postgres) GetWebhookEventType(db database.Querier, id uint64) (result *models.WebhookEventType, err error) {
+	defer pg.observe("GetWebhookEventType", time.Now(), &err, &result, id)
+	w := &models.WebhookEventType{}
+
+	err = db.QueryRow(webhookEventTypeSelectionQuery, id).Scan(&w.ID, &w.Name, &w.Description, &w.CreatedOn, &w.UpdatedOn, &w.ArchivedOn)
+
+	return w, err
+}
+
+func buildWebhookEventTypeListRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	sqlBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	queryBuilder := sqlBuilder.
+		Select(
+			"id",
+			"name",
+			"description",
+			"created_on",
+			"updated_on",
+			"archived_on",
+		).
+		From("webhook_event_types")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, true).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetWebhookEventTypeList(db database.Querier, qf *models.QueryFilter) (result []models.WebhookEventType, err error) {
+	defer pg.observe("GetWebhookEventTypeList", time.Now(), &err, &result, qf)
+	var list []models.WebhookEventType
+	query, args := buildWebhookEventTypeListRetrievalQuery(qf)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var w models.WebhookEventType
+		err := rows.Scan(
+			&w.ID,
+			&w.Name,
+			&w.Description,
+			&w.CreatedOn,
+			&w.UpdatedOn,
+			&w.ArchivedOn,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, w)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+func buildWebhookEventTypeCountRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("count(id)").
+		From("webhook_event_types")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, false).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetWebhookEventTypeCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetWebhookEventTypeCount", time.Now(), &err, nil, qf)
+	var count uint64
+	query, args := buildWebhookEventTypeCountRetrievalQuery(qf)
+	err = db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+const webhookEventTypeCreationQuery = `
+    INSERT INTO webhook_event_types
+        (
+            name, description
+        )
+    VALUES
+        (
+            $1, $2
+        )
+    RETURNING
+        id, created_on;
+`
+
+func (pg *postgres) CreateWebhookEventType(db database.Querier, nu *models.WebhookEventType) (createdID uint64, createdOn time.Time, err error) {
+	defer pg.observe("CreateWebhookEventType", time.Now(), &err, &createdID, nu)
+	err = db.QueryRow(webhookEventTypeCreationQuery, &nu.Name, &nu.Description).Scan(&createdID, &createdOn)
+	return createdID, createdOn, err
+}
+
+const webhookEventTypeUpdateQuery = `
+    UPDATE webhook_event_types
+    SET
+        name = $1,
+        description = $2,
+        updated_on = NOW()
+    WHERE id = $3
+    RETURNING updated_on;
+`
+
+func (pg *postgres) UpdateWebhookEventType(db database.Querier, updated *models.WebhookEventType) (result time.Time, err error) {
+	defer pg.observe("UpdateWebhookEventType", time.Now(), &err, &result, updated)
+	var t time.Time
+	err = db.QueryRow(webhookEventTypeUpdateQuery, &updated.Name, &updated.Description, &updated.ID).Scan(&t)
+	return t, err
+}
+
+const webhookEventTypeDeletionQuery = `
+    UPDATE webhook_event_types
+    SET archived_on = NOW()
+    WHERE id = $1
+    RETURNING archived_on
+`
+
+func (pg *postgres) DeleteWebhookEventType(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteWebhookEventType", time.Now(), &err, &t, id)
+	err = db.QueryRow(webhookEventTypeDeletionQuery, id).Scan(&t)
+	return t, err
+}
@@ -10,12 +10,162 @@ import (
 	"github.com/Masterminds/squirrel"
 )
 
+const webhookExecutionLogRecordQuery = `
+    WITH recorded AS (
+        INSERT INTO webhook_execution_logs
+            (
+                webhook_id, event_type, attempt, status_code, succeeded, request_body, response_body, duration_ms, error_message
+            )
+        VALUES
+            (
+                $1, $2, $3, $4, $5, $6, $7, $8, $9
+            )
+        RETURNING
+            id, webhook_id, succeeded, executed_on
+    ), tracked AS (
+        UPDATE webhooks
+        SET
+            consecutive_failures = CASE WHEN recorded.succeeded THEN 0 ELSE webhooks.consecutive_failures + 1 END,
+            disabled_on = CASE
+                WHEN NOT recorded.succeeded AND webhooks.disabled_on IS NULL AND webhooks.consecutive_failures + 1 >= $10
+                THEN NOW()
+                ELSE webhooks.disabled_on
+            END,
+            disabled_reason = CASE
+                WHEN NOT recorded.succeeded AND webhooks.disabled_on IS NULL AND webhooks.consecutive_failures + 1 >= $10
+                THEN 'disabled after ' || (webhooks.consecutive_failures + 1) || ' consecutive failed deliveries'
+                ELSE webhooks.disabled_reason
+            END
+        FROM recorded
+        WHERE webhooks.id = recorded.webhook_id
+    )
+    SELECT
+        id, executed_on
+    FROM recorded;
+`
+
+func (pg *postgres) RecordWebhookExecutionLog(db database.Querier, nu *models.WebhookExecutionLog) (newID uint64, executedOn time.Time, err error) {
+	defer pg.observe("RecordWebhookExecutionLog", time.Now(), &err, &newID, nu)
+	requestBody := prepareLoggedBody(nu.RequestBody, pg.webhookLogs.MaxBodyBytes)
+	responseBody := prepareLoggedBody(nu.ResponseBody, pg.webhookLogs.MaxBodyBytes)
+	err = db.QueryRow(webhookExecutionLogRecordQuery, nu.WebhookID, nu.EventType, nu.Attempt, nu.StatusCode, nu.Succeeded, requestBody, responseBody, nu.DurationMs, nu.ErrorMessage, pg.webhookFailures.ConsecutiveFailureLimit).Scan(&newID, &executedOn)
+	return newID, executedOn, err
+}
+
+const webhookExecutionLogRecentQueryByWebhookID = `
+    SELECT
+        id,
+        webhook_id,
+        status_code,
+        succeeded,
+        executed_on,
+        event_type,
+        attempt,
+        request_body,
+        response_body,
+        duration_ms,
+        error_message
+    FROM
+        webhook_execution_logs
+    WHERE
+        webhook_id = $1
+    ORDER BY
+        executed_on DESC, id DESC
+    LIMIT $2
+`
+
+func (pg *postgres) GetRecentWebhookExecutionLogsForWebhook(db database.Querier, webhookID uint64, limit uint64) (result []models.WebhookExecutionLog, err error) {
+	defer pg.observe("GetRecentWebhookExecutionLogsForWebhook", time.Now(), &err, &result, webhookID, limit)
+	var list []models.WebhookExecutionLog
+
+	rows, err := db.Query(webhookExecutionLogRecentQueryByWebhookID, webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var w models.WebhookExecutionLog
+		err := rows.Scan(
+			&w.ID,
+			&w.WebhookID,
+			&w.StatusCode,
+			&w.Succeeded,
+			&w.ExecutedOn,
+			&w.EventType,
+			&w.Attempt,
+			&w.RequestBody,
+			&w.ResponseBody,
+			&w.DurationMs,
+			&w.ErrorMessage,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, w)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+const webhookExecutionLogStatsQuery = `
+    SELECT
+        webhook_id,
+        count(*),
+        count(*) FILTER (WHERE succeeded),
+        COALESCE(avg(duration_ms), 0)::float8,
+        COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY duration_ms), 0)::float8,
+        COALESCE(max(duration_ms), 0)
+    FROM
+        webhook_execution_logs
+    WHERE
+        executed_on >= $1
+    AND
+        executed_on < $2
+    GROUP BY
+        webhook_id
+    ORDER BY
+        webhook_id
+`
+
+func (pg *postgres) GetWebhookExecutionLogStats(db database.Querier, from time.Time, to time.Time) (result []models.WebhookExecutionStats, err error) {
+	defer pg.observe("GetWebhookExecutionLogStats", time.Now(), &err, &result, from, to)
+	var list []models.WebhookExecutionStats
+
+	rows, err := db.Query(webhookExecutionLogStatsQuery, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var s models.WebhookExecutionStats
+		err := rows.Scan(&s.WebhookID, &s.Executions, &s.Successes, &s.AverageDurationMs, &s.P95DurationMs, &s.MaxDurationMs)
+		if err != nil {
+			return nil, err
+		}
+		if s.Executions > 0 {
+			s.SuccessRate = float64(s.Successes) / float64(s.Executions)
+		}
+		list = append(list, s)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
 const webhookExecutionLogExistenceQuery = `SELECT EXISTS(SELECT id FROM webhook_execution_logs WHERE id = $1 and archived_on IS NULL);`
 
-func (pg *postgres) WebhookExecutionLogExists(db database.Querier, id uint64) (bool, error) {
+func (pg *postgres) WebhookExecutionLogExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("WebhookExecutionLogExists", time.Now(), &err, &result, id)
 	var exists string
 
-	err := db.QueryRow(webhookExecutionLogExistenceQuery, id).Scan(&exists)
+	err = db.QueryRow(webhookExecutionLogExistenceQuery, id).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -31,7 +181,13 @@ const webhookExecutionLogSelectionQuery = `
         webhook_id,
         status_code,
         succeeded,
-        executed_on
+        executed_on,
+        event_type,
+        attempt,
+        request_body,
+        response_body,
+        duration_ms,
+        error_message
     FROM
         webhook_execution_logs
     WHERE
@@ -40,10 +196,11 @@ const webhookExecutionLogSelectionQuery = `
         id = $1
 `
 
-func (pg *postgres) GetWebhookExecutionLog(db database.Querier, id uint64) (*models.WebhookExecutionLog, error) {
+func (pg *postgres) GetWebhookExecutionLog(db database.Querier, id uint64) (result *models.WebhookExecutionLog, err error) {
+	defer pg.observe("GetWebhookExecutionLog", time.Now(), &err, &result, id)
 	w := &models.WebhookExecutionLog{}
 
-	err := db.QueryRow(webhookExecutionLogSelectionQuery, id).Scan(&w.ID, &w.WebhookID, &w.StatusCode, &w.Succeeded, &w.ExecutedOn)
+	err = db.QueryRow(webhookExecutionLogSelectionQuery, id).Scan(&w.ID, &w.WebhookID, &w.StatusCode, &w.Succeeded, &w.ExecutedOn, &w.EventType, &w.Attempt, &w.RequestBody, &w.ResponseBody, &w.DurationMs, &w.ErrorMessage)
 
 	return w, err
 }
@@ -57,6 +214,12 @@ func buildWebhookExecutionLogListRetrievalQuery(qf *models.QueryFilter) (string,
 			"status_code",
 			"succeeded",
 			"executed_on",
+			"event_type",
+			"attempt",
+			"request_body",
+			"response_body",
+			"duration_ms",
+			"error_message",
 		).
 		From("webhook_execution_logs")
 
@@ -64,7 +227,8 @@ func buildWebhookExecutionLogListRetrievalQuery(qf *models.QueryFilter) (string,
 	return query, args
 }
 
-func (pg *postgres) GetWebhookExecutionLogList(db database.Querier, qf *models.QueryFilter) ([]models.WebhookExecutionLog, error) {
+func (pg *postgres) GetWebhookExecutionLogList(db database.Querier, qf *models.QueryFilter) (result []models.WebhookExecutionLog, err error) {
+	defer pg.observe("GetWebhookExecutionLogList", time.Now(), &err, &result, qf)
 	var list []models.WebhookExecutionLog
 	query, args := buildWebhookExecutionLogListRetrievalQuery(qf)
 
@@ -81,6 +245,12 @@ func (pg *postgres) GetWebhookExecutionLogList(db database.Querier, qf *models.Q
 			&w.StatusCode,
 			&w.Succeeded,
 			&w.ExecutedOn,
+			&w.EventType,
+			&w.Attempt,
+			&w.RequestBody,
+			&w.ResponseBody,
+			&w.DurationMs,
+			&w.ErrorMessage,
 		)
 		if err != nil {
 			return nil, err
@@ -104,28 +274,20 @@ func buildWebhookExecutionLogCountRetrievalQuery(qf *models.QueryFilter) (string
 	return query, args
 }
 
-func (pg *postgres) GetWebhookExecutionLogCount(db database.Querier, qf *models.QueryFilter) (uint64, error) {
+func (pg *postgres) GetWebhookExecutionLogCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetWebhookExecutionLogCount", time.Now(), &err, nil, qf)
 	var count uint64
 	query, args := buildWebhookExecutionLogCountRetrievalQuery(qf)
-	err := db.QueryRow(query, args...).Scan(&count)
+	err = db.QueryRow(query, args...).Scan(&count)
 	return count, err
 }
 
-const webhookExecutionLogCreationQuery = `
-    INSERT INTO webhook_execution_logs
-        (
-            webhook_id, status_code, succeeded, executed_on
-        )
-    VALUES
-        (
-            $1, $2, $3, $4
-        )
-    RETURNING
-        id, created_on;
-`
-
+// CreateWebhookExecutionLog records an execution log the same way RecordWebhookExecutionLog
+// does, so generic callers cannot bypass body truncation or the webhook's
+// consecutive failure tracking.
 func (pg *postgres) CreateWebhookExecutionLog(db database.Querier, nu *models.WebhookExecutionLog) (createdID uint64, createdOn time.Time, err error) {
-	err = db.QueryRow(webhookExecutionLogCreationQuery, &nu.WebhookID, &nu.StatusCode, &nu.Succeeded, &nu.ExecutedOn).Scan(&createdID, &createdOn)
+	defer pg.observe("CreateWebhookExecutionLog", time.Now(), &err, &createdID, nu)
+	createdID, createdOn, err = pg.RecordWebhookExecutionLog(db, nu)
 	return createdID, createdOn, err
 }
 
@@ -136,14 +298,21 @@ const webhookExecutionLogUpdateQuery = `
         status_code = $2,
         succeeded = $3,
         executed_on = $4,
+        event_type = $5,
+        attempt = $6,
+        request_body = $7,
+        response_body = $8,
+        duration_ms = $9,
+        error_message = $10,
         updated_on = NOW()
-    WHERE id = $5
+    WHERE id = $11
     RETURNING updated_on;
 `
 
-func (pg *postgres) UpdateWebhookExecutionLog(db database.Querier, updated *models.WebhookExecutionLog) (time.Time, error) {
+func (pg *postgres) UpdateWebhookExecutionLog(db database.Querier, updated *models.WebhookExecutionLog) (result time.Time, err error) {
+	defer pg.observe("UpdateWebhookExecutionLog", time.Now(), &err, &result, updated)
 	var t time.Time
-	err := db.QueryRow(webhookExecutionLogUpdateQuery, &updated.WebhookID, &updated.StatusCode, &updated.Succeeded, &updated.ExecutedOn, &updated.ID).Scan(&t)
+	err = db.QueryRow(webhookExecutionLogUpdateQuery, &updated.WebhookID, &updated.StatusCode, &updated.Succeeded, &updated.ExecutedOn, &updated.EventType, &updated.Attempt, &updated.RequestBody, &updated.ResponseBody, &updated.DurationMs, &updated.ErrorMessage, &updated.ID).Scan(&t)
 	return t, err
 }
 
@@ -155,6 +324,7 @@ const webhookExecutionLogDeletionQuery = `
 `
 
 func (pg *postgres) DeleteWebhookExecutionLog(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteWebhookExecutionLog", time.Now(), &err, &t, id)
 	err = db.QueryRow(webhookExecutionLogDeletionQuery, id).Scan(&t)
 	return t, err
 }
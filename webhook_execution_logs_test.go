@@ -6,6 +6,7 @@ import (
 	"errors"
 	"strconv"
 	"testing"
+	"time"
 
 	// internal dependencies
 	"github.com/dairycart/dairymodels/v1"
@@ -15,6 +16,192 @@ import (
 	"gopkg.in/DATA-DOG/go-sqlmock.v1"
 )
 
+func TestRecordWebhookExecutionLog(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+	client.SetWebhookLogConfig(WebhookLogConfig{MaxBodyBytes: 4})
+	client.SetWebhookFailureConfig(WebhookFailureConfig{ConsecutiveFailureLimit: 3})
+	query := formatQueryForSQLMock(webhookExecutionLogRecordQuery)
+	example := &models.WebhookExecutionLog{
+		WebhookID:    1,
+		EventType:    "product_created",
+		Attempt:      2,
+		StatusCode:   500,
+		RequestBody:  `{"id": 1}`,
+		ResponseBody: "internal server error",
+		DurationMs:   120,
+		ErrorMessage: "unexpected status code",
+	}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		expectedID := uint64(1)
+		expectedExecutedOn := buildTestTime(t)
+		mock.ExpectQuery(query).
+			WithArgs(example.WebhookID, example.EventType, example.Attempt, example.StatusCode, example.Succeeded, `{"id`, "inte", example.DurationMs, example.ErrorMessage, uint64(3)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "executed_on"}).AddRow(expectedID, expectedExecutedOn))
+		actualID, actualExecutedOn, err := client.RecordWebhookExecutionLog(mockDB, example)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedID, actualID, "expected and actual IDs don't match")
+		assert.Equal(t, expectedExecutedOn, actualExecutedOn, "expected execution time did not match actual execution time")
+		assert.Equal(t, "internal server error", example.ResponseBody, "recording a log should not modify the provided log")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with a database error", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WillReturnError(errors.New("pineapple on pizza"))
+		_, _, err := client.RecordWebhookExecutionLog(mockDB, example)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func setRecentWebhookExecutionLogsForWebhookQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, webhookID uint64, limit uint64, example *models.WebhookExecutionLog, rowErr error, err error) {
+	exampleRows := sqlmock.NewRows([]string{
+		"id",
+		"webhook_id",
+		"status_code",
+		"succeeded",
+		"executed_on",
+		"event_type",
+		"attempt",
+		"request_body",
+		"response_body",
+		"duration_ms",
+		"error_message",
+	}).AddRow(
+		example.ID,
+		example.WebhookID,
+		example.StatusCode,
+		example.Succeeded,
+		example.ExecutedOn,
+		example.EventType,
+		example.Attempt,
+		example.RequestBody,
+		example.ResponseBody,
+		example.DurationMs,
+		example.ErrorMessage,
+	).AddRow(
+		example.ID,
+		example.WebhookID,
+		example.StatusCode,
+		example.Succeeded,
+		example.ExecutedOn,
+		example.EventType,
+		example.Attempt,
+		example.RequestBody,
+		example.ResponseBody,
+		example.DurationMs,
+		example.ErrorMessage,
+	).RowError(1, rowErr)
+
+	mock.ExpectQuery(formatQueryForSQLMock(webhookExecutionLogRecentQueryByWebhookID)).
+		WithArgs(webhookID, limit).
+		WillReturnRows(exampleRows).
+		WillReturnError(err)
+}
+
+func TestGetRecentWebhookExecutionLogsForWebhook(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+
+	exampleWebhookID := uint64(1)
+	exampleLimit := uint64(20)
+	example := &models.WebhookExecutionLog{WebhookID: exampleWebhookID}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		setRecentWebhookExecutionLogsForWebhookQueryExpectation(t, mock, exampleWebhookID, exampleLimit, example, nil, nil)
+		actual, err := client.GetRecentWebhookExecutionLogsForWebhook(mockDB, exampleWebhookID, exampleLimit)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, actual, "list retrieval method should not return an empty slice")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error executing query", func(t *testing.T) {
+		setRecentWebhookExecutionLogsForWebhookQueryExpectation(t, mock, exampleWebhookID, exampleLimit, example, nil, errors.New("pineapple on pizza"))
+		actual, err := client.GetRecentWebhookExecutionLogsForWebhook(mockDB, exampleWebhookID, exampleLimit)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with with row errors", func(t *testing.T) {
+		setRecentWebhookExecutionLogsForWebhookQueryExpectation(t, mock, exampleWebhookID, exampleLimit, example, errors.New("pineapple on pizza"), nil)
+		actual, err := client.GetRecentWebhookExecutionLogsForWebhook(mockDB, exampleWebhookID, exampleLimit)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func TestGetWebhookExecutionLogStats(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+	query := formatQueryForSQLMock(webhookExecutionLogStatsQuery)
+
+	exampleTo := buildTestTime(t)
+	exampleFrom := exampleTo.Add(-24 * time.Hour)
+	columns := []string{"webhook_id", "count", "count", "avg", "percentile_cont", "max"}
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(exampleFrom, exampleTo).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 4, 3, 110.5, 240.0, 300))
+		expected := []models.WebhookExecutionStats{
+			{
+				WebhookID:         1,
+				Executions:        4,
+				Successes:         3,
+				SuccessRate:       0.75,
+				AverageDurationMs: 110.5,
+				P95DurationMs:     240,
+				MaxDurationMs:     300,
+			},
+		}
+		actual, err := client.GetWebhookExecutionLogStats(mockDB, exampleFrom, exampleTo)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected stats did not match actual stats")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error executing query", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(exampleFrom, exampleTo).
+			WillReturnError(errors.New("pineapple on pizza"))
+		actual, err := client.GetWebhookExecutionLogStats(mockDB, exampleFrom, exampleTo)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error scanning values", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(exampleFrom, exampleTo).
+			WillReturnRows(sqlmock.NewRows([]string{"things"}).AddRow("stuff"))
+		actual, err := client.GetWebhookExecutionLogStats(mockDB, exampleFrom, exampleTo)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
 func setWebhookExecutionLogExistenceQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, shouldExist bool, err error) {
 	t.Helper()
 	query := formatQueryForSQLMock(webhookExecutionLogExistenceQuery)
@@ -71,12 +258,24 @@ func setWebhookExecutionLogReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmo
 		"status_code",
 		"succeeded",
 		"executed_on",
+		"event_type",
+		"attempt",
+		"request_body",
+		"response_body",
+		"duration_ms",
+		"error_message",
 	}).AddRow(
 		toReturn.ID,
 		toReturn.WebhookID,
 		toReturn.StatusCode,
 		toReturn.Succeeded,
 		toReturn.ExecutedOn,
+		toReturn.EventType,
+		toReturn.Attempt,
+		toReturn.RequestBody,
+		toReturn.ResponseBody,
+		toReturn.DurationMs,
+		toReturn.ErrorMessage,
 	)
 	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
 }
@@ -107,24 +306,48 @@ func setWebhookExecutionLogListReadQueryExpectation(t *testing.T, mock sqlmock.S
 		"status_code",
 		"succeeded",
 		"executed_on",
+		"event_type",
+		"attempt",
+		"request_body",
+		"response_body",
+		"duration_ms",
+		"error_message",
 	}).AddRow(
 		example.ID,
 		example.WebhookID,
 		example.StatusCode,
 		example.Succeeded,
 		example.ExecutedOn,
+		example.EventType,
+		example.Attempt,
+		example.RequestBody,
+		example.ResponseBody,
+		example.DurationMs,
+		example.ErrorMessage,
 	).AddRow(
 		example.ID,
 		example.WebhookID,
 		example.StatusCode,
 		example.Succeeded,
 		example.ExecutedOn,
+		example.EventType,
+		example.Attempt,
+		example.RequestBody,
+		example.ResponseBody,
+		example.DurationMs,
+		example.ErrorMessage,
 	).AddRow(
 		example.ID,
 		example.WebhookID,
 		example.StatusCode,
 		example.Succeeded,
 		example.ExecutedOn,
+		example.EventType,
+		example.Attempt,
+		example.RequestBody,
+		example.ResponseBody,
+		example.DurationMs,
+		example.ErrorMessage,
 	).RowError(1, rowErr)
 
 	query, _ := buildWebhookExecutionLogListRetrievalQuery(qf)
@@ -237,41 +460,45 @@ func TestGetWebhookExecutionLogCount(t *testing.T) {
 	})
 }
 
-func setWebhookExecutionLogCreationQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toCreate *models.WebhookExecutionLog, err error) {
-	t.Helper()
-	query := formatQueryForSQLMock(webhookExecutionLogCreationQuery)
-	tt := buildTestTime(t)
-	exampleRows := sqlmock.NewRows([]string{"id", "created_on"}).AddRow(uint64(1), tt)
-	mock.ExpectQuery(query).
-		WithArgs(
-			toCreate.WebhookID,
-			toCreate.StatusCode,
-			toCreate.Succeeded,
-			toCreate.ExecutedOn,
-		).
-		WillReturnRows(exampleRows).
-		WillReturnError(err)
-}
-
 func TestCreateWebhookExecutionLog(t *testing.T) {
 	t.Parallel()
 	mockDB, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer mockDB.Close()
-	expectedID := uint64(1)
-	exampleInput := &models.WebhookExecutionLog{ID: expectedID}
 	client := NewPostgres()
+	client.SetWebhookLogConfig(WebhookLogConfig{MaxBodyBytes: 4})
+	example := &models.WebhookExecutionLog{
+		WebhookID:    1,
+		EventType:    "product_created",
+		RequestBody:  `{"id": 1}`,
+		ResponseBody: "internal server error",
+	}
 
-	t.Run("optimal behavior", func(t *testing.T) {
-		setWebhookExecutionLogCreationQueryExpectation(t, mock, exampleInput, nil)
-		expectedCreatedOn := buildTestTime(t)
-
-		actualID, actualCreatedOn, err := client.CreateWebhookExecutionLog(mockDB, exampleInput)
+	t.Run("truncates bodies like RecordWebhookExecutionLog", func(t *testing.T) {
+		expectedID := uint64(1)
+		expectedExecutedOn := buildTestTime(t)
+		mock.ExpectQuery(formatQueryForSQLMock(webhookExecutionLogRecordQuery)).
+			WithArgs(example.WebhookID, example.EventType, example.Attempt, example.StatusCode, example.Succeeded, `{"id`, "inte", example.DurationMs, example.ErrorMessage, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "executed_on"}).AddRow(expectedID, expectedExecutedOn))
+		actualID, actualExecutedOn, err := client.CreateWebhookExecutionLog(mockDB, example)
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedID, actualID, "expected and actual IDs don't match")
-		assert.Equal(t, expectedCreatedOn, actualCreatedOn, "expected creation time did not match actual creation time")
+		assert.Equal(t, expectedExecutedOn, actualExecutedOn, "expected execution time did not match actual execution time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
 
+	t.Run("tracks consecutive failures like RecordWebhookExecutionLog", func(t *testing.T) {
+		client.SetWebhookFailureConfig(WebhookFailureConfig{ConsecutiveFailureLimit: 3})
+		query := formatQueryForSQLMock(webhookExecutionLogRecordQuery)
+		assert.Contains(t, query, "consecutive_failures", "creating a log should update the webhook's failure counter")
+
+		mock.ExpectQuery(query).
+			WithArgs(example.WebhookID, example.EventType, example.Attempt, example.StatusCode, example.Succeeded, `{"id`, "inte", example.DurationMs, example.ErrorMessage, uint64(3)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "executed_on"}).AddRow(uint64(1), buildTestTime(t)))
+		_, _, err := client.CreateWebhookExecutionLog(mockDB, example)
+
+		assert.NoError(t, err)
 		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
 	})
 }
@@ -286,6 +513,12 @@ func setWebhookExecutionLogUpdateQueryExpectation(t *testing.T, mock sqlmock.Sql
 			toUpdate.StatusCode,
 			toUpdate.Succeeded,
 			toUpdate.ExecutedOn,
+			toUpdate.EventType,
+			toUpdate.Attempt,
+			toUpdate.RequestBody,
+			toUpdate.ResponseBody,
+			toUpdate.DurationMs,
+			toUpdate.ErrorMessage,
 			toUpdate.ID,
 		).
 		WillReturnRows(exampleRows).
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// WebhookFilterCondition is a single comparison against a field of an event
+// payload. A webhook's filter is a JSON array of conditions, all of which must
+// hold for the webhook to receive the event. Field may be a dotted path into
+// nested objects, e.g. "product.brand".
+type WebhookFilterCondition struct {
+	Field    string      `json:"field"`
+	Operator string      `json:"op"`
+	Value    interface{} `json:"value"`
+}
+
+var webhookFilterOperators = map[string]bool{
+	"eq":  true,
+	"ne":  true,
+	"lt":  true,
+	"lte": true,
+	"gt":  true,
+	"gte": true,
+}
+
+// ParseWebhookFilter decodes and validates a webhook filter. An empty filter
+// matches every event.
+func ParseWebhookFilter(raw []byte) ([]WebhookFilterCondition, error) {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" || trimmed == "null" {
+		return nil, nil
+	}
+
+	var conditions []WebhookFilterCondition
+	if err := json.Unmarshal(raw, &conditions); err != nil {
+		return nil, fmt.Errorf("invalid webhook filter: %v", err)
+	}
+	for i, c := range conditions {
+		if c.Field == "" {
+			return nil, fmt.Errorf("invalid webhook filter: condition %d has no field", i)
+		}
+		if !webhookFilterOperators[c.Operator] {
+			return nil, fmt.Errorf("invalid webhook filter: condition %d has unknown operator %q", i, c.Operator)
+		}
+		switch c.Value.(type) {
+		case string, float64:
+		case bool:
+			if c.Operator != "eq" && c.Operator != "ne" {
+				return nil, fmt.Errorf("invalid webhook filter: condition %d cannot order booleans", i)
+			}
+		default:
+			return nil, fmt.Errorf("invalid webhook filter: condition %d must compare against a string, number or boolean", i)
+		}
+	}
+	return conditions, nil
+}
+
+// ValidateWebhookFilter reports whether raw is a well formed webhook filter.
+func ValidateWebhookFilter(raw []byte) error {
+	_, err := ParseWebhookFilter(raw)
+	return err
+}
+
+func webhookFilterMatches(conditions []WebhookFilterCondition, payload map[string]interface{}) bool {
+	for _, c := range conditions {
+		actual, ok := lookupPayloadField(payload, c.Field)
+		if !ok || !compareFilterValues(actual, c.Operator, c.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+func lookupPayloadField(payload map[string]interface{}, field string) (interface{}, bool) {
+	var current interface{} = payload
+	for _, part := range strings.Split(field, ".") {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = obj[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func compareFilterValues(actual interface{}, operator string, expected interface{}) bool {
+	var cmp int
+	switch e := expected.(type) {
+	case float64:
+		a, ok := actual.(float64)
+		if !ok {
+			return false
+		}
+		switch {
+		case a < e:
+			cmp = -1
+		case a > e:
+			cmp = 1
+		}
+	case string:
+		a, ok := actual.(string)
+		if !ok {
+			return false
+		}
+		cmp = strings.Compare(a, e)
+	case bool:
+		a, ok := actual.(bool)
+		if !ok {
+			return false
+		}
+		if a != e {
+			cmp = 1
+		}
+	default:
+		return false
+	}
+
+	switch operator {
+	case "eq":
+		return cmp == 0
+	case "ne":
+		return cmp != 0
+	case "lt":
+		return cmp < 0
+	case "lte":
+		return cmp <= 0
+	case "gt":
+		return cmp > 0
+	case "gte":
+		return cmp >= 0
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWebhookFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with empty filter", func(*testing.T) {
+		for _, raw := range []string{"", "null", " "} {
+			actual, err := ParseWebhookFilter([]byte(raw))
+			assert.NoError(t, err)
+			assert.Nil(t, actual)
+		}
+	})
+
+	t.Run("normal usecase", func(*testing.T) {
+		expected := []WebhookFilterCondition{
+			{Field: "brand", Operator: "eq", Value: "Your Favorite Band"},
+			{Field: "quantity", Operator: "lt", Value: float64(10)},
+		}
+		actual, err := ParseWebhookFilter([]byte(`[{"field": "brand", "op": "eq", "value": "Your Favorite Band"}, {"field": "quantity", "op": "lt", "value": 10}]`))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("with invalid filters", func(*testing.T) {
+		examples := []string{
+			`{"field": "brand"}`,
+			`[{"op": "eq", "value": "x"}]`,
+			`[{"field": "brand", "op": "like", "value": "x"}]`,
+			`[{"field": "brand", "op": "eq", "value": ["x"]}]`,
+			`[{"field": "taxable", "op": "lt", "value": true}]`,
+		}
+		for _, raw := range examples {
+			err := ValidateWebhookFilter([]byte(raw))
+			assert.NotNil(t, err, "expected %s to be rejected", raw)
+		}
+	})
+}
+
+func TestWebhookFilterMatches(t *testing.T) {
+	t.Parallel()
+
+	var payload map[string]interface{}
+	err := json.Unmarshal([]byte(`{"brand": "Your Favorite Band", "quantity": 4, "taxable": true, "root": {"sku_prefix": "t-shirt"}}`), &payload)
+	assert.NoError(t, err)
+
+	examples := []struct {
+		filter   string
+		expected bool
+	}{
+		{`[]`, true},
+		{`[{"field": "brand", "op": "eq", "value": "Your Favorite Band"}]`, true},
+		{`[{"field": "brand", "op": "ne", "value": "Your Favorite Band"}]`, false},
+		{`[{"field": "quantity", "op": "lt", "value": 5}]`, true},
+		{`[{"field": "quantity", "op": "gte", "value": 5}]`, false},
+		{`[{"field": "taxable", "op": "eq", "value": true}]`, true},
+		{`[{"field": "root.sku_prefix", "op": "eq", "value": "t-shirt"}]`, true},
+		{`[{"field": "missing", "op": "ne", "value": "x"}]`, false},
+		{`[{"field": "quantity", "op": "eq", "value": "4"}]`, false},
+		{`[{"field": "brand", "op": "eq", "value": "Your Favorite Band"}, {"field": "quantity", "op": "gt", "value": 10}]`, false},
+	}
+	for _, e := range examples {
+		conditions, err := ParseWebhookFilter([]byte(e.filter))
+		assert.NoError(t, err)
+		assert.Equal(t, e.expected, webhookFilterMatches(conditions, payload), "unexpected result for filter %s", e.filter)
+	}
+}
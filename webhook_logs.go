@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// WebhookLogConfig controls how much of each webhook request and response
+// body is kept in webhook_execution_logs.
+type WebhookLogConfig struct {
+	MaxBodyBytes int
+}
+
+var DefaultWebhookLogConfig = WebhookLogConfig{
+	MaxBodyBytes: 64 * 1024,
+}
+
+// SetWebhookLogConfig replaces the webhook log settings. Zero fields keep
+// their default value.
+func (pg *postgres) SetWebhookLogConfig(cfg WebhookLogConfig) {
+	if cfg.MaxBodyBytes == 0 {
+		cfg.MaxBodyBytes = DefaultWebhookLogConfig.MaxBodyBytes
+	}
+	pg.webhookLogs = cfg
+}
+
+// WebhookFailureConfig controls when a webhook that keeps failing is
+// disabled automatically.
+type WebhookFailureConfig struct {
+	ConsecutiveFailureLimit uint64
+}
+
+var DefaultWebhookFailureConfig = WebhookFailureConfig{
+	ConsecutiveFailureLimit: 25,
+}
+
+// SetWebhookFailureConfig replaces the automatic webhook disabling threshold.
+// Zero fields keep their default value.
+func (pg *postgres) SetWebhookFailureConfig(cfg WebhookFailureConfig) {
+	if cfg.ConsecutiveFailureLimit == 0 {
+		cfg.ConsecutiveFailureLimit = DefaultWebhookFailureConfig.ConsecutiveFailureLimit
+	}
+	pg.webhookFailures = cfg
+}
+
+// prepareLoggedBody makes body safe to store in a text column and cuts it
+// down to at most max bytes. Postgres rejects invalid UTF-8 and NUL bytes in
+// text, so invalid sequences are replaced with U+FFFD and NUL bytes dropped
+// before cutting, and the cut never splits a multi-byte character.
+func prepareLoggedBody(body string, max int) string {
+	body = strings.ReplaceAll(strings.ToValidUTF8(body, "\uFFFD"), "\x00", "")
+	if max <= 0 || len(body) <= max {
+		return body
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return body[:cut]
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetWebhookLogConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("normal usecase", func(*testing.T) {
+		client := NewPostgres()
+		expected := WebhookLogConfig{MaxBodyBytes: 128}
+		client.SetWebhookLogConfig(expected)
+		assert.Equal(t, expected, client.webhookLogs)
+	})
+
+	t.Run("with zero values", func(*testing.T) {
+		client := NewPostgres()
+		client.SetWebhookLogConfig(WebhookLogConfig{})
+		assert.Equal(t, DefaultWebhookLogConfig, client.webhookLogs)
+	})
+}
+
+func TestSetWebhookFailureConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("normal usecase", func(*testing.T) {
+		client := NewPostgres()
+		expected := WebhookFailureConfig{ConsecutiveFailureLimit: 5}
+		client.SetWebhookFailureConfig(expected)
+		assert.Equal(t, expected, client.webhookFailures)
+	})
+
+	t.Run("with zero values", func(*testing.T) {
+		client := NewPostgres()
+		client.SetWebhookFailureConfig(WebhookFailureConfig{})
+		assert.Equal(t, DefaultWebhookFailureConfig, client.webhookFailures)
+	})
+}
+
+func TestPrepareLoggedBody(t *testing.T) {
+	t.Parallel()
+
+	t.Run("short body", func(*testing.T) {
+		assert.Equal(t, "hello", prepareLoggedBody("hello", 10))
+	})
+
+	t.Run("long body", func(*testing.T) {
+		assert.Equal(t, "hel", prepareLoggedBody("hello", 3))
+	})
+
+	t.Run("does not split multi-byte characters", func(*testing.T) {
+		assert.Equal(t, "caf", prepareLoggedBody("café", 4))
+	})
+
+	t.Run("with invalid UTF-8", func(*testing.T) {
+		actual := prepareLoggedBody("bad \xff\xfe body", 100)
+		assert.True(t, utf8.ValidString(actual))
+		assert.Equal(t, "bad \uFFFD body", actual)
+	})
+
+	t.Run("with NUL bytes", func(*testing.T) {
+		assert.Equal(t, "binary", prepareLoggedBody("bin\x00ary\x00", 100))
+	})
+
+	t.Run("truncates after cleaning up", func(*testing.T) {
+		assert.Equal(t, "ab", prepareLoggedBody("\x00\x00ab\xffcd", 3))
+	})
+}
@@ -0,0 +1,251 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/dairycart/dairycart/storage/database"
+	"github.com/dairycart/dairymodels/v1"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const webhookSecretRotationQuery = `
+    WITH expiring AS (
+        UPDATE webhook_secrets
+        SET
+            expires_on = NOW() + ($3::float8 * interval '1 second'),
+            updated_on = NOW()
+        WHERE webhook_id = $1
+        AND archived_on IS NULL
+        AND (expires_on IS NULL OR expires_on > NOW() + ($3::float8 * interval '1 second'))
+    )
+    INSERT INTO webhook_secrets
+        (
+            webhook_id, sealed_secret
+        )
+    VALUES
+        (
+            $1, $2
+        )
+    RETURNING
+        id, created_on;
+`
+
+func (pg *postgres) RotateWebhookSecret(db database.Querier, webhookID uint64, sealedSecret []byte, overlap time.Duration) (newID uint64, createdOn time.Time, err error) {
+	defer pg.observe("RotateWebhookSecret", time.Now(), &err, &newID, webhookID, sealedSecret, overlap)
+	err = db.QueryRow(webhookSecretRotationQuery, webhookID, sealedSecret, overlap.Seconds()).Scan(&newID, &createdOn)
+	return newID, createdOn, err
+}
+
+const webhookSecretActiveQueryByWebhookID = `
+    SELECT
+        id,
+        webhook_id,
+        sealed_secret,
+        expires_on,
+        created_on,
+        updated_on,
+        archived_on
+    FROM
+        webhook_secrets
+    WHERE
+        archived_on is null
+    AND
+        webhook_id = $1
+    AND
+        (expires_on IS NULL OR NOW() < expires_on)
+    ORDER BY
+        created_on DESC, id DESC
+`
+
+func (pg *postgres) GetActiveWebhookSecretsForWebhook(db database.Querier, webhookID uint64) (result []models.WebhookSecret, err error) {
+	defer pg.observe("GetActiveWebhookSecretsForWebhook", time.Now(), &err, &result, webhookID)
+	var list []models.WebhookSecret
+
+	rows, err := db.Query(webhookSecretActiveQueryByWebhookID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var w models.WebhookSecret
+		err := rows.Scan(
+			&w.ID,
+			&w.WebhookID,
+			&w.SealedSecret,
+			&w.ExpiresOn,
+			&w.CreatedOn,
+			&w.UpdatedOn,
+			&w.ArchivedOn,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, w)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+const webhookSecretExistenceQuery = `SELECT EXISTS(SELECT id FROM webhook_secrets WHERE id = $1 and archived_on IS NULL);`
+
+func (pg *postgres) WebhookSecretExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("WebhookSecretExists", time.Now(), &err, &result, id)
+	var exists string
+
+	err = db.QueryRow(webhookSecretExistenceQuery, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return exists == "true", err
+}
+
+const webhookSecretSelectionQuery = `
+    SELECT
+        id,
+        webhook_id,
+        expires_on,
+        created_on,
+        updated_on,
+        archived_on
+    FROM
+        webhook_secrets
+    WHERE
+        archived_on is null
+    AND
+        id = $1
+`
+
+func (pg *postgres) GetWebhookSecret(db database.Querier, id uint64) (result *models.WebhookSecret, err error) {
+	defer pg.observe("GetWebhookSecret", time.Now(), &err, &result, id)
+	w := &models.WebhookSecret{}
+
+	err = db.QueryRow(webhookSecretSelectionQuery, id).Scan(&w.ID, &w.WebhookID, &w.ExpiresOn, &w.CreatedOn, &w.UpdatedOn, &w.ArchivedOn)
+
+	return w, err
+}
+
+func buildWebhookSecretListRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	sqlBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	queryBuilder := sqlBuilder.
+		Select(
+			"id",
+			"webhook_id",
+			"expires_on",
+			"created_on",
+			"updated_on",
+			"archived_on",
+		).
+		From("webhook_secrets")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, true).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetWebhookSecretList(db database.Querier, qf *models.QueryFilter) (result []models.WebhookSecret, err error) {
+	defer pg.observe("GetWebhookSecretList", time.Now(), &err, &result, qf)
+	var list []models.WebhookSecret
+	query, args := buildWebhookSecretListRetrievalQuery(qf)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var w models.WebhookSecret
+		err := rows.Scan(
+			&w.ID,
+			&w.WebhookID,
+			&w.ExpiresOn,
+			&w.CreatedOn,
+			&w.UpdatedOn,
+			&w.ArchivedOn,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, w)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+func buildWebhookSecretCountRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("count(id)").
+		From("webhook_secrets")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, false).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetWebhookSecretCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetWebhookSecretCount", time.Now(), &err, nil, qf)
+	var count uint64
+	query, args := buildWebhookSecretCountRetrievalQuery(qf)
+	err = db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+const webhookSecretCreationQuery = `
+    INSERT INTO webhook_secrets
+        (
+            webhook_id, sealed_secret, expires_on
+        )
+    VALUES
+        (
+            $1, $2, $3
+        )
+    RETURNING
+        id, created_on;
+`
+
+func (pg *postgres) CreateWebhookSecret(db database.Querier, nu *models.WebhookSecret) (createdID uint64, createdOn time.Time, err error) {
+	defer pg.observe("CreateWebhookSecret", time.Now(), &err, &createdID, nu)
+	err = db.QueryRow(webhookSecretCreationQuery, &nu.WebhookID, &nu.SealedSecret, &nu.ExpiresOn).Scan(&createdID, &createdOn)
+	return createdID, createdOn, err
+}
+
+const webhookSecretUpdateQuery = `
+    UPDATE webhook_secrets
+    SET
+        webhook_id = $1,
+        sealed_secret = $2,
+        expires_on = $3,
+        updated_on = NOW()
+    WHERE id = $4
+    RETURNING updated_on;
+`
+
+func (pg *postgres) UpdateWebhookSecret(db database.Querier, updated *models.WebhookSecret) (result time.Time, err error) {
+	defer pg.observe("UpdateWebhookSecret", time.Now(), &err, &result, updated)
+	var t time.Time
+	err = db.QueryRow(webhookSecretUpdateQuery, &updated.WebhookID, &updated.SealedSecret, &updated.ExpiresOn, &updated.ID).Scan(&t)
+	return t, err
+}
+
+const webhookSecretDeletionQuery = `
+    UPDATE webhook_secrets
+    SET archived_on = NOW()
+    WHERE id = $1
+    RETURNING archived_on
+`
+
+func (pg *postgres) DeleteWebhookSecret(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteWebhookSecret", time.Now(), &err, &t, id)
+	err = db.QueryRow(webhookSecretDeletionQuery, id).Scan(&t)
+	return t, err
+}
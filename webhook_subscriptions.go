@@ -0,0 +1,242 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/dairycart/dairycart/storage/database"
+	"github.com/dairycart/dairymodels/v1"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+const webhookSubscriptionSetForWebhookQuery = `
+    WITH archived AS (
+        UPDATE webhook_subscriptions
+        SET archived_on = NOW()
+        WHERE webhook_id = $1
+        AND archived_on IS NULL
+        AND NOT (event_type = ANY($2::text[]))
+    )
+    INSERT INTO webhook_subscriptions
+        (
+            webhook_id, event_type
+        )
+    SELECT $1, unnest($2::text[])
+    ON CONFLICT (webhook_id, event_type) WHERE archived_on IS NULL
+    DO NOTHING
+`
+
+func (pg *postgres) SetWebhookSubscriptionsForWebhook(db database.Querier, webhookID uint64, eventTypes []string) (err error) {
+	defer pg.observe("SetWebhookSubscriptionsForWebhook", time.Now(), &err, nil, webhookID, eventTypes)
+	_, err = db.Exec(webhookSubscriptionSetForWebhookQuery, webhookID, pq.Array(eventTypes))
+	return err
+}
+
+const webhookSubscriptionActiveQueryByWebhookID = `
+    SELECT
+        id,
+        webhook_id,
+        event_type,
+        created_on,
+        updated_on,
+        archived_on
+    FROM
+        webhook_subscriptions
+    WHERE
+        archived_on is null
+    AND
+        webhook_id = $1
+    ORDER BY
+        event_type
+`
+
+func (pg *postgres) GetWebhookSubscriptionsForWebhook(db database.Querier, webhookID uint64) (result []models.WebhookSubscription, err error) {
+	defer pg.observe("GetWebhookSubscriptionsForWebhook", time.Now(), &err, &result, webhookID)
+	var list []models.WebhookSubscription
+
+	rows, err := db.Query(webhookSubscriptionActiveQueryByWebhookID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var w models.WebhookSubscription
+		err := rows.Scan(
+			&w.ID,
+			&w.WebhookID,
+			&w.EventType,
+			&w.CreatedOn,
+			&w.UpdatedOn,
+			&w.ArchivedOn,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, w)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+const webhookSubscriptionExistenceQuery = `SELECT EXISTS(SELECT id FROM webhook_subscriptions WHERE id = $1 and archived_on IS NULL);`
+
+func (pg *postgres) WebhookSubscriptionExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("WebhookSubscriptionExists", time.Now(), &err, &result, id)
+	var exists string
+
+	err = db.QueryRow(webhookSubscriptionExistenceQuery, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return exists == "true", err
+}
+
+const webhookSubscriptionSelectionQuery = `
+    SELECT
+        id,
+        webhook_id,
+        event_type,
+        created_on,
+        updated_on,
+        archived_on
+    FROM
+        webhook_subscriptions
+    WHERE
+        archived_on is null
+    AND
+        id = $1
+`
+
+func (pg *postgres) GetWebhookSubscription(db database.Querier, id uint64) (result *models.WebhookSubscription, err error) {
+	defer pg.observe("GetWebhookSubscription", time.Now(), &err, &result, id)
+	w := &models.WebhookSubscription{}
+
+	err = db.QueryRow(webhookSubscriptionSelectionQuery, id).Scan(&w.ID, &w.WebhookID, &w.EventType, &w.CreatedOn, &w.UpdatedOn, &w.ArchivedOn)
+
+	return w, err
+}
+
+func buildWebhookSubscriptionListRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	sqlBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	queryBuilder := sqlBuilder.
+		Select(
+			"id",
+			"webhook_id",
+			"event_type",
+			"created_on",
+			"updated_on",
+			"archived_on",
+		).
+		From("webhook_subscriptions")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, true).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetWebhookSubscriptionList(db database.Querier, qf *models.QueryFilter) (result []models.WebhookSubscription, err error) {
+	defer pg.observe("GetWebhookSubscriptionList", time.Now(), &err, &result, qf)
+	var list []models.WebhookSubscription
+	query, args := buildWebhookSubscriptionListRetrievalQuery(qf)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var w models.WebhookSubscription
+		err := rows.Scan(
+			&w.ID,
+			&w.WebhookID,
+			&w.EventType,
+			&w.CreatedOn,
+			&w.UpdatedOn,
+			&w.ArchivedOn,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, w)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+func buildWebhookSubscriptionCountRetrievalQuery(qf *models.QueryFilter) (string, []interface{}) {
+	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("count(id)").
+		From("webhook_subscriptions")
+
+	query, args, _ := applyQueryFilterToQueryBuilder(queryBuilder, qf, false).ToSql()
+	return query, args
+}
+
+func (pg *postgres) GetWebhookSubscriptionCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetWebhookSubscriptionCount", time.Now(), &err, nil, qf)
+	var count uint64
+	query, args := buildWebhookSubscriptionCountRetrievalQuery(qf)
+	err = db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+const webhookSubscriptionCreationQuery = `
+    INSERT INTO webhook_subscriptions
+        (
+            webhook_id, event_type
+        )
+    VALUES
+        (
+            $1, $2
+        )
+    RETURNING
+        id, created_on;
+`
+
+func (pg *postgres) CreateWebhookSubscription(db database.Querier, nu *models.WebhookSubscription) (createdID uint64, createdOn time.Time, err error) {
+	defer pg.observe("CreateWebhookSubscription", time.Now(), &err, &createdID, nu)
+	err = db.QueryRow(webhookSubscriptionCreationQuery, &nu.WebhookID, &nu.EventType).Scan(&createdID, &createdOn)
+	return createdID, createdOn, err
+}
+
+const webhookSubscriptionUpdateQuery = `
+    UPDATE webhook_subscriptions
+    SET
+        webhook_id = $1,
+        event_type = $2,
+        updated_on = NOW()
+    WHERE id = $3
+    RETURNING updated_on;
+`
+
+func (pg *postgres) UpdateWebhookSubscription(db database.Querier, updated *models.WebhookSubscription) (result time.Time, err error) {
+	defer pg.observe("UpdateWebhookSubscription", time.Now(), &err, &result, updated)
+	var t time.Time
+	err = db.QueryRow(webhookSubscriptionUpdateQuery, &updated.WebhookID, &updated.EventType, &updated.ID).Scan(&t)
+	return t, err
+}
+
+const webhookSubscriptionDeletionQuery = `
+    UPDATE webhook_subscriptions
+    SET archived_on = NOW()
+    WHERE id = $1
+    RETURNING archived_on
+`
+
+func (pg *postgres) DeleteWebhookSubscription(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteWebhookSubscription", time.Now(), &err, &t, id)
+	err = db.QueryRow(webhookSubscriptionDeletionQuery, id).Scan(&t)
+	return t, err
+}
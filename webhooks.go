@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/dairycart/dairycart/storage/database"
@@ -12,20 +13,33 @@ import (
 
 const webhookQueryByEventType = `
     SELECT
-        id,
-        url,
-        event_type,
-        content_type,
-        created_on,
-        updated_on,
-        archived_on
+        webhooks.id,
+        webhooks.url,
+        webhooks.content_type,
+        webhooks.created_on,
+        webhooks.updated_on,
+        webhooks.archived_on,
+        webhooks.consecutive_failures,
+        webhooks.disabled_on,
+        webhooks.disabled_reason,
+        webhooks.headers,
+        webhooks.filter
     FROM
         webhooks
+    JOIN
+        webhook_subscriptions ON webhook_subscriptions.webhook_id = webhooks.id
     WHERE
-        event_type = $1
+        webhook_subscriptions.archived_on IS NULL
+    AND
+        webhook_subscriptions.event_type = $1
+    AND
+        webhooks.archived_on IS NULL
+    AND
+        webhooks.disabled_on IS NULL
 `
 
-func (pg *postgres) GetWebhooksByEventType(db database.Querier, eventType string) ([]models.Webhook, error) {
+func (pg *postgres) GetWebhooksByEventType(db database.Querier, eventType string) (result []models.Webhook, err error) {
+	defer pg.observe("GetWebhooksByEventType", time.Now(), &err, &result, eventType)
 	var list []models.Webhook
 
 	rows, err := db.Query(webhookQueryByEventType, eventType)
@@ -38,11 +52,15 @@ func (pg *postgres) GetWebhooksByEventType(db database.Querier, eventType string
 		err := rows.Scan(
 			&w.ID,
 			&w.URL,
-			&w.EventType,
 			&w.ContentType,
 			&w.CreatedOn,
 			&w.UpdatedOn,
 			&w.ArchivedOn,
+			&w.ConsecutiveFailures,
+			&w.DisabledOn,
+			&w.DisabledReason,
+			&w.Headers,
+			&w.Filter,
 		)
 		if err != nil {
 			return nil, err
@@ -57,12 +75,57 @@ func (pg *postgres) GetWebhooksByEventType(db database.Querier, eventType string
 	return list, err
 }
 
+func (pg *postgres) GetWebhooksForEvent(db database.Querier, eventType string, payload []byte) (result []models.Webhook, err error) {
+	defer pg.observe("GetWebhooksForEvent", time.Now(), &err, &result, eventType, payload)
+	var fields map[string]interface{}
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, err
+	}
+
+	candidates, err := pg.GetWebhooksByEventType(db, eventType)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []models.Webhook
+	for _, w := range candidates {
+		conditions, err := ParseWebhookFilter(w.Filter)
+		if err != nil {
+			// filters are validated on write, so a broken one only skips its own webhook
+			continue
+		}
+		if webhookFilterMatches(conditions, fields) {
+			list = append(list, w)
+		}
+	}
+	return list, nil
+}
+
+const webhookEnableQuery = `
+    UPDATE webhooks
+    SET
+        consecutive_failures = 0,
+        disabled_on = NULL,
+        disabled_reason = '',
+        updated_on = NOW()
+    WHERE id = $1
+    AND archived_on IS NULL
+    RETURNING updated_on
+`
+
+func (pg *postgres) EnableWebhook(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("EnableWebhook", time.Now(), &err, &t, id)
+	err = db.QueryRow(webhookEnableQuery, id).Scan(&t)
+	return t, err
+}
+
 const webhookExistenceQuery = `SELECT EXISTS(SELECT id FROM webhooks WHERE id = $1 and archived_on IS NULL);`
 
-func (pg *postgres) WebhookExists(db database.Querier, id uint64) (bool, error) {
+func (pg *postgres) WebhookExists(db database.Querier, id uint64) (result bool, err error) {
+	defer pg.observe("WebhookExists", time.Now(), &err, &result, id)
 	var exists string
 
-	err := db.QueryRow(webhookExistenceQuery, id).Scan(&exists)
+	err = db.QueryRow(webhookExistenceQuery, id).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	} else if err != nil {
@@ -76,11 +139,15 @@ const webhookSelectionQuery = `
     SELECT
         id,
         url,
-        event_type,
         content_type,
         created_on,
         updated_on,
-        archived_on
+        archived_on,
+        consecutive_failures,
+        disabled_on,
+        disabled_reason,
+        headers,
+        filter
     FROM
         webhooks
     WHERE
@@ -89,10 +156,11 @@ const webhookSelectionQuery = `
         id = $1
 `
 
-func (pg *postgres) GetWebhook(db database.Querier, id uint64) (*models.Webhook, error) {
+func (pg *postgres) GetWebhook(db database.Querier, id uint64) (result *models.Webhook, err error) {
+	defer pg.observe("GetWebhook", time.Now(), &err, &result, id)
 	w := &models.Webhook{}
 
-	err := db.QueryRow(webhookSelectionQuery, id).Scan(&w.ID, &w.URL, &w.EventType, &w.ContentType, &w.CreatedOn, &w.UpdatedOn, &w.ArchivedOn)
+	err = db.QueryRow(webhookSelectionQuery, id).Scan(&w.ID, &w.URL, &w.ContentType, &w.CreatedOn, &w.UpdatedOn, &w.ArchivedOn, &w.ConsecutiveFailures, &w.DisabledOn, &w.DisabledReason, &w.Headers, &w.Filter)
 
 	return w, err
 }
@@ -103,11 +171,15 @@ func buildWebhookListRetrievalQuery(qf *models.QueryFilter) (string, []interface
 		Select(
 			"id",
 			"url",
-			"event_type",
 			"content_type",
 			"created_on",
 			"updated_on",
 			"archived_on",
+			"consecutive_failures",
+			"disabled_on",
+			"disabled_reason",
+			"headers",
+			"filter",
 		).
 		From("webhooks")
 
@@ -115,7 +187,8 @@ func buildWebhookListRetrievalQuery(qf *models.QueryFilter) (string, []interface
 	return query, args
 }
 
-func (pg *postgres) GetWebhookList(db database.Querier, qf *models.QueryFilter) ([]models.Webhook, error) {
+func (pg *postgres) GetWebhookList(db database.Querier, qf *models.QueryFilter) (result []models.Webhook, err error) {
+	defer pg.observe("GetWebhookList", time.Now(), &err, &result, qf)
 	var list []models.Webhook
 	query, args := buildWebhookListRetrievalQuery(qf)
 
@@ -129,11 +202,15 @@ func (pg *postgres) GetWebhookList(db database.Querier, qf *models.QueryFilter)
 		err := rows.Scan(
 			&w.ID,
 			&w.URL,
-			&w.EventType,
 			&w.ContentType,
 			&w.CreatedOn,
 			&w.UpdatedOn,
 			&w.ArchivedOn,
+			&w.ConsecutiveFailures,
+			&w.DisabledOn,
+			&w.DisabledReason,
+			&w.Headers,
+			&w.Filter,
 		)
 		if err != nil {
 			return nil, err
@@ -157,28 +234,33 @@ func buildWebhookCountRetrievalQuery(qf *models.QueryFilter) (string, []interfac
 	return query, args
 }
 
-func (pg *postgres) GetWebhookCount(db database.Querier, qf *models.QueryFilter) (uint64, error) {
+func (pg *postgres) GetWebhookCount(db database.Querier, qf *models.QueryFilter) (result uint64, err error) {
+	defer pg.observe("GetWebhookCount", time.Now(), &err, nil, qf)
 	var count uint64
 	query, args := buildWebhookCountRetrievalQuery(qf)
-	err := db.QueryRow(query, args...).Scan(&count)
+	err = db.QueryRow(query, args...).Scan(&count)
 	return count, err
 }
 
 const webhookCreationQuery = `
     INSERT INTO webhooks
         (
-            url, event_type, content_type
+            url, content_type, consecutive_failures, disabled_on, disabled_reason, headers, filter
         )
     VALUES
         (
-            $1, $2, $3
+            $1, $2, $3, $4, $5, COALESCE($6, '{}'::jsonb), COALESCE(NULLIF($7::jsonb, 'null'::jsonb), '[]'::jsonb)
         )
     RETURNING
         id, created_on;
 `
 
 func (pg *postgres) CreateWebhook(db database.Querier, nu *models.Webhook) (createdID uint64, createdOn time.Time, err error) {
-	err = db.QueryRow(webhookCreationQuery, &nu.URL, &nu.EventType, &nu.ContentType).Scan(&createdID, &createdOn)
+	defer pg.observe("CreateWebhook", time.Now(), &err, &createdID, nu)
+	if err = ValidateWebhookFilter(nu.Filter); err != nil {
+		return 0, time.Time{}, err
+	}
+	err = db.QueryRow(webhookCreationQuery, &nu.URL, &nu.ContentType, &nu.ConsecutiveFailures, &nu.DisabledOn, &nu.DisabledReason, &nu.Headers, &nu.Filter).Scan(&createdID, &createdOn)
 	return createdID, createdOn, err
 }
 
@@ -186,16 +268,21 @@ const webhookUpdateQuery = `
     UPDATE webhooks
     SET
         url = $1,
-        event_type = $2,
-        content_type = $3,
+        content_type = $2,
+        headers = COALESCE($3, '{}'::jsonb),
+        filter = COALESCE(NULLIF($4::jsonb, 'null'::jsonb), '[]'::jsonb),
         updated_on = NOW()
-    WHERE id = $4
+    WHERE id = $5
     RETURNING updated_on;
 `
 
-func (pg *postgres) UpdateWebhook(db database.Querier, updated *models.Webhook) (time.Time, error) {
+func (pg *postgres) UpdateWebhook(db database.Querier, updated *models.Webhook) (result time.Time, err error) {
+	defer pg.observe("UpdateWebhook", time.Now(), &err, &result, updated)
+	if err := ValidateWebhookFilter(updated.Filter); err != nil {
+		return time.Time{}, err
+	}
 	var t time.Time
-	err := db.QueryRow(webhookUpdateQuery, &updated.URL, &updated.EventType, &updated.ContentType, &updated.ID).Scan(&t)
+	err = db.QueryRow(webhookUpdateQuery, &updated.URL, &updated.ContentType, &updated.Headers, &updated.Filter, &updated.ID).Scan(&t)
 	return t, err
 }
 
@@ -207,6 +294,7 @@ const webhookDeletionQuery = `
 `
 
 func (pg *postgres) DeleteWebhook(db database.Querier, id uint64) (t time.Time, err error) {
+	defer pg.observe("DeleteWebhook", time.Now(), &err, &t, id)
 	err = db.QueryRow(webhookDeletionQuery, id).Scan(&t)
 	return t, err
 }
@@ -19,35 +19,51 @@ func setWebhookReadQueryExpectationByEventType(t *testing.T, mock sqlmock.Sqlmoc
 	exampleRows := sqlmock.NewRows([]string{
 		"id",
 		"url",
-		"event_type",
 		"content_type",
 		"created_on",
 		"updated_on",
 		"archived_on",
+		"consecutive_failures",
+		"disabled_on",
+		"disabled_reason",
+		"headers",
+		"filter",
 	}).AddRow(
 		example.ID,
 		example.URL,
-		example.EventType,
 		example.ContentType,
 		example.CreatedOn,
 		example.UpdatedOn,
 		example.ArchivedOn,
+		example.ConsecutiveFailures,
+		example.DisabledOn,
+		example.DisabledReason,
+		example.Headers,
+		example.Filter,
 	).AddRow(
 		example.ID,
 		example.URL,
-		example.EventType,
 		example.ContentType,
 		example.CreatedOn,
 		example.UpdatedOn,
 		example.ArchivedOn,
+		example.ConsecutiveFailures,
+		example.DisabledOn,
+		example.DisabledReason,
+		example.Headers,
+		example.Filter,
 	).AddRow(
 		example.ID,
 		example.URL,
-		example.EventType,
 		example.ContentType,
 		example.CreatedOn,
 		example.UpdatedOn,
 		example.ArchivedOn,
+		example.ConsecutiveFailures,
+		example.DisabledOn,
+		example.DisabledReason,
+		example.Headers,
+		example.Filter,
 	).RowError(1, rowErr)
 
 	mock.ExpectQuery(formatQueryForSQLMock(webhookQueryByEventType)).
@@ -104,6 +120,156 @@ func TestGetWebhooksByEventType(t *testing.T) {
 		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
 	})
 }
+func TestGetWebhooksForEvent(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+
+	exampleEventType := "product_updated"
+	example := &models.Webhook{Filter: []byte(`[{"field": "brand", "op": "eq", "value": "Your Favorite Band"}]`)}
+
+	t.Run("with matching payload", func(t *testing.T) {
+		setWebhookReadQueryExpectationByEventType(t, mock, example, nil, nil)
+		actual, err := client.GetWebhooksForEvent(mockDB, exampleEventType, []byte(`{"brand": "Your Favorite Band"}`))
+
+		assert.NoError(t, err)
+		assert.Len(t, actual, 3, "every webhook with a matching filter should be returned")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with non-matching payload", func(t *testing.T) {
+		setWebhookReadQueryExpectationByEventType(t, mock, example, nil, nil)
+		actual, err := client.GetWebhooksForEvent(mockDB, exampleEventType, []byte(`{"brand": "Some Other Band"}`))
+
+		assert.NoError(t, err)
+		assert.Empty(t, actual, "webhooks whose filters don't match should not be returned")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with invalid payload", func(t *testing.T) {
+		actual, err := client.GetWebhooksForEvent(mockDB, exampleEventType, []byte(`not json`))
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with error executing query", func(t *testing.T) {
+		setWebhookReadQueryExpectationByEventType(t, mock, example, nil, errors.New("pineapple on pizza"))
+		actual, err := client.GetWebhooksForEvent(mockDB, exampleEventType, []byte(`{}`))
+
+		assert.NotNil(t, err)
+		assert.Nil(t, actual)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
+
+func TestWebhookFilterValidation(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+	example := &models.Webhook{ID: 1, Filter: []byte(`[{"field": "brand", "op": "like", "value": "x"}]`)}
+
+	t.Run("on creation", func(t *testing.T) {
+		_, _, err := client.CreateWebhook(mockDB, example)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "no query should run for an invalid filter")
+	})
+
+	t.Run("on update", func(t *testing.T) {
+		_, err := client.UpdateWebhook(mockDB, example)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "no query should run for an invalid filter")
+	})
+}
+
+func TestWebhookWritesDefaultUnsetJSONColumns(t *testing.T) {
+	t.Parallel()
+
+	for name, query := range map[string]string{"creation": webhookCreationQuery, "update": webhookUpdateQuery} {
+		assert.Regexp(t, `COALESCE\(\$\d+, '\{\}'::jsonb\)`, query, "%s should store unset headers as an empty object", name)
+		assert.Regexp(t, `COALESCE\(NULLIF\(\$\d+::jsonb, 'null'::jsonb\), '\[\]'::jsonb\)`, query, "%s should store an unset or null filter as an empty array", name)
+	}
+}
+
+func TestCreateWebhookWithZeroValueFilter(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+	example := &models.Webhook{URL: "https://dairycart.com/webhooks"}
+
+	setWebhookCreationQueryExpectation(t, mock, example, nil)
+	actualID, _, err := client.CreateWebhook(mockDB, example)
+
+	assert.NoError(t, err, "a webhook without a filter should be valid")
+	assert.Equal(t, uint64(1), actualID)
+	assert.Nil(t, example.Filter, "creating a webhook should not modify the provided filter")
+	assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+}
+
+func TestWebhookUpdateQueryDoesNotWriteFailureTracking(t *testing.T) {
+	t.Parallel()
+
+	for _, col := range []string{"consecutive_failures", "disabled_on", "disabled_reason"} {
+		assert.NotRegexp(t, `\b`+col+` =`, webhookUpdateQuery, "general webhook updates should not write %s", col)
+	}
+}
+
+func TestCreateWebhookWithNullFilter(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+	example := &models.Webhook{URL: "https://dairycart.com/webhooks", Filter: []byte("null")}
+
+	setWebhookCreationQueryExpectation(t, mock, example, nil)
+	actualID, _, err := client.CreateWebhook(mockDB, example)
+
+	assert.NoError(t, err, "a null filter should be stored as an empty one")
+	assert.Equal(t, uint64(1), actualID)
+	assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+}
+
+func TestEnableWebhook(t *testing.T) {
+	t.Parallel()
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	client := NewPostgres()
+	exampleID := uint64(1)
+	query := formatQueryForSQLMock(webhookEnableQuery)
+
+	t.Run("optimal behavior", func(t *testing.T) {
+		expected := buildTestTime(t)
+		mock.ExpectQuery(query).
+			WithArgs(exampleID).
+			WillReturnRows(sqlmock.NewRows([]string{"updated_on"}).AddRow(expected))
+		actual, err := client.EnableWebhook(mockDB, exampleID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "expected update time did not match actual update time")
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+
+	t.Run("with nonexistent webhook", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(exampleID).
+			WillReturnError(sql.ErrNoRows)
+		_, err := client.EnableWebhook(mockDB, exampleID)
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.Nil(t, mock.ExpectationsWereMet(), "not all database expectations were met")
+	})
+}
 
 func setWebhookExistenceQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint64, shouldExist bool, err error) {
 	t.Helper()
@@ -158,19 +324,27 @@ func setWebhookReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, id uint6
 	exampleRows := sqlmock.NewRows([]string{
 		"id",
 		"url",
-		"event_type",
 		"content_type",
 		"created_on",
 		"updated_on",
 		"archived_on",
+		"consecutive_failures",
+		"disabled_on",
+		"disabled_reason",
+		"headers",
+		"filter",
 	}).AddRow(
 		toReturn.ID,
 		toReturn.URL,
-		toReturn.EventType,
 		toReturn.ContentType,
 		toReturn.CreatedOn,
 		toReturn.UpdatedOn,
 		toReturn.ArchivedOn,
+		toReturn.ConsecutiveFailures,
+		toReturn.DisabledOn,
+		toReturn.DisabledReason,
+		toReturn.Headers,
+		toReturn.Filter,
 	)
 	mock.ExpectQuery(query).WithArgs(id).WillReturnRows(exampleRows).WillReturnError(err)
 }
@@ -198,35 +372,51 @@ func setWebhookListReadQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, qf *
 	exampleRows := sqlmock.NewRows([]string{
 		"id",
 		"url",
-		"event_type",
 		"content_type",
 		"created_on",
 		"updated_on",
 		"archived_on",
+		"consecutive_failures",
+		"disabled_on",
+		"disabled_reason",
+		"headers",
+		"filter",
 	}).AddRow(
 		example.ID,
 		example.URL,
-		example.EventType,
 		example.ContentType,
 		example.CreatedOn,
 		example.UpdatedOn,
 		example.ArchivedOn,
+		example.ConsecutiveFailures,
+		example.DisabledOn,
+		example.DisabledReason,
+		example.Headers,
+		example.Filter,
 	).AddRow(
 		example.ID,
 		example.URL,
-		example.EventType,
 		example.ContentType,
 		example.CreatedOn,
 		example.UpdatedOn,
 		example.ArchivedOn,
+		example.ConsecutiveFailures,
+		example.DisabledOn,
+		example.DisabledReason,
+		example.Headers,
+		example.Filter,
 	).AddRow(
 		example.ID,
 		example.URL,
-		example.EventType,
 		example.ContentType,
 		example.CreatedOn,
 		example.UpdatedOn,
 		example.ArchivedOn,
+		example.ConsecutiveFailures,
+		example.DisabledOn,
+		example.DisabledReason,
+		example.Headers,
+		example.Filter,
 	).RowError(1, rowErr)
 
 	query, _ := buildWebhookListRetrievalQuery(qf)
@@ -347,8 +537,12 @@ func setWebhookCreationQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toCr
 	mock.ExpectQuery(query).
 		WithArgs(
 			toCreate.URL,
-			toCreate.EventType,
 			toCreate.ContentType,
+			toCreate.ConsecutiveFailures,
+			toCreate.DisabledOn,
+			toCreate.DisabledReason,
+			toCreate.Headers,
+			toCreate.Filter,
 		).
 		WillReturnRows(exampleRows).
 		WillReturnError(err)
@@ -384,8 +578,9 @@ func setWebhookUpdateQueryExpectation(t *testing.T, mock sqlmock.Sqlmock, toUpda
 	mock.ExpectQuery(query).
 		WithArgs(
 			toUpdate.URL,
-			toUpdate.EventType,
 			toUpdate.ContentType,
+			toUpdate.Headers,
+			toUpdate.Filter,
 			toUpdate.ID,
 		).
 		WillReturnRows(exampleRows).